@@ -0,0 +1,237 @@
+// cmd/importer/changes.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// changeBatchSize - сколько событий копить перед записью в change_events
+const changeBatchSize = 500
+
+// derivedColumns - колонки, которые вычисляются из других (BeforeSave) и не попадают в журнал
+var derivedColumns = map[string]bool{"name_folded": true}
+
+// changeTracker сравнивает строку CSV с текущей записью в БД (по ConflictTarget)
+// и пишет в change_events insert или по событию update на каждое изменившееся поле.
+type changeTracker struct {
+	tx      *gorm.DB
+	runID   uint
+	cfg     Config
+	schema  *schema.Schema
+	enabled bool
+
+	pending           []models.ChangeEvent
+	recorded          int
+	statementRegcodes map[uint]*string // statement_id -> regcode (для строк отчетов)
+}
+
+// newChangeTracker создает трекер для файла. Если таблица пуста (первая загрузка),
+// изменения не записываются: иначе журнал состоял бы из вставки всего реестра.
+func newChangeTracker(tx *gorm.DB, runID uint, cfg Config, modelSchema *schema.Schema) (*changeTracker, error) {
+	var count int64
+	if err := tx.Table(modelSchema.Table).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	return &changeTracker{
+		tx:                tx,
+		runID:             runID,
+		cfg:               cfg,
+		schema:            modelSchema,
+		enabled:           runID != 0 && count > 0,
+		statementRegcodes: make(map[uint]*string),
+	}, nil
+}
+
+// existing загружает текущую версию записи по ключу ON CONFLICT (nil - записи нет)
+func (t *changeTracker) existing(ctx context.Context, record reflect.Value) (interface{}, error) {
+	if !t.enabled {
+		return nil, nil
+	}
+	conditions := make(map[string]interface{}, len(t.cfg.ConflictTarget))
+	for _, column := range t.cfg.ConflictTarget {
+		field := t.schema.LookUpField(column.Name)
+		if field == nil {
+			return nil, fmt.Errorf("conflict column %s not found in model", column.Name)
+		}
+		value, zero := field.ValueOf(ctx, record)
+		if zero {
+			return nil, nil // Ключ не задан - будет вставка с автоинкрементом
+		}
+		conditions[column.Name] = value
+	}
+
+	current := reflect.New(record.Type().Elem()).Interface()
+	result := t.tx.Where(conditions).Limit(1).Find(current)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return current, nil
+}
+
+// record сравнивает сохраненную запись с прежней версией (previous = nil - вставка)
+func (t *changeTracker) record(ctx context.Context, record reflect.Value, previous interface{}) error {
+	if !t.enabled {
+		return nil
+	}
+	entityID := t.entityID(ctx, record)
+	regcode, err := t.regcode(ctx, record)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+
+	if previous == nil {
+		t.pending = append(t.pending, models.ChangeEvent{
+			ImportRunID: t.runID, Entity: t.schema.Table, EntityID: entityID, Regcode: regcode,
+			Operation: models.ChangeOperationInsert, CreatedAt: now,
+		})
+		return t.flushIfFull()
+	}
+
+	previousValue := reflect.ValueOf(previous)
+	for _, column := range t.cfg.UpdateColumns {
+		field := t.schema.LookUpField(column)
+		if field == nil || derivedColumns[column] {
+			continue
+		}
+		oldRaw, _ := field.ValueOf(ctx, previousValue)
+		newRaw, _ := field.ValueOf(ctx, record)
+		oldValue, newValue := formatChangeValue(oldRaw), formatChangeValue(newRaw)
+		if equalStringPtr(oldValue, newValue) {
+			continue
+		}
+		name := column
+		t.pending = append(t.pending, models.ChangeEvent{
+			ImportRunID: t.runID, Entity: t.schema.Table, EntityID: entityID, Regcode: regcode,
+			Operation: models.ChangeOperationUpdate, Field: &name, OldValue: oldValue, NewValue: newValue, CreatedAt: now,
+		})
+	}
+	return t.flushIfFull()
+}
+
+// recordDelete пишет событие delete с прежней версией записи в old_value
+func (t *changeTracker) recordDelete(ctx context.Context, record reflect.Value) error {
+	if !t.enabled {
+		return nil
+	}
+	regcode, err := t.regcode(ctx, record)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(record.Interface())
+	if err != nil {
+		return err
+	}
+	oldValue := string(data)
+	t.pending = append(t.pending, models.ChangeEvent{
+		ImportRunID: t.runID, Entity: t.schema.Table, EntityID: t.entityID(ctx, record), Regcode: regcode,
+		Operation: models.ChangeOperationDelete, OldValue: &oldValue, CreatedAt: time.Now().UTC(),
+	})
+	return t.flushIfFull()
+}
+
+func (t *changeTracker) flushIfFull() error {
+	if len(t.pending) < changeBatchSize {
+		return nil
+	}
+	return t.flush()
+}
+
+// flush записывает накопленные события (в той же транзакции, что и upsert)
+func (t *changeTracker) flush() error {
+	if len(t.pending) == 0 {
+		return nil
+	}
+	if err := t.tx.CreateInBatches(t.pending, changeBatchSize).Error; err != nil {
+		return err
+	}
+	t.recorded += len(t.pending)
+	t.pending = t.pending[:0]
+	return nil
+}
+
+// entityID - значения колонок ключа через "|" ("40003000000|2019" для financial_statements)
+func (t *changeTracker) entityID(ctx context.Context, record reflect.Value) string {
+	parts := make([]string, 0, len(t.cfg.ConflictTarget))
+	for _, column := range t.cfg.ConflictTarget {
+		value, _ := t.schema.LookUpField(column.Name).ValueOf(ctx, record)
+		parts = append(parts, utils.StringValue(formatChangeValue(value)))
+	}
+	return strings.Join(parts, "|")
+}
+
+// regcode - компания записи: из CompanyColumn или, для строк отчетов, через financial_statements
+func (t *changeTracker) regcode(ctx context.Context, record reflect.Value) (*string, error) {
+	if t.cfg.CompanyColumn != "" {
+		value, _ := t.schema.LookUpField(t.cfg.CompanyColumn).ValueOf(ctx, record)
+		return formatChangeValue(value), nil
+	}
+	field := t.schema.LookUpField("statement_id")
+	if field == nil {
+		return nil, nil
+	}
+	value, _ := field.ValueOf(ctx, record)
+	statementID, ok := value.(*uint)
+	if !ok || statementID == nil {
+		return nil, nil
+	}
+	if regcode, cached := t.statementRegcodes[*statementID]; cached {
+		return regcode, nil
+	}
+	var statement models.FinancialStatement
+	result := t.tx.Select("legal_entity_registration_number").Where("id = ?", *statementID).Limit(1).Find(&statement)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	t.statementRegcodes[*statementID] = statement.LegalEntityRegistrationNumber
+	return statement.LegalEntityRegistrationNumber, nil
+}
+
+// formatChangeValue приводит значение поля к строке для журнала (nil-указатель -> nil)
+func formatChangeValue(value interface{}) *string {
+	reflected := reflect.ValueOf(value)
+	if !reflected.IsValid() {
+		return nil
+	}
+	if reflected.Kind() == reflect.Ptr {
+		if reflected.IsNil() {
+			return nil
+		}
+		reflected = reflected.Elem()
+	}
+
+	var formatted string
+	switch typed := reflected.Interface().(type) {
+	case time.Time:
+		formatted = typed.UTC().Format(time.RFC3339)
+	case float64:
+		formatted = strconv.FormatFloat(typed, 'f', -1, 64)
+	case float32:
+		formatted = strconv.FormatFloat(float64(typed), 'f', -1, 32)
+	default:
+		formatted = fmt.Sprint(typed)
+	}
+	return &formatted
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,91 @@
+// cmd/importer/duplicates.go
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+)
+
+// newCSVReader - настройки чтения, общие для всех проходов по файлу
+func newCSVReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = ';' // <--- УСТАНОВИТЕ ПРАВИЛЬНЫЙ РАЗДЕЛИТЕЛЬ (',' или ';')
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	return reader
+}
+
+// keyColumnIndexes - индексы колонок ключа (ConflictTarget) в CSV, -1 - колонки нет
+func keyColumnIndexes(cfg Config, headerMap map[string]int) []int {
+	columns := make([]int, 0, len(cfg.ConflictTarget))
+	for _, column := range cfg.ConflictTarget {
+		index, ok := headerMap[strings.ToLower(column.Name)]
+		if !ok {
+			index = -1
+		}
+		columns = append(columns, index)
+	}
+	return columns
+}
+
+// rawKey - ключ строки по сырым значениям CSV через "|". complete = false, если какая-то
+// часть ключа пуста (например, нет id и запись получит автоинкремент).
+func rawKey(columns []int, row []string) (key string, complete bool) {
+	parts := make([]string, len(columns))
+	complete = true
+	for i, index := range columns {
+		if index >= 0 && index < len(row) {
+			parts[i] = strings.TrimSpace(row[index])
+		}
+		if parts[i] == "" {
+			complete = false
+		}
+	}
+	return strings.Join(parts, "|"), complete
+}
+
+// findDuplicateRows предварительно читает файл и возвращает номера строк (1 - первая строка
+// данных, как recordsProcessed), ключ которых повторяется ниже в том же файле. Такие строки
+// пропускаются: каждую из них журнал изменений сравнивал бы с БД отдельно, и повтор ключа
+// давал бы ложные update (запись переключалась бы между версиями при каждом запуске).
+// Побеждает последняя строка - итог в таблице тот же, что при последовательном upsert.
+func findDuplicateRows(filePath string, columns []int) (map[int]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := newCSVReader(file)
+	if _, err := reader.Read(); err != nil { // Заголовок
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	lastRow := make(map[string]int)
+	duplicates := make(map[int]string)
+	rowNumber := 0
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			continue // Нечитаемые строки пропускает и основной проход
+		}
+		rowNumber++
+		key, complete := rawKey(columns, row)
+		if !complete {
+			continue
+		}
+		if previous, seen := lastRow[key]; seen {
+			duplicates[previous] = key
+		}
+		lastRow[key] = rowNumber
+	}
+	return duplicates, nil
+}
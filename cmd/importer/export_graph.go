@@ -0,0 +1,62 @@
+// cmd/importer/export_graph.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	dbConn "capital-view-api/db"
+	"capital-view-api/graphexport"
+)
+
+// runExportGraph - подкоманда "export-graph": выгружает сеть связей компании в файл или stdout.
+//
+//	go run ./cmd/importer export-graph -regcode 40003000000 -format gexf -depth 2 -out company.gexf
+func runExportGraph(args []string) {
+	flags := flag.NewFlagSet("export-graph", flag.ExitOnError)
+	regcode := flags.String("regcode", "", "Regcode of the company in the center of the graph (required)")
+	formatName := flags.String("format", "graphml", "Output format: "+strings.Join(graphexport.FormatNames(), ", "))
+	depth := flags.Int("depth", graphexport.DefaultDepth, fmt.Sprintf("Number of company hops (1-%d)", graphexport.MaxDepth))
+	outPath := flags.String("out", "", "Output file (default: stdout)")
+	flags.Parse(args)
+
+	if *regcode == "" {
+		log.Fatal("FATAL: -regcode is required")
+	}
+	format, ok := graphexport.Formats[strings.ToLower(*formatName)]
+	if !ok {
+		log.Fatalf("FATAL: Unknown format %q (supported: %s)", *formatName, strings.Join(graphexport.FormatNames(), ", "))
+	}
+	if *depth < 1 || *depth > graphexport.MaxDepth {
+		log.Fatalf("FATAL: -depth must be between 1 and %d", graphexport.MaxDepth)
+	}
+
+	if err := dbConn.ConnectDatabase(); err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	graph, err := graphexport.Build(dbConn.DB, *regcode, *depth)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to build graph for %s: %v", *regcode, err)
+	}
+	if graph.Truncated {
+		log.Printf("WARN: Graph was truncated at %d nodes", graphexport.MaxNodes)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("FATAL: Failed to create %s: %v", *outPath, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := format.Write(out, graph); err != nil {
+		log.Fatalf("FATAL: Failed to write graph: %v", err)
+	}
+	log.Printf("Exported graph of %s: %d nodes, %d edges (%s)", *regcode, len(graph.Nodes), len(graph.Edges), format.Name)
+}
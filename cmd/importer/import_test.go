@@ -0,0 +1,280 @@
+// cmd/importer/import_test.go
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dbConn "capital-view-api/db"
+	"capital-view-api/db/postgrestest"
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+// testModels - таблицы, которые нужны импортеру в тестах
+var testModels = []interface{}{
+	&models.Registers{},
+	&models.Member{},
+	&models.BeneficialOwner{},
+	&models.FinancialStatement{},
+	&models.IncomeStatement{},
+	&models.BalanceSheet{},
+	&models.CashFlowStatement{},
+	&models.Officer{},
+	&models.ImportRun{},
+	&models.ChangeEvent{},
+	&models.EntityVersion{},
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// connectTestDB подключается через dbConn.ConnectDatabase (с теми же настройками gorm, что у
+// импортера) и создает таблицы заново
+func connectTestDB(t *testing.T, driver, dsn string) *gorm.DB {
+	t.Helper()
+	t.Setenv("DB_DRIVER", driver)
+	t.Setenv("DB_DSN", dsn)
+	t.Setenv("DB_PATH", "")
+	if err := dbConn.ConnectDatabase(); err != nil {
+		t.Fatalf("ConnectDatabase: %v", err)
+	}
+	database := dbConn.DB
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := database.Migrator().DropTable(testModels...); err != nil {
+		t.Fatalf("DropTable: %v", err)
+	}
+	if err := database.AutoMigrate(testModels...); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	return database
+}
+
+// connectTestSQLite - временная база SQLite
+func connectTestSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	return connectTestDB(t, dbConn.DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+}
+
+// writeCSV пишет файл с разделителем ';' и возвращает путь
+func writeCSV(t *testing.T, dir, name string, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name+".csv")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// configByName - конфигурация импорта из importConfigs
+func configByName(t *testing.T, name string) Config {
+	t.Helper()
+	for _, cfg := range importConfigs() {
+		if cfg.Name == name {
+			return cfg
+		}
+	}
+	t.Fatalf("import config %s not found", name)
+	return Config{}
+}
+
+func TestUpsertSQLite(t *testing.T) {
+	testUpsert(t, connectTestSQLite(t))
+}
+
+// TestUpsertPostgres выполняет те же проверки на PostgreSQL из TEST_POSTGRES_DSN.
+// Внимание: таблицы импорта в этой базе удаляются и создаются заново.
+func TestUpsertPostgres(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	testUpsert(t, connectTestDB(t, dbConn.DriverPostgres, dsn))
+}
+
+// testUpsert проверяет ON CONFLICT upsert импортера, отсутствие внешних ключей и SyncIDSequence
+func testUpsert(t *testing.T, database *gorm.DB) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Внешних ключей по связям моделей быть не должно: выгрузки ссылаются на отсутствующие компании и отчеты
+	relations := []struct {
+		model    interface{}
+		relation string
+	}{
+		{&models.Registers{}, "BeneficialOwners"},
+		{&models.Registers{}, "FinancialStatements"},
+		{&models.Registers{}, "Officers"},
+		{&models.FinancialStatement{}, "IncomeStatement"},
+		{&models.FinancialStatement{}, "BalanceSheet"},
+		{&models.FinancialStatement{}, "CashFlowStatement"},
+	}
+	for _, r := range relations {
+		if database.Migrator().HasConstraint(r.model, r.relation) {
+			t.Errorf("foreign key for %T.%s must not be created", r.model, r.relation)
+		}
+	}
+
+	// Первая загрузка (журнал не ведется); бенефициар и строка баланса ссылаются на отсутствующие записи
+	members := configByName(t, "members")
+	path := writeCSV(t, dir, "members",
+		"id;at_legal_entity_registration_number;entity_type;name;number_of_shares",
+		"1;40000000001;NATURAL_PERSON;Jānis Bērziņš;10",
+		"2;40000000001;NATURAL_PERSON;Anna Kalniņa;20",
+	)
+	if err := processCSV(ctx, database, path, members, importOptions{}); err != nil {
+		t.Fatalf("initial members import: %v", err)
+	}
+	ownersPath := writeCSV(t, dir, "beneficial_owners",
+		"id;legal_entity_registration_number;forename;surname",
+		"1;49999999999;Pēteris;Ozols",
+	)
+	if err := processCSV(ctx, database, ownersPath, configByName(t, "beneficial_owners"), importOptions{}); err != nil {
+		t.Fatalf("beneficial owner of a missing company: %v", err)
+	}
+	balancePath := writeCSV(t, dir, "balance_sheets",
+		"id;statement_id;total_assets;equity",
+		"1;999;100;50",
+	)
+	if err := processCSV(ctx, database, balancePath, configByName(t, "balance_sheets"), importOptions{}); err != nil {
+		t.Fatalf("balance sheet of a missing statement: %v", err)
+	}
+
+	// Повторная загрузка: id 2 изменился, id 3 новый - upsert и журнал изменений
+	run := models.ImportRun{CSVDir: dir, StartedAt: time.Now().UTC()}
+	if err := database.Create(&run).Error; err != nil {
+		t.Fatal(err)
+	}
+	path = writeCSV(t, dir, "members",
+		"id;at_legal_entity_registration_number;entity_type;name;number_of_shares",
+		"1;40000000001;NATURAL_PERSON;Jānis Bērziņš;10",
+		"2;40000000001;NATURAL_PERSON;Anna Kalniņa;25",
+		"3;40000000002;NATURAL_PERSON;Ilze Liepa;5",
+	)
+	if err := processCSV(ctx, database, path, members, importOptions{RunID: run.ID}); err != nil {
+		t.Fatalf("second members import: %v", err)
+	}
+
+	var count int64
+	database.Model(&models.Member{}).Count(&count)
+	if count != 3 {
+		t.Fatalf("members after upsert = %d, want 3", count)
+	}
+	var updated models.Member
+	if err := database.First(&updated, 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	if updated.NumberOfShares == nil || *updated.NumberOfShares != 25 {
+		t.Errorf("member 2 number_of_shares = %v, want 25", updated.NumberOfShares)
+	}
+	if updated.NameFolded == nil || *updated.NameFolded != "anna kalnina" {
+		t.Errorf("member 2 name_folded = %v, want \"anna kalnina\"", updated.NameFolded)
+	}
+
+	var events []models.ChangeEvent
+	if err := database.Where("import_run_id = ?", run.ID).Order("id").Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("change events = %d, want 2 (update of member 2, insert of member 3): %+v", len(events), events)
+	}
+	if events[0].Operation != models.ChangeOperationUpdate || events[0].EntityID != "2" ||
+		events[0].Field == nil || *events[0].Field != "number_of_shares" {
+		t.Errorf("first event = %+v, want update of member 2 number_of_shares", events[0])
+	}
+	if events[1].Operation != models.ChangeOperationInsert || events[1].EntityID != "3" {
+		t.Errorf("second event = %+v, want insert of member 3", events[1])
+	}
+
+	// id из CSV вставлены явно: после SyncIDSequence вставка без id не должна получить занятый id
+	if err := dbConn.SyncIDSequence(database, "members"); err != nil {
+		t.Fatalf("SyncIDSequence: %v", err)
+	}
+	name := "Bez ID"
+	added := models.Member{Name: &name}
+	if err := database.Create(&added).Error; err != nil {
+		t.Fatalf("insert without id after import: %v", err)
+	}
+	if added.ID <= 3 {
+		t.Errorf("id of inserted member = %d, want > 3", added.ID)
+	}
+}
+
+// TestPostgresSQL проверяет SQL, который импортер отправляет в PostgreSQL, без сервера (postgrestest)
+func TestPostgresSQL(t *testing.T) {
+	database, recorder := postgrestest.Open(t)
+	if !dbConn.IsPostgres(database) {
+		t.Fatal("IsPostgres = false for the postgres dialector")
+	}
+
+	records := map[string]interface{}{
+		"registers":            &models.Registers{},
+		"financial_statements": &models.FinancialStatement{},
+		"income_statements":    &models.IncomeStatement{},
+		"balance_sheets":       &models.BalanceSheet{},
+		"cash_flow_statements": &models.CashFlowStatement{},
+		"members":              &models.Member{ID: 1},
+		"beneficial_owners":    &models.BeneficialOwner{ID: 1},
+		"officers":             &models.Officer{ID: 1},
+	}
+	for _, cfg := range importConfigs() {
+		recorder.Statements = nil
+		if err := database.Clauses(upsertClause(cfg)).Create(records[cfg.Name]).Error; err != nil {
+			t.Fatalf("%s: upsert: %v", cfg.Name, err)
+		}
+		if len(recorder.Statements) != 1 {
+			t.Fatalf("%s: upsert SQL = %q, want one statement", cfg.Name, recorder.Statements)
+		}
+		sql := recorder.Statements[0]
+		columns := make([]string, len(cfg.ConflictTarget))
+		for i, column := range cfg.ConflictTarget {
+			columns[i] = `"` + column.Name + `"`
+		}
+		wantConflict := "ON CONFLICT (" + strings.Join(columns, ",") + ") DO UPDATE SET "
+		if !strings.Contains(sql, wantConflict) {
+			t.Errorf("%s: upsert SQL %q does not contain %q", cfg.Name, sql, wantConflict)
+		}
+		for _, column := range cfg.UpdateColumns {
+			if assignment := `"` + column + `"="excluded"."` + column + `"`; !strings.Contains(sql, assignment) {
+				t.Errorf("%s: upsert SQL does not update %s", cfg.Name, column)
+			}
+		}
+		postgrestest.Check(t, recorder.Statements)
+	}
+
+	recorder.Statements = nil
+	if err := dbConn.SyncIDSequence(database, "members"); err != nil {
+		t.Fatalf("SyncIDSequence: %v", err)
+	}
+	want := "SELECT setval(pg_get_serial_sequence('members', 'id'), COALESCE((SELECT MAX(id) FROM members), 0) + 1, false)"
+	if len(recorder.Statements) != 1 || recorder.Statements[0] != want {
+		t.Errorf("SyncIDSequence SQL = %q, want %q", recorder.Statements, want)
+	}
+	postgrestest.Check(t, recorder.Statements)
+
+	// Версии: закрытие версий исчезнувших записей - подзапросы NOT IN с CAST
+	recorder.Statements = nil
+	if err := syncVersions(database, 1, time.Now().UTC()); err != nil {
+		t.Fatalf("syncVersions: %v", err)
+	}
+	for _, entity := range []string{"registers", "members", "beneficial_owners", "officers"} {
+		found := false
+		for _, statement := range recorder.Statements {
+			found = found || strings.Contains(statement, "entity = '"+entity+"' AND valid_to IS NULL AND entity_id NOT IN")
+		}
+		if !found {
+			t.Errorf("no SQL closing removed %s versions:\n%s", entity, strings.Join(recorder.Statements, "\n"))
+		}
+	}
+	postgrestest.Check(t, recorder.Statements)
+}
@@ -3,7 +3,6 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"flag"
 	"fmt"
 	"io"
@@ -13,10 +12,13 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	// Используем алиас для пакета db вашего проекта
 	dbConn "capital-view-api/db" // <--- Проверьте правильность пути
 	"capital-view-api/models"    // <--- Проверьте правильность пути
+	"capital-view-api/risk"
+	"capital-view-api/utils"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -25,15 +27,52 @@ import (
 
 // Config для обработки CSV
 type Config struct {
+	Name           string          // Имя конфигурации (таблица) для логов
 	FileName       string          // Имя CSV файла (без расширения)
 	Model          interface{}     // Указатель на пустую структуру модели (например, &models.Registers{})
 	ConflictTarget []clause.Column // Колонки для ON CONFLICT
 	UpdateColumns  []string        // Колонки для UPDATE в ON CONFLICT
+	CompanyColumn  string          // Колонка с regcode компании для журнала изменений ("" - через statement_id)
+	Snapshot       bool            // В полном режиме (-full) удалять записи, которых нет в файле
+	PerCompany     bool            // Файл содержит все записи каждой своей компании: записи этих компаний, которых нет в файле, удаляются при любом импорте
 }
 
 func main() {
+	// Подкоманды: export-graph выгружает сеть связей компании (см. export_graph.go)
+	if len(os.Args) > 1 && os.Args[1] == "export-graph" {
+		runExportGraph(os.Args[2:])
+		return
+	}
+	// deliver-webhooks - повторная отправка уведомлений, которым пора (см. webhooks.go)
+	if len(os.Args) > 1 && os.Args[1] == "deliver-webhooks" {
+		runDeliverWebhooks()
+		return
+	}
+	// load-screening-lists - загрузка списков санкций/PEP из каталога и скрининг (см. screening.go)
+	if len(os.Args) > 1 && os.Args[1] == "load-screening-lists" {
+		runLoadScreeningLists(os.Args[2:])
+		return
+	}
+	// resolve-persons - только сопоставление лиц, без импорта CSV
+	if len(os.Args) > 1 && os.Args[1] == "resolve-persons" {
+		if err := dbConn.ConnectDatabase(); err != nil {
+			log.Fatalf("FATAL: Failed to connect to database: %v", err)
+		}
+		if err := dbConn.DB.AutoMigrate(&models.Person{}, &models.Member{}, &models.BeneficialOwner{}, &models.Officer{}); err != nil {
+			log.Fatalf("FATAL: AutoMigrate failed: %v", err)
+		}
+		if err := resolvePersons(dbConn.DB); err != nil {
+			log.Fatalf("FATAL: Person resolution failed: %v", err)
+		}
+		return
+	}
+
 	// --- Настройка ---
 	csvDir := flag.String("csvdir", "./csv_real", "Directory containing CSV files")
+	fullSnapshot := flag.Bool("full", false, "CSV files are a full snapshot: delete registers, members, beneficial owners and officers missing from them")
+	maxDeletePercent := flag.Float64("max-delete-percent", 10, "With -full, abort a file if more than this percent of its table would be deleted")
+	riskRulesPath := flag.String("risk-rules", risk.RulesPath(), "JSON file with risk rules (RISK_RULES_PATH overrides the default)")
+	screeningDir := flag.String("screening-dir", "", "Directory with sanctions/PEP list files to reload before screening (default: screen against the lists already loaded)")
 	flag.Parse()
 	log.Printf("Starting CSV import from directory: %s", *csvDir)
 
@@ -55,16 +94,133 @@ func main() {
 		&models.IncomeStatement{},
 		&models.BalanceSheet{},
 		&models.CashFlowStatement{},
-		// Добавьте сюда &models.Officer{} если будете импортировать officers.csv
+		&models.Officer{},
+		&models.CompanyMetric{},
+		&models.Person{},
+		&models.ImportRun{},
+		&models.ChangeEvent{},
+		&models.EntityVersion{},
+		&models.WatchlistEntry{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.ScreeningList{},
+		&models.ScreeningEntry{},
+		&models.ScreeningMatch{},
+		&models.CompanyRisk{},
+		&models.CompanyRiskFlag{},
 	)
 	if err != nil {
 		log.Fatalf("FATAL: AutoMigrate failed: %v", err)
 	}
 	log.Println("AutoMigrate completed.")
 
-	// --- Конфигурация импорта (СКОРРЕКТИРОВАНА!) ---
-	configs := map[string]Config{
-		"registers": {
+	// --- Полнотекстовый индекс (FTS5 в SQLite, pg_trgm в PostgreSQL) ---
+	ftsAvailable := false
+	if dbConn.IsPostgres(db) {
+		if err := dbConn.EnsureTrigramIndexes(db); err != nil {
+			log.Printf("WARN: Failed to create pg_trgm indexes, LIKE search will be slow: %v", err)
+		}
+	} else if err := dbConn.EnsureRegistersFTS(db); err != nil {
+		log.Printf("WARN: FTS5 is not available, full-text index will not be updated (build with -tags sqlite_fts5): %v", err)
+	} else {
+		ftsAvailable = true
+	}
+
+	// --- Запуск импорта: к нему привязываются события журнала изменений ---
+	run := models.ImportRun{CSVDir: *csvDir, StartedAt: time.Now().UTC()}
+	if err := db.Create(&run).Error; err != nil {
+		log.Fatalf("FATAL: Failed to create import run: %v", err)
+	}
+	log.Printf("Import run %d started.", run.ID)
+	options := importOptions{RunID: run.ID, FullSnapshot: *fullSnapshot, MaxDeletePercent: *maxDeletePercent}
+	if options.FullSnapshot {
+		log.Printf("Full snapshot mode: records missing from the CSV files will be deleted (limit %.1f%% per table).", options.MaxDeletePercent)
+	}
+
+	// --- Обработка конфигураций ---
+	ctx := context.Background()
+	for _, cfg := range importConfigs() {
+		// Проверяем путь к директории CSV
+		if _, err := os.Stat(*csvDir); os.IsNotExist(err) {
+			log.Printf("WARN: CSV directory not found: %s, skipping all files.", *csvDir)
+			break // Выходим, если папки нет
+		}
+		filePath := filepath.Join(*csvDir, cfg.FileName+".csv")
+		log.Printf("Processing file: %s for table %s", filePath, cfg.Name)
+		err := processCSV(ctx, db, filePath, cfg, options)
+		if err != nil {
+			log.Printf("ERROR processing %s: %v", filePath, err)
+		} else {
+			log.Printf("Successfully finished processing file: %s", filePath)
+		}
+	}
+
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	if err := db.Model(&models.ChangeEvent{}).Where("import_run_id = ?", run.ID).Count(&run.Changes).Error; err != nil {
+		log.Printf("ERROR counting changes of import run %d: %v", run.ID, err)
+	}
+	if err := db.Save(&run).Error; err != nil {
+		log.Printf("ERROR saving import run %d: %v", run.ID, err)
+	}
+	log.Printf("Import run %d recorded %d changes.", run.ID, run.Changes)
+
+	// Версии registers, members и beneficial_owners для запросов на дату (?as_of=)
+	log.Println("Updating record versions (valid_from/valid_to)...")
+	if err := syncVersions(db, run.ID, run.StartedAt); err != nil {
+		log.Printf("ERROR updating record versions: %v", err)
+	}
+
+	// Перестраиваем FTS индекс, чтобы он соответствовал только что загруженным registers
+	if ftsAvailable {
+		log.Println("Rebuilding registers full-text index...")
+		if err := dbConn.RebuildRegistersFTS(db); err != nil {
+			log.Printf("ERROR rebuilding registers_fts: %v", err)
+		} else {
+			log.Println("Full-text index rebuilt.")
+		}
+	}
+
+	// Сопоставляем физ. лиц из members, beneficial_owners и officers (person_id)
+	log.Println("Resolving persons across members, beneficial owners and officers...")
+	if err := resolvePersons(db); err != nil {
+		log.Printf("ERROR resolving persons: %v", err)
+	}
+
+	// Пересчитываем показатели для сравнения с аналогами по новым отчетам
+	log.Println("Refreshing benchmark aggregates (company_metrics)...")
+	if err := dbConn.RefreshCompanyMetrics(db); err != nil {
+		log.Printf("ERROR refreshing company_metrics: %v", err)
+	} else {
+		log.Println("Benchmark aggregates refreshed.")
+	}
+
+	// Оценка риска компаний по правилам (company_risks, company_risk_flags)
+	log.Println("Evaluating company risk rules...")
+	if rules, err := risk.LoadRules(*riskRulesPath); err != nil {
+		log.Printf("ERROR loading risk rules: %v", err)
+	} else if flagged, err := risk.Refresh(db, rules); err != nil {
+		log.Printf("ERROR evaluating risk rules: %v", err)
+	} else {
+		log.Printf("Risk rules evaluated: %d companies flagged.", flagged)
+	}
+
+	// Проверяем компании и лиц по спискам санкций и PEP
+	screenRegistry(db, *screeningDir, run.ID)
+
+	// Уведомляем подписчиков вебхуков об изменениях компаний из watchlist
+	notifyWebhooks(db, run.ID)
+	log.Println("CSV import process finished.")
+}
+
+// importConfigs - файлы импорта в порядке обработки. Порядок важен: журнал изменений находит
+// компанию строки отчета через financial_statements (changeTracker.regcode), поэтому
+// registers и financial_statements загружаются раньше детализации отчетов, а участники,
+// бенефициары и должностные лица - после компаний.
+func importConfigs() []Config {
+	return []Config{
+		{
+			Name:           "registers",
 			FileName:       "register",          // CSV файл называется register.csv
 			Model:          &models.Registers{}, // Используем модель Registers (для таблицы registers)
 			ConflictTarget: []clause.Column{{Name: "regcode"}},
@@ -73,30 +229,13 @@ func main() {
 				"without_quotes", "regtype", "regtype_text", "type", "type_text", "registered",
 				"terminated", "closed", "address", "index_company", // <-- index_company
 				"addressid", "region", "city", "atvk", "reregistration_term",
+				"name_folded", // Заполняется в Registers.BeforeSave
 			},
+			CompanyColumn: "regcode",
+			Snapshot:      true,
 		},
-		"members": {
-			FileName:       "members", // CSV файл members.csv
-			Model:          &models.Member{},
-			ConflictTarget: []clause.Column{{Name: "id"}}, // Используем ID из CSV
-			UpdateColumns: []string{ // Все поля модели Member КРОМЕ ID
-				"uri", "at_legal_entity_registration_number", "entity_type", "name",
-				"latvian_identity_number_masked", "birth_date", "legal_entity_registration_number",
-				"number_of_shares", "share_nominal_value", "share_currency", "date_from",
-				"registered_on", "last_modified_at",
-			},
-		},
-		"beneficial_owners": {
-			FileName:       "beneficial_owners", // CSV файл beneficial_owners.csv
-			Model:          &models.BeneficialOwner{},
-			ConflictTarget: []clause.Column{{Name: "id"}}, // Используем ID из CSV
-			UpdateColumns: []string{ // Все поля модели BeneficialOwner КРОМЕ ID
-				"legal_entity_registration_number", "forename", "surname",
-				"latvian_identity_number_masked", "birth_date", "nationality", "residence",
-				"registered_on", "last_modified_at",
-			},
-		},
-		"financial_statements": {
+		{
+			Name:     "financial_statements",
 			FileName: "financial_statements", // CSV файл financial_statements.csv
 			Model:    &models.FinancialStatement{},
 			ConflictTarget: []clause.Column{ // Ключ: компания + год
@@ -107,8 +246,10 @@ func main() {
 				"file_id", "source_schema", "source_type", "year_started_on", "year_ended_on",
 				"employees", "rounded_to_nearest", "currency", "created_at",
 			},
+			CompanyColumn: "legal_entity_registration_number",
 		},
-		"income_statements": {
+		{
+			Name:           "income_statements",
 			FileName:       "income_statements", // CSV файл income_statements.csv
 			Model:          &models.IncomeStatement{},
 			ConflictTarget: []clause.Column{{Name: "statement_id"}}, // Ключ (теперь с uniqueIndex)
@@ -123,7 +264,8 @@ func main() {
 				"provision_for_income_taxes", "income_after_income_taxes", "other_taxes", "extra_dividends", "net_income",
 			},
 		},
-		"balance_sheets": {
+		{
+			Name:           "balance_sheets",
 			FileName:       "balance_sheets", // CSV файл balance_sheets.csv
 			Model:          &models.BalanceSheet{},
 			ConflictTarget: []clause.Column{{Name: "statement_id"}}, // Ключ (теперь с uniqueIndex)
@@ -134,7 +276,8 @@ func main() {
 				"current_liabilities", "non_current_liabilities", "provisions", "equity", "total_equities",
 			},
 		},
-		"cash_flow_statements": {
+		{
+			Name:           "cash_flow_statements",
 			FileName:       "cash_flow_statements", // CSV файл cash_flow_statements.csv
 			Model:          &models.CashFlowStatement{},
 			ConflictTarget: []clause.Column{{Name: "statement_id"}}, // Ключ (теперь с uniqueIndex)
@@ -154,31 +297,67 @@ func main() {
 				"effect_of_exchange_rate_change", "net_increase", "at_beginning_of_year", "at_end_of_year",
 			},
 		},
-		// Добавьте сюда officers, если нужно, создав модель и конфигурацию
+		{
+			Name:           "members",
+			FileName:       "members", // CSV файл members.csv
+			Model:          &models.Member{},
+			ConflictTarget: []clause.Column{{Name: "id"}}, // Используем ID из CSV
+			UpdateColumns: []string{ // Все поля модели Member КРОМЕ ID
+				"uri", "at_legal_entity_registration_number", "entity_type", "name",
+				"latvian_identity_number_masked", "birth_date", "legal_entity_registration_number",
+				"number_of_shares", "share_nominal_value", "share_currency", "date_from",
+				"registered_on", "last_modified_at",
+				"name_folded", // Заполняется в Member.BeforeSave
+			},
+			CompanyColumn: "at_legal_entity_registration_number",
+			Snapshot:      true,
+			PerCompany:    true,
+		},
+		{
+			Name:           "beneficial_owners",
+			FileName:       "beneficial_owners", // CSV файл beneficial_owners.csv
+			Model:          &models.BeneficialOwner{},
+			ConflictTarget: []clause.Column{{Name: "id"}}, // Используем ID из CSV
+			UpdateColumns: []string{ // Все поля модели BeneficialOwner КРОМЕ ID
+				"legal_entity_registration_number", "forename", "surname",
+				"latvian_identity_number_masked", "birth_date", "nationality", "residence",
+				"registered_on", "last_modified_at",
+				"name_folded", // Заполняется в BeneficialOwner.BeforeSave
+			},
+			CompanyColumn: "legal_entity_registration_number",
+			Snapshot:      true,
+			PerCompany:    true,
+		},
+		{
+			Name:           "officers",
+			FileName:       "officers", // CSV файл officers.csv
+			Model:          &models.Officer{},
+			ConflictTarget: []clause.Column{{Name: "id"}}, // Используем ID из CSV
+			UpdateColumns: []string{ // Все поля модели Officer КРОМЕ ID
+				"uri", "at_legal_entity_registration_number", "entity_type", "position", "governing_body",
+				"name", "latvian_identity_number_masked", "birth_date", "legal_entity_registration_number",
+				"rights_of_representation_type", "representation_with_at_least", "registered_on", "last_modified_at",
+				"name_folded", // Заполняется в Officer.BeforeSave
+			},
+			CompanyColumn: "at_legal_entity_registration_number",
+			Snapshot:      true,
+			PerCompany:    true,
+		},
 	}
+}
 
-	// --- Обработка конфигураций ---
-	ctx := context.Background()
-	for cfgName, cfg := range configs {
-		// Проверяем путь к директории CSV
-		if _, err := os.Stat(*csvDir); os.IsNotExist(err) {
-			log.Printf("WARN: CSV directory not found: %s, skipping all files.", *csvDir)
-			break // Выходим, если папки нет
-		}
-		filePath := filepath.Join(*csvDir, cfg.FileName+".csv")
-		log.Printf("Processing file: %s for table %s", filePath, cfgName)
-		err := processCSV(ctx, db, filePath, cfg)
-		if err != nil {
-			log.Printf("ERROR processing %s: %v", filePath, err)
-		} else {
-			log.Printf("Successfully finished processing file: %s", filePath)
-		}
+// upsertClause - INSERT ... ON CONFLICT (ConflictTarget) DO UPDATE SET UpdateColumns (SQLite и PostgreSQL)
+func upsertClause(cfg Config) clause.OnConflict {
+	return clause.OnConflict{
+		Columns:   cfg.ConflictTarget,
+		DoUpdates: clause.AssignmentColumns(cfg.UpdateColumns),
 	}
-	log.Println("CSV import process finished.")
 }
 
-// processCSV обрабатывает один CSV файл
-func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) error {
+// processCSV обрабатывает один CSV файл. Изменения относительно БД пишутся в change_events с opts.RunID;
+// записи компаний из файла, которых в нем больше нет (cfg.PerCompany), а в полном режиме - все записи
+// таблицы, которых нет в файле, удаляются в той же транзакции.
+func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config, opts importOptions) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -189,10 +368,7 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = ';' // <--- УСТАНОВИТЕ ПРАВИЛЬНЫЙ РАЗДЕЛИТЕЛЬ (',' или ';')
-	reader.LazyQuotes = true
-	reader.TrimLeadingSpace = true
+	reader := newCSVReader(file)
 
 	headers, err := reader.Read()
 	if err == io.EOF {
@@ -209,6 +385,12 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 		headerMap[strings.ToLower(strings.TrimSpace(h))] = i
 	}
 
+	// Строки с повторяющимся ключом: обрабатывается только последняя (см. findDuplicateRows)
+	duplicates, err := findDuplicateRows(filePath, keyColumnIndexes(cfg, headerMap))
+	if err != nil {
+		return fmt.Errorf("could not check duplicate keys: %w", err)
+	}
+
 	// Получаем схему модели один раз перед циклом
 	stmt := &gorm.Statement{DB: db}
 	if err := stmt.Parse(cfg.Model); err != nil {
@@ -219,6 +401,7 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 	recordsProcessed := 0
 	recordsUpserted := 0
 	recordsFailed := 0
+	recordsDuplicate := 0
 	modelType := reflect.TypeOf(cfg.Model).Elem() // Тип структуры (не указателя)
 
 	// Используем транзакцию для каждого файла для ускорения
@@ -227,6 +410,23 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 		return fmt.Errorf("failed to start transaction for file %s: %w", filePath, tx.Error)
 	}
 
+	changes, err := newChangeTracker(tx, opts.RunID, cfg, schema)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to prepare change tracking for %s: %w", schema.Table, err)
+	}
+	if !changes.enabled {
+		log.Printf("Table %s is empty, initial load is not recorded in change_events.", schema.Table)
+	}
+	var snapshot *snapshotKeys
+	if opts.FullSnapshot && cfg.Snapshot {
+		snapshot = newSnapshotKeys(cfg, headerMap)
+	}
+	var perCompany *companyKeys
+	if cfg.PerCompany && snapshot == nil { // Полный режим удаляет и эти записи
+		perCompany = newCompanyKeys(cfg, headerMap)
+	}
+
 	for {
 		row, err := reader.Read()
 		if err == io.EOF {
@@ -235,21 +435,36 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 		if err != nil {
 			log.Printf("WARN: Error reading row (approx line %d): %v - Skipping row", recordsProcessed+2, err)
 			recordsFailed++
+			if snapshot != nil {
+				snapshot.unreadable++
+			}
+			if perCompany != nil {
+				perCompany.unreadable++
+			}
 			continue
 		}
 
 		recordsProcessed++
 
+		if key, duplicate := duplicates[recordsProcessed]; duplicate {
+			recordsDuplicate++
+			log.Printf("WARN: Skipping row %d: key %s appears again later in %s (the last row wins)", recordsProcessed+1, key, filePath)
+			if snapshot != nil {
+				snapshot.addRaw(row)
+			}
+			if perCompany != nil {
+				perCompany.addRaw(row)
+			}
+			continue
+		}
+
 		currentRecord := reflect.New(modelType).Interface() // Создаем *указатель* на структуру
 		currentRecordValue := reflect.ValueOf(currentRecord)
 
-		parseErrorsInRow := false
+		// ID берем из CSV, если он там есть: по нему связываются отчеты (statement_id)
+		// и работает ON CONFLICT (id). Пустой автоинкрементный ID пропускается в SetFieldValue.
+		var rowErrors []string // Все ошибки разбора строки собираем вместе, чтобы сообщить о них одной записью
 		for _, field := range schema.Fields {
-			// Пропускаем ID, если он автоинкрементный (мы используем его только как ConflictTarget из CSV)
-			if field.Name == "ID" && field.AutoIncrement && !isConflictTarget(field.Name, cfg.ConflictTarget) {
-				continue
-			}
-
 			// --- Маппинг для index -> index_company ---
 			csvHeaderName := strings.ToLower(field.DBName)
 			// Специальный случай для register.csv -> registers
@@ -265,8 +480,7 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 				continue
 			}
 			if columnIndex >= len(row) {
-				log.Printf("WARN: Row %d is shorter than expected header index for '%s'. Skipping field.", recordsProcessed+1, csvHeaderName)
-				parseErrorsInRow = true // Отмечаем ошибку
+				rowErrors = append(rowErrors, fmt.Sprintf("%s: row is shorter than header", csvHeaderName))
 				continue
 			}
 
@@ -274,22 +488,31 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 
 			err := SetFieldValue(ctx, currentRecordValue, field, valueStr)
 			if err != nil {
-				log.Printf("WARN: Failed to set field '%s' from value '%s' (line %d): %v", field.Name, valueStr, recordsProcessed+1, err)
-				parseErrorsInRow = true
+				rowErrors = append(rowErrors, fmt.Sprintf("%s: %v", csvHeaderName, err))
 			}
 		}
 
-		if parseErrorsInRow {
+		if snapshot != nil {
+			snapshot.addRaw(row) // Строка есть в выгрузке, даже если ее не удалось разобрать
+		}
+		if perCompany != nil {
+			perCompany.addRaw(row)
+		}
+		if len(rowErrors) > 0 {
 			recordsFailed++
-			log.Printf("WARN: Skipping row %d due to parsing errors.", recordsProcessed+1)
+			log.Printf("WARN: Skipping row %d due to parsing errors: %s", recordsProcessed+1, strings.Join(rowErrors, "; "))
 			continue
 		}
 
+		// Текущая версия записи - для журнала изменений
+		previous, err := changes.existing(ctx, currentRecordValue)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to load existing record (line %d), transaction rolled back: %w", recordsProcessed+1, err)
+		}
+
 		// Выполняем Upsert внутри транзакции
-		result := tx.WithContext(ctx).Clauses(clause.OnConflict{
-			Columns:   cfg.ConflictTarget,
-			DoUpdates: clause.AssignmentColumns(cfg.UpdateColumns),
-		}).Create(currentRecord)
+		result := tx.WithContext(ctx).Clauses(upsertClause(cfg)).Create(currentRecord)
 
 		if result.Error != nil {
 			log.Printf("ERROR: Failed to upsert record (line %d): %v - Record Data approx: %+v", recordsProcessed+1, result.Error, currentRecord)
@@ -301,12 +524,51 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 			if result.RowsAffected > 0 {
 				recordsUpserted++
 			}
+			if err := changes.record(ctx, currentRecordValue, previous); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to record changes (line %d), transaction rolled back: %w", recordsProcessed+1, err)
+			}
+			if snapshot != nil {
+				snapshot.seen[changes.entityID(ctx, currentRecordValue)] = true
+			}
 			if recordsProcessed%1000 == 0 { // Логируем прогресс
 				log.Printf("Processed %d rows for %s...", recordsProcessed, filePath)
 			}
 		}
 	}
 
+	// Записи компаний из файла, которых в нем больше нет (бенефициар удален и т.п.)
+	recordsDeleted := 0
+	if perCompany != nil {
+		if perCompany.unreadable > 0 {
+			log.Printf("WARN: %d unreadable rows in %s, their companies are unknown: skipping removal of missing records.", perCompany.unreadable, filePath)
+		} else {
+			recordsDeleted, err = deleteRemoved(ctx, tx, cfg, schema, changes, perCompany)
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to remove missing records, transaction rolled back: %w", err)
+			}
+		}
+	}
+
+	// Полный режим: удаляем записи, которых нет в файле
+	if snapshot != nil {
+		if snapshot.unreadable > 0 {
+			log.Printf("WARN: %d unreadable rows in %s, keys are unknown: skipping deletion of missing records.", snapshot.unreadable, filePath)
+		} else {
+			recordsDeleted, err = deleteMissing(ctx, tx, cfg, schema, changes, snapshot, opts.MaxDeletePercent)
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("%w, transaction rolled back", err)
+			}
+		}
+	}
+
+	if err := changes.flush(); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record changes, transaction rolled back: %w", err)
+	}
+
 	// Коммитим транзакцию, если весь файл обработан без ошибок upsert
 	if err := tx.Commit().Error; err != nil {
 		log.Printf("ERROR: Failed to commit transaction for file %s: %v", filePath, err)
@@ -315,22 +577,17 @@ func processCSV(ctx context.Context, db *gorm.DB, filePath string, cfg Config) e
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	log.Printf("Finished processing %s. Total rows read: %d, Rows upserted/updated: %d, Rows failed/skipped: %d",
-		filePath, recordsProcessed, recordsUpserted, recordsFailed)
+	// id из CSV вставлены явно: в PostgreSQL сдвигаем последовательность (для записей без id)
+	if err := dbConn.SyncIDSequence(db, schema.Table); err != nil {
+		log.Printf("WARN: Failed to sync id sequence of %s: %v", schema.Table, err)
+	}
+
+	log.Printf("Finished processing %s. Total rows read: %d, Rows upserted/updated: %d, Rows failed/skipped: %d, Duplicate keys skipped: %d, Rows deleted: %d, Changes recorded: %d",
+		filePath, recordsProcessed, recordsUpserted, recordsFailed, recordsDuplicate, recordsDeleted, changes.recorded)
 
 	return nil
 }
 
-// isConflictTarget проверяет, является ли поле частью ключа конфликта
-func isConflictTarget(fieldName string, targets []clause.Column) bool {
-	for _, target := range targets {
-		if target.Name == fieldName {
-			return true
-		}
-	}
-	return false
-}
-
 // SetFieldValue - Хелпер для установки значения поля структуры через reflect
 func SetFieldValue(ctx context.Context, targetStructValue reflect.Value, field *schema.Field, valueStr string) error {
 
@@ -361,34 +618,61 @@ func SetFieldValue(ctx context.Context, targetStructValue reflect.Value, field *
 		}
 	}
 
-	// --- Все поля, кроме ID, у нас *string согласно моделям ---
-	// Проверяем, что поле действительно *string
-	if field.FieldType.Kind() == reflect.Ptr && field.FieldType.Elem().Kind() == reflect.String {
-		// Создаем указатель на строку
-		ptrValue := reflect.New(field.FieldType.Elem())                // *string -> string
-		ptrValue.Elem().SetString(valueStr)                            // Устанавливаем значение строки
-		return field.Set(ctx, targetStructValue, ptrValue.Interface()) // Устанавливаем указатель *string
-	} else {
-		// Если поле в модели не *string (кроме ID), это несоответствие
-		// или нужно добавить логику парсинга для других типов здесь
-		log.Printf("DEBUG: Field %s is not *string (and not ID), value '%s' ignored.", field.Name, valueStr)
-		// Возвращаем ошибку или просто игнорируем? Давайте игнорировать пока.
-		// return fmt.Errorf("field %s type mismatch: expected *string, got %s", field.Name, field.FieldType.String())
-		return nil
+	// --- Разбор по типу поля модели ---
+	fieldType := field.FieldType
+	isPtr := false
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+		isPtr = true
 	}
 
-	// Старая логика парсинга чисел/bool закомментирована, т.к. модели используют *string
-	/*
-		var value interface{}
-		var err error
-		fieldType := field.FieldType
-		isPtr := false
-		if fieldType.Kind() == reflect.Ptr {	fieldType = fieldType.Elem(); isPtr = true }
-
-		switch fieldType.Kind() {
-		// ... парсинг uint, int, float, bool ...
+	var value interface{}
+	switch {
+	case fieldType == reflect.TypeOf(time.Time{}):
+		t, err := utils.ParseDate(valueStr)
+		if err != nil {
+			return err
+		}
+		value = t
+	case fieldType.Kind() == reflect.String:
+		value = valueStr
+	case fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Int64:
+		i, err := utils.ParseInt(valueStr)
+		if err != nil {
+			return err
 		}
-		if err != nil { return fmt.Errorf(...) }
-		if isPtr { // ... установка указателя ... } else { // ... установка значения ... }
-	*/
+		value = i
+	case fieldType.Kind() >= reflect.Uint && fieldType.Kind() <= reflect.Uint64:
+		i, err := utils.ParseInt(valueStr)
+		if err != nil {
+			return err
+		}
+		if i < 0 {
+			return fmt.Errorf("'%s' cannot be negative", valueStr)
+		}
+		value = uint64(i)
+	case fieldType.Kind() == reflect.Float32 || fieldType.Kind() == reflect.Float64:
+		f, err := utils.ParseFloat(valueStr)
+		if err != nil {
+			return err
+		}
+		value = f
+	case fieldType.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(valueStr)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a bool", valueStr)
+		}
+		value = b
+	default:
+		return fmt.Errorf("unsupported field type %s", field.FieldType.String())
+	}
+
+	// Приводим разобранное значение к точному типу поля (int64 -> int, uint64 -> uint и т.д.)
+	converted := reflect.ValueOf(value).Convert(fieldType)
+	if isPtr {
+		ptrValue := reflect.New(fieldType)
+		ptrValue.Elem().Set(converted)
+		return field.Set(ctx, targetStructValue, ptrValue.Interface())
+	}
+	return field.Set(ctx, targetStructValue, converted.Interface())
 }
@@ -0,0 +1,237 @@
+// cmd/importer/persons.go
+package main
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// Уверенность сопоставления записи с лицом
+const (
+	confidenceIDAndBirthDate = 0.99 // Начало кода = дата рождения (ddmmyy), имя совпадает
+	confidenceID             = 0.9  // Начало кода и имя, даты рождения нет
+	confidenceIDNewFormat    = 0.95 // Код нового формата (32...) не содержит даты, дата рождения есть
+	confidenceIDConflict     = 0.7  // Начало кода и дата рождения противоречат друг другу
+	confidenceBirthDate      = 0.85 // Кода нет: дата рождения (как ddmmyy) и имя
+	confidenceNameUnique     = 0.6  // Только имя, и ему соответствует ровно одно лицо с кодом
+	confidenceNameOnly       = 0.5  // Только имя, лиц с таким именем и кодом нет
+	confidenceNameAmbiguous  = 0.2  // Только имя, лиц с таким именем и кодом несколько
+)
+
+// identityPrefixPattern - первые 6 цифр маскированного кода ("090859-*****")
+var identityPrefixPattern = regexp.MustCompile(`^(\d{6})`)
+
+// personRecord - строка members/beneficial_owners/officers, описывающая физ. лицо
+type personRecord struct {
+	Table      string
+	ID         uint
+	Name       string
+	Masked     *string
+	BirthDate  *time.Time
+	MatchKey   string
+	Confidence float64
+}
+
+// resolvePersons сопоставляет физ. лиц из members, beneficial_owners и officers и
+// записывает person_id и person_confidence. Ключ лица - начало персонального кода
+// (или дата рождения в формате ddmmyy) + слова имени без учета порядка и диакритики;
+// записи только с именем присоединяются к единственному лицу с таким именем.
+// ID лица сохраняется между запусками, пока ключ не меняется.
+func resolvePersons(db *gorm.DB) error {
+	records, err := loadPersonRecords(db)
+	if err != nil {
+		return err
+	}
+
+	// Сильные ключи: по коду или дате рождения
+	strongKeysByName := make(map[string]map[string]bool)
+	var nameOnly []*personRecord
+	for i := range records {
+		record := &records[i]
+		nameKey := utils.PersonKey(record.Name, nil)
+		prefix, confidence := identityPrefix(record.Masked, record.BirthDate)
+		if prefix == "" {
+			nameOnly = append(nameOnly, record)
+			continue
+		}
+		record.MatchKey = utils.PersonKey(record.Name, &prefix)
+		record.Confidence = confidence
+		if strongKeysByName[nameKey] == nil {
+			strongKeysByName[nameKey] = make(map[string]bool)
+		}
+		strongKeysByName[nameKey][record.MatchKey] = true
+	}
+	for _, record := range nameOnly {
+		nameKey := utils.PersonKey(record.Name, nil)
+		candidates := strongKeysByName[nameKey]
+		switch len(candidates) {
+		case 1:
+			for key := range candidates {
+				record.MatchKey = key
+			}
+			record.Confidence = confidenceNameUnique
+		case 0:
+			record.MatchKey, record.Confidence = nameKey, confidenceNameOnly
+		default:
+			record.MatchKey, record.Confidence = nameKey, confidenceNameAmbiguous
+		}
+	}
+
+	// Сводные данные лиц: имя и код из первой записи с наибольшей уверенностью
+	persons := make(map[string]*models.Person)
+	var keys []string
+	for _, record := range records {
+		person, ok := persons[record.MatchKey]
+		if !ok {
+			person = &models.Person{MatchKey: record.MatchKey, Name: record.Name, Confidence: record.Confidence}
+			persons[record.MatchKey] = person
+			keys = append(keys, record.MatchKey)
+		}
+		person.Records++
+		if record.Confidence < person.Confidence {
+			person.Confidence = record.Confidence
+		}
+		if person.LatvianIdentityNumberMasked == nil {
+			person.LatvianIdentityNumberMasked = record.Masked
+		}
+		if person.BirthDate == nil {
+			person.BirthDate = record.BirthDate
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Существующие лица сохраняют ID по ключу
+		var existing []models.Person
+		if err := tx.Select("id", "match_key").Find(&existing).Error; err != nil {
+			return err
+		}
+		ids := make(map[string]uint, len(existing))
+		for _, person := range existing {
+			ids[person.MatchKey] = person.ID
+		}
+
+		now := time.Now().UTC()
+		var created []*models.Person
+		for _, key := range keys {
+			person := persons[key]
+			person.UpdatedAt = now
+			if id, ok := ids[key]; ok {
+				person.ID = id
+				err := tx.Model(&models.Person{}).Where("id = ?", id).Updates(map[string]interface{}{
+					"name": person.Name, "latvian_identity_number_masked": person.LatvianIdentityNumberMasked,
+					"birth_date": person.BirthDate, "confidence": person.Confidence,
+					"records": person.Records, "updated_at": now,
+				}).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			created = append(created, person)
+		}
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(created, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range []string{"members", "beneficial_owners", "officers"} {
+			if err := tx.Exec("UPDATE " + table + " SET person_id = NULL, person_confidence = NULL").Error; err != nil {
+				return err
+			}
+		}
+		for _, record := range records {
+			err := tx.Exec("UPDATE "+record.Table+" SET person_id = ?, person_confidence = ? WHERE id = ?",
+				persons[record.MatchKey].ID, record.Confidence, record.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// Лица, у которых не осталось записей
+		result := tx.Exec(`DELETE FROM persons WHERE id NOT IN (
+			SELECT person_id FROM members WHERE person_id IS NOT NULL
+			UNION SELECT person_id FROM beneficial_owners WHERE person_id IS NOT NULL
+			UNION SELECT person_id FROM officers WHERE person_id IS NOT NULL)`)
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Printf("Person resolution: %d records -> %d persons (%d new, %d removed)", len(records), len(keys), len(created), result.RowsAffected)
+		return nil
+	})
+}
+
+// loadPersonRecords загружает физ. лиц из трех таблиц (у members и officers - только NATURAL_PERSON)
+func loadPersonRecords(db *gorm.DB) ([]personRecord, error) {
+	var records []personRecord
+	add := func(table string, id uint, name string, masked *string, birthDate *time.Time) {
+		name = strings.TrimSpace(name)
+		if utils.FoldName(name) == "" {
+			return
+		}
+		records = append(records, personRecord{Table: table, ID: id, Name: name, Masked: masked, BirthDate: birthDate})
+	}
+
+	var members []models.Member
+	if err := db.Select("id", "name", "latvian_identity_number_masked", "birth_date").
+		Where("entity_type = ?", "NATURAL_PERSON").Order("id asc").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		add("members", m.ID, utils.StringValue(m.Name), m.LatvianIdentityNumberMasked, m.BirthDate)
+	}
+
+	var owners []models.BeneficialOwner
+	if err := db.Select("id", "forename", "surname", "latvian_identity_number_masked", "birth_date").
+		Order("id asc").Find(&owners).Error; err != nil {
+		return nil, err
+	}
+	for _, o := range owners {
+		add("beneficial_owners", o.ID, utils.StringValue(o.Forename)+" "+utils.StringValue(o.Surname), o.LatvianIdentityNumberMasked, o.BirthDate)
+	}
+
+	var officers []models.Officer
+	if err := db.Select("id", "name", "latvian_identity_number_masked", "birth_date").
+		Where("entity_type = ?", "NATURAL_PERSON").Order("id asc").Find(&officers).Error; err != nil {
+		return nil, err
+	}
+	for _, o := range officers {
+		add("officers", o.ID, utils.StringValue(o.Name), o.LatvianIdentityNumberMasked, o.BirthDate)
+	}
+	return records, nil
+}
+
+// identityPrefix возвращает часть ключа лица (6 цифр кода или дата рождения как ddmmyy) и уверенность.
+// Пустой prefix - ни кода, ни даты рождения нет.
+func identityPrefix(masked *string, birthDate *time.Time) (prefix string, confidence float64) {
+	var birthPrefix string
+	if birthDate != nil {
+		birthPrefix = birthDate.Format("020106")
+	}
+	if masked != nil {
+		if match := identityPrefixPattern.FindStringSubmatch(strings.TrimSpace(*masked)); match != nil {
+			prefix = match[1]
+			switch {
+			case birthDate == nil:
+				return prefix, confidenceID
+			case prefix == birthPrefix:
+				return prefix, confidenceIDAndBirthDate
+			case strings.HasPrefix(prefix, "32"): // Новый формат кода без даты рождения
+				return prefix, confidenceIDNewFormat
+			default:
+				return prefix, confidenceIDConflict
+			}
+		}
+	}
+	if birthDate != nil {
+		return birthPrefix, confidenceBirthDate
+	}
+	return "", 0
+}
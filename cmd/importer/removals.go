@@ -0,0 +1,94 @@
+// cmd/importer/removals.go
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"capital-view-api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// companyKeys - ключи строк файла по компаниям (CompanyColumn). Выгрузка members,
+// beneficial_owners и officers содержит полный список записей каждой компании из файла,
+// поэтому запись такой компании, которой нет в файле, удалена в реестре.
+type companyKeys struct {
+	keyColumns    []int // Индексы колонок ключа в CSV (-1 - колонки нет)
+	companyColumn int   // Индекс колонки компании в CSV (-1 - колонки нет)
+	keys          map[string]map[string]bool
+	unreadable    int // Строки, которые не удалось прочитать как CSV: их компания неизвестна
+}
+
+func newCompanyKeys(cfg Config, headerMap map[string]int) *companyKeys {
+	companyColumn, ok := headerMap[strings.ToLower(cfg.CompanyColumn)]
+	if !ok {
+		companyColumn = -1
+	}
+	return &companyKeys{
+		keyColumns:    keyColumnIndexes(cfg, headerMap),
+		companyColumn: companyColumn,
+		keys:          make(map[string]map[string]bool),
+	}
+}
+
+// addRaw запоминает ключ строки в списке ее компании (по сырым значениям CSV, в т.ч. для строк с ошибками разбора)
+func (k *companyKeys) addRaw(row []string) {
+	if k.companyColumn < 0 || k.companyColumn >= len(row) {
+		return
+	}
+	company := strings.TrimSpace(row[k.companyColumn])
+	if company == "" {
+		return
+	}
+	if k.keys[company] == nil {
+		k.keys[company] = make(map[string]bool)
+	}
+	key, _ := rawKey(k.keyColumns, row)
+	k.keys[company][key] = true
+}
+
+// deleteRemoved удаляет записи компаний из файла, ключей которых нет в списке этой компании,
+// и пишет по ним события delete. Компании, которых в файле нет, не затрагиваются
+// (их записи удаляет только полный режим, -full).
+func deleteRemoved(ctx context.Context, tx *gorm.DB, cfg Config, modelSchema *schema.Schema, changes *changeTracker, keys *companyKeys) (int, error) {
+	companyField := modelSchema.LookUpField(cfg.CompanyColumn)
+	if companyField == nil || len(keys.keys) == 0 {
+		return 0, nil
+	}
+	companies := make([]string, 0, len(keys.keys))
+	for company := range keys.keys {
+		companies = append(companies, company)
+	}
+
+	modelType := reflect.TypeOf(cfg.Model).Elem()
+	var removed []reflect.Value
+	for start := 0; start < len(companies); start += snapshotBatchSize {
+		end := start + snapshotBatchSize
+		if end > len(companies) {
+			end = len(companies)
+		}
+		rows := reflect.New(reflect.SliceOf(modelType))
+		if err := tx.Where(companyField.DBName+" IN ?", companies[start:end]).Find(rows.Interface()).Error; err != nil {
+			return 0, err
+		}
+		slice := rows.Elem()
+		for i := 0; i < slice.Len(); i++ {
+			record := reflect.New(modelType)
+			record.Elem().Set(slice.Index(i))
+			company, _ := companyField.ValueOf(ctx, record)
+			if !keys.keys[utils.StringValue(formatChangeValue(company))][changes.entityID(ctx, record)] {
+				removed = append(removed, record)
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+	if err := deleteRecords(ctx, tx, modelSchema, changes, removed); err != nil {
+		return 0, err
+	}
+	return len(removed), nil
+}
@@ -0,0 +1,72 @@
+// cmd/importer/removals_test.go
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"capital-view-api/models"
+)
+
+// TestRemovedRecords: обычный импорт удаляет записи компаний из файла, которых в нем больше нет,
+// и пишет по ним события delete; компании, которых в файле нет, не затрагиваются
+func TestRemovedRecords(t *testing.T) {
+	database := connectTestSQLite(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	owners := configByName(t, "beneficial_owners")
+
+	path := writeCSV(t, dir, "beneficial_owners",
+		"id;legal_entity_registration_number;forename;surname;birth_date",
+		"1;40000000001;Jānis;Bērziņš;01/02/1970",
+		"2;40000000001;Anna;Kalniņa;03/04/1980",
+		"3;40000000001;Pēteris;Ozols;05/06/1990",
+		"4;40000000002;Ilze;Liepa;07/08/1975",
+	)
+	if err := processCSV(ctx, database, path, owners, importOptions{}); err != nil {
+		t.Fatalf("initial import: %v", err)
+	}
+
+	run := models.ImportRun{CSVDir: dir, StartedAt: time.Now().UTC()}
+	if err := database.Create(&run).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Бенефициара 3 больше нет; строку 2 не удалось разобрать, но она есть в файле;
+	// компании 40000000002 в файле нет
+	path = writeCSV(t, dir, "beneficial_owners",
+		"id;legal_entity_registration_number;forename;surname;birth_date",
+		"1;40000000001;Jānis;Bērziņš;01/02/1970",
+		"2;40000000001;Anna;Kalniņa;not a date",
+	)
+	if err := processCSV(ctx, database, path, owners, importOptions{RunID: run.ID}); err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+
+	var ids []uint
+	if err := database.Model(&models.BeneficialOwner{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 4 {
+		t.Errorf("beneficial owners after import = %v, want [1 2 4]", ids)
+	}
+
+	var events []models.ChangeEvent
+	if err := database.Where("import_run_id = ?", run.ID).Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("change events = %d, want 1 (delete of owner 3): %+v", len(events), events)
+	}
+	event := events[0]
+	if event.Operation != models.ChangeOperationDelete || event.Entity != "beneficial_owners" || event.EntityID != "3" {
+		t.Errorf("event = %+v, want delete of beneficial_owners 3", event)
+	}
+	if event.Regcode == nil || *event.Regcode != "40000000001" {
+		t.Errorf("event regcode = %v, want 40000000001", event.Regcode)
+	}
+	if event.OldValue == nil || !strings.Contains(*event.OldValue, "Ozols") {
+		t.Errorf("event old_value = %v, want the removed record as JSON", event.OldValue)
+	}
+}
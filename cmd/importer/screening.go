@@ -0,0 +1,51 @@
+// cmd/importer/screening.go
+package main
+
+import (
+	"flag"
+	"log"
+
+	dbConn "capital-view-api/db"
+	"capital-view-api/screening"
+
+	"gorm.io/gorm"
+)
+
+// runLoadScreeningLists - подкоманда "load-screening-lists": загружает списки санкций/PEP
+// (.csv, .xml: ООН, ЕС, OFAC SDN) из каталога и проверяет по ним весь реестр.
+//
+//	go run ./cmd/importer load-screening-lists -dir ./screening_lists
+func runLoadScreeningLists(args []string) {
+	flags := flag.NewFlagSet("load-screening-lists", flag.ExitOnError)
+	dir := flags.String("dir", "./screening_lists", "Directory with sanctions/PEP list files (.csv, .xml)")
+	flags.Parse(args)
+
+	if err := dbConn.ConnectDatabase(); err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	if err := dbConn.EnsureScreening(dbConn.DB); err != nil {
+		log.Fatalf("FATAL: AutoMigrate failed: %v", err)
+	}
+	screenRegistry(dbConn.DB, *dir, 0)
+}
+
+// screenRegistry при заданном dir перезагружает списки из каталога, затем проверяет реестр
+// по всем загруженным спискам. Ошибки только логируются: импорт от скрининга не зависит.
+func screenRegistry(db *gorm.DB, dir string, runID uint) {
+	if dir != "" {
+		log.Printf("Loading screening lists from %s...", dir)
+		loaded, err := screening.LoadDir(db, dir)
+		if err != nil {
+			log.Printf("ERROR loading screening lists: %v", err)
+		}
+		log.Printf("Screening lists loaded: %d.", loaded)
+	}
+
+	log.Println("Screening companies, members, beneficial owners and officers against sanctions/PEP lists...")
+	matches, err := screening.Run(db, runID)
+	if err != nil {
+		log.Printf("ERROR screening against sanctions/PEP lists: %v", err)
+		return
+	}
+	log.Printf("Screening finished: %d matches.", matches)
+}
@@ -0,0 +1,45 @@
+// cmd/importer/shareholding_test.go
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+)
+
+// testdata/shareholding - register.csv и members.csv из csv_examples. В примерах нет участников -
+// юр. лиц, поэтому в строке 93 участником сделана компания IK DANDIS (40002158072): она держит
+// доли 40103196773. Строки с id 38 повторяются, как в csv_examples (остается последняя).
+// Ответы /shareholders и /holdings по этим строкам проверяет handlers/shareholding_handlers_test.go.
+func TestImportMemberRelations(t *testing.T) {
+	database := connectTestSQLite(t)
+	dir := filepath.Join("testdata", "shareholding")
+	for _, cfg := range importConfigs() {
+		if err := processCSV(context.Background(), database, filepath.Join(dir, cfg.FileName+".csv"), cfg, importOptions{}); err != nil {
+			t.Fatalf("import %s: %v", cfg.Name, err)
+		}
+	}
+
+	tests := []struct {
+		id   uint
+		at   string // at_legal_entity_registration_number - компания, в которой держатся доли
+		self string // legal_entity_registration_number - regcode участника - юр. лица
+	}{
+		{3, "40103165237", ""},
+		{38, "40103182646", ""}, // Последняя из строк с id 38
+		{93, "40103196773", "40002158072"},
+	}
+	for _, tt := range tests {
+		var member models.Member
+		if err := database.First(&member, tt.id).Error; err != nil {
+			t.Fatalf("member %d: %v", tt.id, err)
+		}
+		at, self := utils.StringValue(member.AtLegalEntityRegistrationNumber), utils.StringValue(member.LegalEntityRegistrationNumber)
+		if at != tt.at || self != tt.self {
+			t.Errorf("member %d: at %q, own regcode %q; want at %q, own regcode %q", tt.id, at, self, tt.at, tt.self)
+		}
+	}
+}
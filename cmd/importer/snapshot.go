@@ -0,0 +1,110 @@
+// cmd/importer/snapshot.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// snapshotBatchSize - размер пачки при поиске и удалении отсутствующих записей
+const snapshotBatchSize = 1000
+
+// importOptions - параметры запуска, общие для всех файлов
+type importOptions struct {
+	RunID            uint    // Запуск импорта (import_runs), к которому привязываются изменения
+	FullSnapshot     bool    // CSV - полная выгрузка: записи, которых в ней нет, удаляются (-full)
+	MaxDeletePercent float64 // Предел удаления в полном режиме, % строк таблицы
+}
+
+// snapshotKeys - ключи (ConflictTarget) всех строк файла, в т.ч. не прошедших разбор:
+// такие строки есть в выгрузке и не должны удаляться
+type snapshotKeys struct {
+	columns    []int // Индексы колонок ключа в CSV (-1 - колонки нет)
+	seen       map[string]bool
+	unreadable int // Строки, которые не удалось прочитать как CSV: их ключ неизвестен
+}
+
+func newSnapshotKeys(cfg Config, headerMap map[string]int) *snapshotKeys {
+	return &snapshotKeys{columns: keyColumnIndexes(cfg, headerMap), seen: make(map[string]bool)}
+}
+
+// addRaw запоминает ключ строки по сырым значениям CSV (работает и для строк с ошибками разбора)
+func (k *snapshotKeys) addRaw(row []string) {
+	key, _ := rawKey(k.columns, row)
+	k.seen[key] = true
+}
+
+// deleteMissing удаляет записи таблицы, ключей которых нет в полной выгрузке, и пишет по ним
+// события delete (old_value - запись в JSON). Если удалить пришлось бы больше maxPercent
+// строк таблицы, возвращает ошибку: обычно это обрезанный или пустой файл.
+func deleteMissing(ctx context.Context, tx *gorm.DB, cfg Config, modelSchema *schema.Schema, changes *changeTracker, keys *snapshotKeys, maxPercent float64) (int, error) {
+	var total int64
+	if err := tx.Table(modelSchema.Table).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, nil
+	}
+
+	modelType := reflect.TypeOf(cfg.Model).Elem()
+	rows := reflect.New(reflect.SliceOf(modelType))
+	var missing []reflect.Value
+	err := tx.Model(cfg.Model).FindInBatches(rows.Interface(), snapshotBatchSize, func(batch *gorm.DB, _ int) error {
+		slice := rows.Elem()
+		for i := 0; i < slice.Len(); i++ {
+			record := reflect.New(modelType)
+			record.Elem().Set(slice.Index(i))
+			if !keys.seen[changes.entityID(ctx, record)] {
+				missing = append(missing, record)
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	percent := float64(len(missing)) / float64(total) * 100
+	if percent > maxPercent {
+		return 0, fmt.Errorf("full snapshot aborted: %d of %d %s rows (%.1f%%) are missing from the file, limit is %.1f%% (-max-delete-percent)",
+			len(missing), total, modelSchema.Table, percent, maxPercent)
+	}
+
+	if err := deleteRecords(ctx, tx, modelSchema, changes, missing); err != nil {
+		return 0, err
+	}
+	return len(missing), nil
+}
+
+// deleteRecords удаляет записи по первичному ключу пачками и пишет по каждой событие delete
+func deleteRecords(ctx context.Context, tx *gorm.DB, modelSchema *schema.Schema, changes *changeTracker, records []reflect.Value) error {
+	primaryField := modelSchema.PrioritizedPrimaryField
+	if primaryField == nil {
+		return fmt.Errorf("table %s has no primary key", modelSchema.Table)
+	}
+	for start := 0; start < len(records); start += snapshotBatchSize {
+		end := start + snapshotBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		ids := make([]interface{}, 0, end-start)
+		for _, record := range records[start:end] {
+			id, _ := primaryField.ValueOf(ctx, record)
+			ids = append(ids, id)
+			if err := changes.recordDelete(ctx, record); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where(primaryField.DBName+" IN ?", ids).Delete(reflect.New(records[start].Type().Elem()).Interface()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// cmd/importer/snapshot_test.go
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+const officersHeader = "id;at_legal_entity_registration_number;entity_type;governing_body;name;registered_on"
+
+// importOfficers - первичная загрузка четырех должностных лиц двух компаний
+func importOfficers(t *testing.T, database *gorm.DB, dir string) {
+	t.Helper()
+	path := writeCSV(t, dir, "officers", officersHeader,
+		"1;40000000001;NATURAL_PERSON;EXECUTIVE_BOARD;Bērziņš Jānis;01/02/2015",
+		"2;40000000001;NATURAL_PERSON;EXECUTIVE_BOARD;Kalniņa Anna;03/04/2016",
+		"3;40000000001;NATURAL_PERSON;COUNCIL;Ozols Pēteris;05/06/2017",
+		"4;40000000002;NATURAL_PERSON;EXECUTIVE_BOARD;Liepa Ilze;07/08/2018",
+	)
+	if err := processCSV(context.Background(), database, path, configByName(t, "officers"), importOptions{}); err != nil {
+		t.Fatalf("initial import: %v", err)
+	}
+}
+
+// newImportRun - запись запуска импорта, к которому привяжутся события
+func newImportRun(t *testing.T, database *gorm.DB, dir string, startedAt time.Time) models.ImportRun {
+	t.Helper()
+	run := models.ImportRun{CSVDir: dir, StartedAt: startedAt}
+	if err := database.Create(&run).Error; err != nil {
+		t.Fatal(err)
+	}
+	return run
+}
+
+func officerIDs(t *testing.T, database *gorm.DB) []uint {
+	t.Helper()
+	var ids []uint
+	if err := database.Model(&models.Officer{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+// TestFullSnapshotDeletes: в полном режиме удаляются записи, которых нет в файле (в т.ч. компаний,
+// которых в файле нет), по ним пишутся события delete и закрываются версии
+func TestFullSnapshotDeletes(t *testing.T) {
+	database := connectTestSQLite(t)
+	dir := t.TempDir()
+	importOfficers(t, database, dir)
+	firstRun := newImportRun(t, database, dir, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err := syncVersions(database, firstRun.ID, firstRun.StartedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	// Должностного лица 4 (и всей компании 40000000002) в полной выгрузке больше нет
+	run := newImportRun(t, database, dir, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	path := writeCSV(t, dir, "officers", officersHeader,
+		"1;40000000001;NATURAL_PERSON;EXECUTIVE_BOARD;Bērziņš Jānis;01/02/2015",
+		"2;40000000001;NATURAL_PERSON;EXECUTIVE_BOARD;Kalniņa Anna;03/04/2016",
+		"3;40000000001;NATURAL_PERSON;COUNCIL;Ozols Pēteris;05/06/2017",
+	)
+	opts := importOptions{RunID: run.ID, FullSnapshot: true, MaxDeletePercent: 30}
+	if err := processCSV(context.Background(), database, path, configByName(t, "officers"), opts); err != nil {
+		t.Fatalf("full import: %v", err)
+	}
+
+	if ids := officerIDs(t, database); len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("officers after full import = %v, want [1 2 3]", ids)
+	}
+	var events []models.ChangeEvent
+	if err := database.Where("import_run_id = ?", run.ID).Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("change events = %d, want 1 (delete of officer 4): %+v", len(events), events)
+	}
+	event := events[0]
+	if event.Operation != models.ChangeOperationDelete || event.Entity != "officers" || event.EntityID != "4" {
+		t.Errorf("event = %+v, want delete of officers 4", event)
+	}
+	if event.Regcode == nil || *event.Regcode != "40000000002" {
+		t.Errorf("event regcode = %v, want 40000000002", event.Regcode)
+	}
+	if event.OldValue == nil || !strings.Contains(*event.OldValue, "Liepa Ilze") {
+		t.Errorf("event old_value = %v, want the removed record as JSON", event.OldValue)
+	}
+
+	if err := syncVersions(database, run.ID, run.StartedAt); err != nil {
+		t.Fatal(err)
+	}
+	versions := entityVersions(t, database, "officers", "4")
+	if len(versions) != 1 {
+		t.Fatalf("officer 4 versions = %+v, want 1", versions)
+	}
+	assertPeriod(t, versions[0], ptr(time.Date(2018, 8, 7, 0, 0, 0, 0, time.UTC)), &run.StartedAt)
+	if versions := entityVersions(t, database, "officers", "1"); len(versions) != 1 || versions[0].ValidTo != nil {
+		t.Errorf("officer 1 versions = %+v, want one open version", versions)
+	}
+}
+
+// TestFullSnapshotAbortsTruncatedFile: если по файлу пришлось бы удалить больше
+// -max-delete-percent строк таблицы, файл откатывается целиком, включая обновления
+func TestFullSnapshotAbortsTruncatedFile(t *testing.T) {
+	database := connectTestSQLite(t)
+	dir := t.TempDir()
+	importOfficers(t, database, dir)
+
+	run := newImportRun(t, database, dir, time.Now().UTC())
+	path := writeCSV(t, dir, "officers", officersHeader,
+		"1;40000000001;NATURAL_PERSON;EXECUTIVE_BOARD;Bērziņš Jānis;01/02/2015",
+		"2;40000000001;NATURAL_PERSON;COUNCIL;Kalniņa Anna;03/04/2016", // Изменено: откатится
+	)
+	opts := importOptions{RunID: run.ID, FullSnapshot: true, MaxDeletePercent: 10}
+	err := processCSV(context.Background(), database, path, configByName(t, "officers"), opts)
+	if err == nil || !strings.Contains(err.Error(), "-max-delete-percent") || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("full import of a truncated file: err = %v, want abort by -max-delete-percent", err)
+	}
+
+	if ids := officerIDs(t, database); len(ids) != 4 {
+		t.Errorf("officers after aborted import = %v, want all 4", ids)
+	}
+	var body string
+	if err := database.Model(&models.Officer{}).Where("id = ?", 2).Pluck("governing_body", &body).Error; err != nil {
+		t.Fatal(err)
+	}
+	if body != "EXECUTIVE_BOARD" {
+		t.Errorf("officer 2 governing_body = %q, want the update rolled back", body)
+	}
+	var count int64
+	if err := database.Model(&models.ChangeEvent{}).Where("import_run_id = ?", run.ID).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("change events of the aborted run = %d, want 0", count)
+	}
+}
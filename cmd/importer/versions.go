@@ -0,0 +1,214 @@
+// cmd/importer/versions.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+// versionBatchSize - сколько записей сверяется с версиями за один запрос
+const versionBatchSize = 1000
+
+// versionedRecord - текущее состояние записи для сверки с ее действующей версией
+type versionedRecord struct {
+	EntityID  string
+	Regcode   *string
+	Snapshot  string     // JSON записи без служебных полей
+	SeedFrom  *time.Time // Начало первой версии: дата регистрации / вступления
+	ChangedAt *time.Time // Дата изменения по данным реестра (last_modified_at), если есть
+}
+
+// syncVersions сверяет registers, members, beneficial_owners и officers с entity_versions:
+// для новых записей открывает первую версию, для изменившихся закрывает действующую и
+// открывает новую, для исчезнувших из таблицы закрывает действующую датой запуска.
+func syncVersions(db *gorm.DB, runID uint, now time.Time) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var rows []models.Registers
+		return tx.Model(&models.Registers{}).FindInBatches(&rows, versionBatchSize, func(batch *gorm.DB, _ int) error {
+			records := make([]versionedRecord, 0, len(rows))
+			for _, row := range rows {
+				if row.Regcode == nil {
+					continue
+				}
+				snapshot := row
+				snapshot.NameFolded = nil
+				snapshot.Members, snapshot.BeneficialOwners, snapshot.FinancialStatements, snapshot.Officers = nil, nil, nil, nil
+				data, err := json.Marshal(snapshot)
+				if err != nil {
+					return err
+				}
+				records = append(records, versionedRecord{
+					EntityID: *row.Regcode, Regcode: row.Regcode, Snapshot: string(data), SeedFrom: row.Registered,
+				})
+			}
+			return syncVersionBatch(tx, "registers", records, runID, now)
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("registers: %w", err)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var rows []models.Member
+		return tx.Model(&models.Member{}).FindInBatches(&rows, versionBatchSize, func(batch *gorm.DB, _ int) error {
+			records := make([]versionedRecord, 0, len(rows))
+			for _, row := range rows {
+				snapshot := row
+				snapshot.NameFolded, snapshot.PersonID, snapshot.PersonConfidence, snapshot.OwnershipPercent = nil, nil, nil, nil
+				data, err := json.Marshal(snapshot)
+				if err != nil {
+					return err
+				}
+				seedFrom := row.DateFrom
+				if seedFrom == nil {
+					seedFrom = row.RegisteredOn
+				}
+				records = append(records, versionedRecord{
+					EntityID: strconv.FormatUint(uint64(row.ID), 10), Regcode: row.AtLegalEntityRegistrationNumber,
+					Snapshot: string(data), SeedFrom: seedFrom, ChangedAt: row.LastModifiedAt,
+				})
+			}
+			return syncVersionBatch(tx, "members", records, runID, now)
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("members: %w", err)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var rows []models.BeneficialOwner
+		return tx.Model(&models.BeneficialOwner{}).FindInBatches(&rows, versionBatchSize, func(batch *gorm.DB, _ int) error {
+			records := make([]versionedRecord, 0, len(rows))
+			for _, row := range rows {
+				snapshot := row
+				snapshot.NameFolded, snapshot.PersonID, snapshot.PersonConfidence = nil, nil, nil
+				data, err := json.Marshal(snapshot)
+				if err != nil {
+					return err
+				}
+				records = append(records, versionedRecord{
+					EntityID: strconv.FormatUint(uint64(row.ID), 10), Regcode: row.LegalEntityRegistrationNumber,
+					Snapshot: string(data), SeedFrom: row.RegisteredOn, ChangedAt: row.LastModifiedAt,
+				})
+			}
+			return syncVersionBatch(tx, "beneficial_owners", records, runID, now)
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("beneficial_owners: %w", err)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var rows []models.Officer
+		return tx.Model(&models.Officer{}).FindInBatches(&rows, versionBatchSize, func(batch *gorm.DB, _ int) error {
+			records := make([]versionedRecord, 0, len(rows))
+			for _, row := range rows {
+				snapshot := row
+				snapshot.NameFolded, snapshot.PersonID, snapshot.PersonConfidence = nil, nil, nil
+				data, err := json.Marshal(snapshot)
+				if err != nil {
+					return err
+				}
+				records = append(records, versionedRecord{
+					EntityID: strconv.FormatUint(uint64(row.ID), 10), Regcode: row.AtLegalEntityRegistrationNumber,
+					Snapshot: string(data), SeedFrom: row.RegisteredOn, ChangedAt: row.LastModifiedAt,
+				})
+			}
+			return syncVersionBatch(tx, "officers", records, runID, now)
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("officers: %w", err)
+	}
+
+	// Записи, которых больше нет в таблицах: закрываем действующие версии
+	removed := map[string]string{
+		"registers":         "SELECT regcode FROM registers WHERE regcode IS NOT NULL",
+		"members":           "SELECT CAST(id AS TEXT) FROM members",
+		"beneficial_owners": "SELECT CAST(id AS TEXT) FROM beneficial_owners",
+		"officers":          "SELECT CAST(id AS TEXT) FROM officers",
+	}
+	for entity, existing := range removed {
+		result := db.Model(&models.EntityVersion{}).
+			Where("entity = ? AND valid_to IS NULL AND entity_id NOT IN ("+existing+")", entity).
+			Update("valid_to", now)
+		if result.Error != nil {
+			return fmt.Errorf("closing removed %s versions: %w", entity, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Closed %d versions of removed %s records.", result.RowsAffected, entity)
+		}
+	}
+	return nil
+}
+
+// syncVersionBatch сверяет пачку записей одной таблицы с их версиями
+func syncVersionBatch(tx *gorm.DB, entity string, records []versionedRecord, runID uint, now time.Time) error {
+	if len(records) == 0 {
+		return nil
+	}
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.EntityID
+	}
+	var versions []models.EntityVersion
+	if err := tx.Where("entity = ? AND entity_id IN ?", entity, ids).Order("id").Find(&versions).Error; err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(versions))
+	open := make(map[string]models.EntityVersion, len(versions))
+	for _, version := range versions {
+		known[version.EntityID] = true
+		if version.ValidTo == nil {
+			open[version.EntityID] = version
+		}
+	}
+
+	var created []models.EntityVersion
+	for _, record := range records {
+		current, hasOpen := open[record.EntityID]
+		if hasOpen && current.Data == record.Snapshot {
+			continue
+		}
+
+		var validFrom *time.Time
+		switch {
+		case !known[record.EntityID]:
+			validFrom = record.SeedFrom // Первая версия действует с даты регистрации
+		case hasOpen:
+			validFrom = versionChangedAt(record.ChangedAt, current.ValidFrom, now)
+		default:
+			validFrom = &now // Запись снова появилась после удаления
+		}
+
+		if hasOpen {
+			if err := tx.Model(&models.EntityVersion{}).Where("id = ?", current.ID).Update("valid_to", validFrom).Error; err != nil {
+				return err
+			}
+		}
+		created = append(created, models.EntityVersion{
+			Entity: entity, EntityID: record.EntityID, Regcode: record.Regcode,
+			ValidFrom: validFrom, ImportRunID: runID, Data: record.Snapshot,
+		})
+	}
+	if len(created) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(created, versionBatchSize).Error
+}
+
+// versionChangedAt - начало новой версии: last_modified_at из реестра, если он позже начала
+// текущей версии и не в будущем, иначе время запуска импорта
+func versionChangedAt(changedAt, currentFrom *time.Time, now time.Time) *time.Time {
+	if changedAt != nil && !changedAt.After(now) && (currentFrom == nil || changedAt.After(*currentFrom)) {
+		return changedAt
+	}
+	return &now
+}
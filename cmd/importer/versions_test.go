@@ -0,0 +1,158 @@
+// cmd/importer/versions_test.go
+package main
+
+import (
+	"testing"
+	"time"
+
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+// date - полночь UTC
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// entityVersions - версии записи по порядку создания
+func entityVersions(t *testing.T, database *gorm.DB, entity, entityID string) []models.EntityVersion {
+	t.Helper()
+	var versions []models.EntityVersion
+	if err := database.Where("entity = ? AND entity_id = ?", entity, entityID).Order("id").Find(&versions).Error; err != nil {
+		t.Fatal(err)
+	}
+	return versions
+}
+
+// assertPeriod проверяет границы версии (nil - граница не задана)
+func assertPeriod(t *testing.T, version models.EntityVersion, from, to *time.Time) {
+	t.Helper()
+	same := func(got, want *time.Time) bool {
+		return (got == nil && want == nil) || (got != nil && want != nil && got.Equal(*want))
+	}
+	if !same(version.ValidFrom, from) || !same(version.ValidTo, to) {
+		t.Errorf("version %d (%s) = [%v, %v), want [%v, %v)", version.ID, version.Data, version.ValidFrom, version.ValidTo, from, to)
+	}
+}
+
+func TestSyncVersionBatchSeedsFirstVersion(t *testing.T) {
+	database := connectTestSQLite(t)
+	registered := date(2020, 3, 15)
+	now := date(2025, 1, 1)
+
+	err := syncVersionBatch(database, "members", []versionedRecord{
+		{EntityID: "1", Regcode: ptr("40000000001"), Snapshot: `{"id":1}`, SeedFrom: &registered},
+		{EntityID: "2", Regcode: ptr("40000000001"), Snapshot: `{"id":2}`}, // Дата вступления неизвестна
+	}, 7, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := entityVersions(t, database, "members", "1")
+	if len(first) != 1 {
+		t.Fatalf("member 1 versions = %+v, want 1", first)
+	}
+	assertPeriod(t, first[0], &registered, nil)
+	if first[0].ImportRunID != 7 || first[0].Regcode == nil || *first[0].Regcode != "40000000001" {
+		t.Errorf("version = %+v, want import run 7 and regcode 40000000001", first[0])
+	}
+	second := entityVersions(t, database, "members", "2")
+	if len(second) != 1 {
+		t.Fatalf("member 2 versions = %+v, want 1", second)
+	}
+	assertPeriod(t, second[0], nil, nil)
+
+	// Повторная сверка без изменений новых версий не создает
+	err = syncVersionBatch(database, "members", []versionedRecord{
+		{EntityID: "1", Snapshot: `{"id":1}`, SeedFrom: &registered},
+		{EntityID: "2", Snapshot: `{"id":2}`},
+	}, 8, now.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	if err := database.Model(&models.EntityVersion{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("versions after unchanged sync = %d, want 2", count)
+	}
+}
+
+func TestSyncVersionBatchChanges(t *testing.T) {
+	registered := date(2020, 3, 15)
+	now := date(2025, 1, 1)
+	tests := []struct {
+		name      string
+		changedAt *time.Time
+		want      time.Time // Граница между старой и новой версией
+	}{
+		{"last_modified_at", ptr(date(2023, 5, 1)), date(2023, 5, 1)},
+		{"no last_modified_at", nil, now},
+		{"last_modified_at in the future", ptr(date(2026, 1, 1)), now},
+		{"last_modified_at before current version", ptr(date(2019, 1, 1)), now},
+		{"last_modified_at equals current version", &registered, now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := connectTestSQLite(t)
+			record := versionedRecord{EntityID: "1", Snapshot: `{"share":10}`, SeedFrom: &registered}
+			if err := syncVersionBatch(database, "members", []versionedRecord{record}, 1, registered); err != nil {
+				t.Fatal(err)
+			}
+			record.Snapshot, record.ChangedAt = `{"share":20}`, tt.changedAt
+			if err := syncVersionBatch(database, "members", []versionedRecord{record}, 2, now); err != nil {
+				t.Fatal(err)
+			}
+
+			versions := entityVersions(t, database, "members", "1")
+			if len(versions) != 2 {
+				t.Fatalf("versions = %+v, want 2", versions)
+			}
+			assertPeriod(t, versions[0], &registered, &tt.want)
+			assertPeriod(t, versions[1], &tt.want, nil)
+			if versions[1].Data != `{"share":20}` || versions[1].ImportRunID != 2 {
+				t.Errorf("new version = %+v, want share 20 from import run 2", versions[1])
+			}
+		})
+	}
+}
+
+// TestSyncVersionsRemovedAndRestored: исчезнувшая из таблицы запись закрывается датой запуска,
+// а снова появившаяся открывает новую версию с даты запуска (не с даты вступления)
+func TestSyncVersionsRemovedAndRestored(t *testing.T) {
+	database := connectTestSQLite(t)
+	registered := date(2020, 3, 15)
+	member := models.Member{ID: 1, AtLegalEntityRegistrationNumber: ptr("40000000001"), Name: ptr("Jānis Bērziņš"), DateFrom: &registered}
+	if err := database.Create(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	first, removed, restored := date(2024, 1, 1), date(2024, 6, 1), date(2025, 1, 1)
+	if err := syncVersions(database, 1, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Delete(&models.Member{}, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := syncVersions(database, 2, removed); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Create(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := syncVersions(database, 3, restored); err != nil {
+		t.Fatal(err)
+	}
+
+	versions := entityVersions(t, database, "members", "1")
+	if len(versions) != 2 {
+		t.Fatalf("versions = %+v, want 2", versions)
+	}
+	assertPeriod(t, versions[0], &registered, &removed)
+	assertPeriod(t, versions[1], &restored, nil)
+	if versions[1].ImportRunID != 3 {
+		t.Errorf("restored version import run = %d, want 3", versions[1].ImportRunID)
+	}
+}
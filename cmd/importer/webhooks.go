@@ -0,0 +1,44 @@
+// cmd/importer/webhooks.go
+package main
+
+import (
+	"log"
+
+	dbConn "capital-view-api/db"
+	"capital-view-api/webhooks"
+
+	"gorm.io/gorm"
+)
+
+// notifyWebhooks создает уведомления по изменениям запуска runID для компаний из watchlist
+// и делает первую попытку доставки. Повторные попытки выполняет сервер (или deliver-webhooks).
+func notifyWebhooks(db *gorm.DB, runID uint) {
+	queued, err := webhooks.Enqueue(db, runID)
+	if err != nil {
+		log.Printf("ERROR queueing webhook deliveries for import run %d: %v", runID, err)
+		return
+	}
+	log.Printf("Queued %d webhook deliveries for import run %d.", queued, runID)
+	deliverWebhooks(db)
+}
+
+// runDeliverWebhooks - подкоманда "deliver-webhooks": отправляет доставки, которым пора
+// повторить попытку (для запуска по cron, если API сервер не работает).
+func runDeliverWebhooks() {
+	if err := dbConn.ConnectDatabase(); err != nil {
+		log.Fatalf("FATAL: Failed to connect to database: %v", err)
+	}
+	if err := dbConn.EnsureWebhooks(dbConn.DB); err != nil {
+		log.Fatalf("FATAL: AutoMigrate failed: %v", err)
+	}
+	deliverWebhooks(dbConn.DB)
+}
+
+func deliverWebhooks(db *gorm.DB) {
+	delivered, failed, err := webhooks.DeliverDue(db, webhooks.DefaultClient)
+	if err != nil {
+		log.Printf("ERROR delivering webhooks: %v", err)
+		return
+	}
+	log.Printf("Webhooks: %d delivered, %d failed permanently.", delivered, failed)
+}
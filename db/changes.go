@@ -0,0 +1,15 @@
+// db/changes.go
+package db
+
+import (
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+// EnsureChangeLog создает таблицы журнала изменений (import_runs, change_events) и версий
+// записей (entity_versions), чтобы /changes, /company/:regcode/history и ?as_of= работали
+// до первого запуска нового импортера.
+func EnsureChangeLog(database *gorm.DB) error {
+	return database.AutoMigrate(&models.ImportRun{}, &models.ChangeEvent{}, &models.EntityVersion{})
+}
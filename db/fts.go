@@ -0,0 +1,55 @@
+// db/fts.go
+package db
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// FTSEnabled - true, если таблица полнотекстового поиска registers_fts доступна.
+// FTS5 есть в SQLite только при сборке с тегом sqlite_fts5 (go build -tags sqlite_fts5),
+// без него поиск работает по-старому через LIKE.
+var FTSEnabled bool
+
+// registers_fts - внешний (content=) FTS5 индекс поверх таблицы registers:
+// хранит только токены, сами данные берутся из registers по rowid = registers.id.
+// unicode61 с remove_diacritics приводит "Lūsiņš" и "LŪSIŅŠ" к одному токену.
+const createRegistersFTS = `CREATE VIRTUAL TABLE IF NOT EXISTS registers_fts USING fts5(
+	name, name_in_quotes, without_quotes, address,
+	content='registers', content_rowid='id',
+	tokenize='unicode61 remove_diacritics 2'
+)`
+
+// EnsureRegistersFTS создает FTS таблицу, если ее еще нет, и заполняет ее при первом создании.
+// При отсутствии модуля FTS5 возвращает ошибку и оставляет FTSEnabled = false.
+func EnsureRegistersFTS(database *gorm.DB) error {
+	var existing int64
+	if err := database.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'registers_fts'").Scan(&existing).Error; err != nil {
+		return err
+	}
+
+	if err := database.Exec(createRegistersFTS).Error; err != nil {
+		FTSEnabled = false
+		return err
+	}
+	// IF NOT EXISTS не проверяет модуль, если таблица уже создана сборкой с FTS5 -
+	// пробный запрос падает с "no such module: fts5", когда модуля нет.
+	if err := database.Exec("SELECT rowid FROM registers_fts LIMIT 0").Error; err != nil {
+		FTSEnabled = false
+		return err
+	}
+	FTSEnabled = true
+
+	if existing == 0 {
+		log.Println("INFO: registers_fts created, building full-text index...")
+		return RebuildRegistersFTS(database)
+	}
+	return nil
+}
+
+// RebuildRegistersFTS перестраивает FTS индекс по текущему содержимому registers.
+// Вызывается импортером после загрузки register.csv.
+func RebuildRegistersFTS(database *gorm.DB) error {
+	return database.Exec("INSERT INTO registers_fts(registers_fts) VALUES('rebuild')").Error
+}
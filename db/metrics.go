@@ -0,0 +1,97 @@
+// db/metrics.go
+package db
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// EURAmountMultiplierSQL приводит суммы строк отчета к единицам евро (см. utils.EURMultiplierSQL).
+// Используется в запросах, где financial_statements соединена с таблицами строк отчета.
+var EURAmountMultiplierSQL = utils.EURMultiplierSQL("financial_statements.currency", "financial_statements.rounded_to_nearest")
+
+// DerivedMetricSQL - SQL-выражения производных показателей поверх financial_statements,
+// income_statements, balance_sheets и cash_flow_statements (имена как в /company/:regcode/ratios).
+// Числитель и знаменатель берутся из одного отчета, поэтому единицы и валюта на них не влияют.
+var DerivedMetricSQL = map[string]string{
+	"current_ratio":                RatioSQL("balance_sheets.total_current_assets", "balance_sheets.current_liabilities", false),
+	"debt_to_equity":               RatioSQL("(balance_sheets.current_liabilities + COALESCE(balance_sheets.non_current_liabilities, 0))", "balance_sheets.equity", true),
+	"return_on_assets":             RatioSQL("income_statements.net_income", "balance_sheets.total_assets", true),
+	"return_on_equity":             RatioSQL("income_statements.net_income", "balance_sheets.equity", true),
+	"net_margin":                   RatioSQL("income_statements.net_income", "income_statements.net_turnover", true),
+	"gross_margin":                 RatioSQL("income_statements.by_function_gross_profit", "income_statements.net_turnover", true),
+	"operating_cash_flow_coverage": RatioSQL("COALESCE(cash_flow_statements.cfo_dm_net_operating_cash_flow, cash_flow_statements.cfo_im_net_operating_cash_flow)", "balance_sheets.current_liabilities", false),
+	"equity_ratio":                 RatioSQL("balance_sheets.equity", "balance_sheets.total_assets", true),
+	"revenue_per_employee":         RatioSQL("(income_statements.net_turnover * "+EURAmountMultiplierSQL+")", "financial_statements.employees", true),
+}
+
+// RatioSQL - SQL-аналог handlers.ratio: NULL, если данных нет, знаменатель 0 или (при positiveDenominator) отрицательный
+func RatioSQL(numerator, denominator string, positiveDenominator bool) string {
+	condition := denominator + " <> 0"
+	if positiveDenominator {
+		condition = denominator + " > 0"
+	}
+	return fmt.Sprintf("(CASE WHEN %s THEN %s * 1.0 / %s END)", condition, numerator, denominator)
+}
+
+// sizeBandSQL - размерная группа по числу сотрудников (models.SizeBand*)
+var sizeBandSQL = fmt.Sprintf(`(CASE
+		WHEN financial_statements.employees IS NULL THEN NULL
+		WHEN financial_statements.employees < 10 THEN '%s'
+		WHEN financial_statements.employees < 50 THEN '%s'
+		WHEN financial_statements.employees < 250 THEN '%s'
+		ELSE '%s' END)`,
+	models.SizeBandMicro, models.SizeBandSmall, models.SizeBandMedium, models.SizeBandLarge)
+
+// EnsureCompanyMetrics создает таблицу company_metrics и заполняет ее, если она пуста
+// (например, база загружена импортером до появления сравнения с аналогами).
+func EnsureCompanyMetrics(database *gorm.DB) error {
+	if err := database.AutoMigrate(&models.CompanyMetric{}); err != nil {
+		return err
+	}
+	var count int64
+	if err := database.Model(&models.CompanyMetric{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		log.Println("INFO: company_metrics is empty, computing benchmark aggregates...")
+		return RefreshCompanyMetrics(database)
+	}
+	return nil
+}
+
+// RefreshCompanyMetrics пересчитывает company_metrics по текущим фин. отчетам.
+// Вызывается импортером после загрузки данных; старые значения заменяются в одной транзакции.
+func RefreshCompanyMetrics(database *gorm.DB) error {
+	insert := `INSERT INTO company_metrics (
+			regcode, year, statement_id, type, region, size_band,
+			net_turnover, net_margin, equity_ratio, employees, revenue_per_employee, refreshed_at)
+		SELECT
+			registers.regcode, financial_statements.year, financial_statements.id,
+			registers.type, registers.region, ` + sizeBandSQL + `,
+			(income_statements.net_turnover * ` + EURAmountMultiplierSQL + `),
+			` + DerivedMetricSQL["net_margin"] + `,
+			` + DerivedMetricSQL["equity_ratio"] + `,
+			financial_statements.employees * 1.0,
+			` + DerivedMetricSQL["revenue_per_employee"] + `,
+			?
+		FROM financial_statements
+		JOIN registers ON registers.regcode = financial_statements.legal_entity_registration_number
+		LEFT JOIN income_statements ON income_statements.statement_id = financial_statements.id
+		LEFT JOIN balance_sheets ON balance_sheets.statement_id = financial_statements.id
+		LEFT JOIN cash_flow_statements ON cash_flow_statements.statement_id = financial_statements.id
+		WHERE financial_statements.year IS NOT NULL`
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM company_metrics").Error; err != nil {
+			return err
+		}
+		return tx.Exec(insert, time.Now().UTC()).Error
+	})
+}
@@ -0,0 +1,34 @@
+// db/metrics_test.go
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"capital-view-api/db/postgrestest"
+)
+
+// TestRefreshCompanyMetricsPostgresSQL проверяет запрос пересчета company_metrics (с множителем
+// utils.EURMultiplierSQL и производными показателями) парсером PostgreSQL
+func TestRefreshCompanyMetricsPostgresSQL(t *testing.T) {
+	database, recorder := postgrestest.Open(t)
+	if err := RefreshCompanyMetrics(database); err != nil {
+		t.Fatalf("RefreshCompanyMetrics: %v", err)
+	}
+	if len(recorder.Statements) != 2 || !strings.HasPrefix(strings.TrimSpace(recorder.Statements[1]), "INSERT INTO company_metrics") {
+		t.Fatalf("statements = %q, want DELETE and INSERT INTO company_metrics", recorder.Statements)
+	}
+	postgrestest.Check(t, recorder.Statements)
+}
+
+func TestDerivedMetricSQLPostgres(t *testing.T) {
+	for name, expression := range DerivedMetricSQL {
+		statement := "SELECT " + expression + " FROM financial_statements " +
+			"LEFT JOIN income_statements ON income_statements.statement_id = financial_statements.id " +
+			"LEFT JOIN balance_sheets ON balance_sheets.statement_id = financial_statements.id " +
+			"LEFT JOIN cash_flow_statements ON cash_flow_statements.statement_id = financial_statements.id"
+		for _, problem := range postgrestest.Problems(statement) {
+			t.Errorf("%s: %s", name, problem)
+		}
+	}
+}
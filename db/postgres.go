@@ -0,0 +1,49 @@
+// db/postgres.go
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// trigramIndexes - GIN индексы pg_trgm для поиска LIKE '%слово%' (findRegcodesLike, SearchPersons).
+// В SQLite их роль выполняет FTS5 (registers_fts), поиск лиц там идет полным перебором.
+var trigramIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_registers_name_folded_trgm ON registers USING gin (name_folded gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_members_name_folded_trgm ON members USING gin (name_folded gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_owners_name_folded_trgm ON beneficial_owners USING gin (name_folded gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_officers_name_folded_trgm ON officers USING gin (name_folded gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_members_identity_trgm ON members USING gin (latvian_identity_number_masked gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_owners_identity_trgm ON beneficial_owners USING gin (latvian_identity_number_masked gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_officers_identity_trgm ON officers USING gin (latvian_identity_number_masked gin_trgm_ops)",
+}
+
+// EnsureTrigramIndexes подключает расширение pg_trgm и создает индексы для поиска по подстроке.
+// Только для PostgreSQL; CREATE EXTENSION требует прав владельца БД (или заранее созданного расширения).
+func EnsureTrigramIndexes(database *gorm.DB) error {
+	if !IsPostgres(database) {
+		return nil
+	}
+	if err := database.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return fmt.Errorf("pg_trgm extension: %w", err)
+	}
+	for _, command := range trigramIndexes {
+		if err := database.Exec(command).Error; err != nil {
+			return fmt.Errorf("%s: %w", command, err)
+		}
+	}
+	return nil
+}
+
+// SyncIDSequence сдвигает последовательность id таблицы за максимальный id. Импортер вставляет
+// id из CSV явно, и в PostgreSQL последовательность при этом не двигается - следующая вставка
+// без id получила бы уже занятое значение. В SQLite ничего не делает.
+func SyncIDSequence(database *gorm.DB, table string) error {
+	if !IsPostgres(database) {
+		return nil
+	}
+	return database.Exec(fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE((SELECT MAX(id) FROM %s), 0) + 1, false)",
+		table, table)).Error
+}
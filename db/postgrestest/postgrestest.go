@@ -0,0 +1,311 @@
+// db/postgrestest/postgrestest.go
+
+// Package postgrestest проверяет SQL для PostgreSQL без сервера: запросы строятся диалектом
+// postgres, записываются и разбираются парсером PostgreSQL (libpg_query).
+// Ловит синтаксис, функции SQLite (strftime, ifnull, ...) и столбцы вне GROUP BY, которые
+// SQLite допускает, а PostgreSQL нет. Типы и существование таблиц не проверяются.
+// Пакет только для тестов.
+package postgrestest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	pg_query "github.com/pganalyze/pg_query_go/v6"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Recorder - логгер gorm, который запоминает SQL с подставленными значениями
+type Recorder struct {
+	logger.Interface
+	Statements []string
+	// Result - строки результата запроса (SQL с $1, $2...); nil или пустой columns - ноль строк
+	Result func(query string) (columns []string, rows [][]driver.Value)
+}
+
+func (r *Recorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	statement, _ := fc()
+	r.Statements = append(r.Statements, statement)
+}
+
+// Open возвращает gorm с диалектом postgres поверх соединения, которое ничего не выполняет:
+// запросы возвращают пустой результат (или Recorder.Result), а их SQL записывается в Recorder. В отличие от DryRun
+// код выполняется до конца (пустые Scan и Count не прерывают его после первого запроса).
+func Open(t testing.TB) (*gorm.DB, *Recorder) {
+	t.Helper()
+	recorder := &Recorder{Interface: logger.Discard}
+	conn := sql.OpenDB(connector{recorder})
+	database, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               recorder,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return database, recorder
+}
+
+// connector, emptyConn, emptyTx и resultRows - драйвер database/sql без сервера:
+// запрос возвращает строки из Recorder.Result (по умолчанию ноль), команда - ноль измененных строк
+type connector struct{ recorder *Recorder }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return emptyConn(c), nil }
+func (connector) Driver() driver.Driver                          { return nil }
+
+type emptyConn connector
+
+func (emptyConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("postgrestest: prepared statements are not supported")
+}
+func (emptyConn) Close() error              { return nil }
+func (emptyConn) Begin() (driver.Tx, error) { return emptyTx{}, nil }
+
+func (c emptyConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows := &resultRows{}
+	if c.recorder.Result != nil {
+		rows.columns, rows.rows = c.recorder.Result(query)
+	}
+	return rows, nil
+}
+
+func (emptyConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+type emptyTx struct{}
+
+func (emptyTx) Commit() error   { return nil }
+func (emptyTx) Rollback() error { return nil }
+
+// resultRows - строки из Recorder.Result
+type resultRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *resultRows) Columns() []string { return r.columns }
+func (r *resultRows) Close() error      { return nil }
+
+func (r *resultRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// functions - функции PostgreSQL, которые встречаются в запросах API (остальные считаются ошибкой,
+// чтобы функции SQLite не попали в запросы незамеченными; при необходимости список дополняется)
+var functions = map[string]bool{
+	"abs": true, "avg": true, "count": true, "greatest": true, "least": true, "length": true,
+	"lower": true, "max": true, "min": true, "nullif": true, "pg_get_serial_sequence": true,
+	"round": true, "setval": true, "string_agg": true, "substr": true, "sum": true, "upper": true,
+}
+
+// aggregates - агрегатные функции из functions
+var aggregates = map[string]bool{"avg": true, "count": true, "max": true, "min": true, "string_agg": true, "sum": true}
+
+// Check разбирает записанные запросы парсером PostgreSQL и сообщает об ошибках через t.Errorf
+func Check(t testing.TB, statements []string) {
+	t.Helper()
+	if len(statements) == 0 {
+		t.Error("no SQL recorded")
+	}
+	for _, statement := range statements {
+		for _, problem := range Problems(statement) {
+			t.Errorf("%s\nSQL: %s", problem, statement)
+		}
+	}
+}
+
+// Problems - ошибки запроса для PostgreSQL (пусто - запрос корректен)
+func Problems(statement string) []string {
+	tree, err := pg_query.Parse(statement)
+	if err != nil {
+		return []string{"PostgreSQL syntax error: " + err.Error()}
+	}
+	var problems []string
+	for _, raw := range tree.GetStmts() {
+		walk(raw.GetStmt().ProtoReflect(), func(message protoreflect.Message) bool {
+			switch node := message.Interface().(type) {
+			case *pg_query.FuncCall:
+				if name, builtin := functionName(node); !builtin && !functions[name] {
+					problems = append(problems, "function "+name+" is not known to exist in PostgreSQL")
+				}
+			case *pg_query.SelectStmt:
+				problems = append(problems, ungrouped(node)...)
+			}
+			return true
+		})
+	}
+	return problems
+}
+
+// walk обходит дерево разбора в глубину; visit возвращает false, чтобы не заходить внутрь узла
+func walk(message protoreflect.Message, visit func(protoreflect.Message) bool) {
+	if !visit(message) {
+		return
+	}
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.Message() == nil || field.IsMap() {
+			return true
+		}
+		if field.IsList() {
+			for i := 0; i < value.List().Len(); i++ {
+				walk(value.List().Get(i).Message(), visit)
+			}
+			return true
+		}
+		walk(value.Message(), visit)
+		return true
+	})
+}
+
+// functionName - имя функции; builtin - имя из pg_catalog (так парсер записывает TRIM, EXTRACT и т.п.)
+func functionName(call *pg_query.FuncCall) (name string, builtin bool) {
+	var parts []string
+	for _, part := range call.GetFuncname() {
+		parts = append(parts, strings.ToLower(part.GetString_().GetSval()))
+	}
+	if len(parts) == 0 {
+		return "", false
+	}
+	return parts[len(parts)-1], len(parts) > 1 && parts[0] == "pg_catalog"
+}
+
+// ungrouped находит столбцы в SELECT, HAVING и ORDER BY запроса с GROUP BY (или с агрегатами),
+// которые не входят в GROUP BY и не стоят внутри агрегатной функции
+func ungrouped(stmt *pg_query.SelectStmt) []string {
+	grouped := make(map[string]bool)
+	for _, node := range stmt.GetGroupClause() {
+		column := columnName(node)
+		if column == "" {
+			return nil // GROUP BY по выражению: сравнивать выражения не умеем
+		}
+		grouped[column] = true
+	}
+	hasAggregate := false
+	for _, target := range stmt.GetTargetList() {
+		if containsAggregate(target.GetResTarget().GetVal()) {
+			hasAggregate = true
+		}
+	}
+	if len(grouped) == 0 && !hasAggregate {
+		return nil
+	}
+
+	aliases := make(map[string]bool)
+	for _, target := range stmt.GetTargetList() {
+		if alias := target.GetResTarget().GetName(); alias != "" {
+			aliases[alias] = true
+		}
+	}
+	isGrouped := func(column string) bool {
+		if grouped[column] {
+			return true
+		}
+		// registers.regcode и regcode - один столбец, если одно из имен без таблицы
+		short := column[strings.LastIndexByte(column, '.')+1:]
+		for name := range grouped {
+			if name == short || (!strings.Contains(column, ".") && strings.HasSuffix(name, "."+column)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var problems []string
+	check := func(node *pg_query.Node, clause string, allowAlias bool) {
+		for _, column := range ungroupedColumns(node) {
+			if isGrouped(column) || (allowAlias && aliases[column]) {
+				continue
+			}
+			problems = append(problems, "column "+column+" in "+clause+" must appear in GROUP BY or be used in an aggregate function")
+		}
+	}
+	for _, target := range stmt.GetTargetList() {
+		if alias := target.GetResTarget().GetName(); alias != "" && grouped[alias] {
+			continue // GROUP BY по псевдониму выражения
+		}
+		check(target.GetResTarget().GetVal(), "SELECT", false)
+	}
+	check(stmt.GetHavingClause(), "HAVING", false)
+	for _, sort := range stmt.GetSortClause() {
+		check(sort.GetSortBy().GetNode(), "ORDER BY", true)
+	}
+	return problems
+}
+
+// columnName - имя столбца (таблица.столбец) для ColumnRef, иначе пусто
+func columnName(node *pg_query.Node) string {
+	ref := node.GetColumnRef()
+	if ref == nil {
+		return ""
+	}
+	var parts []string
+	for _, field := range ref.GetFields() {
+		if field.GetAStar() != nil {
+			return ""
+		}
+		parts = append(parts, field.GetString_().GetSval())
+	}
+	return strings.Join(parts, ".")
+}
+
+// ungroupedColumns - столбцы выражения вне агрегатных функций и подзапросов
+func ungroupedColumns(node *pg_query.Node) []string {
+	var columns []string
+	if node == nil {
+		return nil
+	}
+	walk(node.ProtoReflect(), func(message protoreflect.Message) bool {
+		switch value := message.Interface().(type) {
+		case *pg_query.FuncCall:
+			return !isAggregate(value)
+		case *pg_query.SubLink:
+			return false
+		case *pg_query.Node:
+			if column := columnName(value); column != "" {
+				columns = append(columns, column)
+				return false
+			}
+		}
+		return true
+	})
+	return columns
+}
+
+// containsAggregate - есть ли в выражении агрегатная функция (вне подзапросов)
+func containsAggregate(node *pg_query.Node) bool {
+	found := false
+	if node == nil {
+		return false
+	}
+	walk(node.ProtoReflect(), func(message protoreflect.Message) bool {
+		switch value := message.Interface().(type) {
+		case *pg_query.FuncCall:
+			found = found || isAggregate(value)
+		case *pg_query.SubLink:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// isAggregate - агрегатная функция (не оконная)
+func isAggregate(call *pg_query.FuncCall) bool {
+	name, _ := functionName(call)
+	return aggregates[name] && call.GetOver() == nil
+}
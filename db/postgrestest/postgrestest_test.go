@@ -0,0 +1,62 @@
+// db/postgrestest/postgrestest_test.go
+package postgrestest
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string // Часть сообщения об ошибке; пусто - запрос корректен
+	}{
+		{"valid", "SELECT registers.regcode, COUNT(*) FROM registers GROUP BY registers.regcode HAVING COUNT(*) > 1 ORDER BY registers.regcode", ""},
+		{"short and qualified names", "SELECT regcode, COUNT(*) AS companies FROM registers GROUP BY registers.regcode ORDER BY companies DESC", ""},
+		{"group by alias", "SELECT COALESCE(person_id, id) AS person_key, MIN(name) FROM members GROUP BY person_key ORDER BY MIN(name), person_key", ""},
+		{"correlated subquery", "SELECT registers.regcode, (SELECT MAX(fs.year) FROM financial_statements fs WHERE fs.legal_entity_registration_number = registers.regcode) FROM registers", ""},
+		{"pg_catalog function", "SELECT UPPER(TRIM(currency)) FROM financial_statements", ""},
+		{"syntax", "SELEC regcode FROM registers", "syntax error"},
+		{"double quoted string", "SELECT regcode FROM registers WHERE closed = \"\"", "syntax error"},
+		{"sqlite function", "SELECT strftime('%Y', registered) FROM registers", "function strftime"},
+		{"ifnull", "SELECT IFNULL(name, '') FROM registers", "function ifnull"},
+		{"ungrouped select", "SELECT registers.regcode, registers.address, COUNT(*) FROM registers GROUP BY registers.regcode", "column registers.address in SELECT"},
+		{"aggregate without group by", "SELECT regcode, COUNT(*) FROM registers", "column regcode in SELECT"},
+		{"ungrouped order by", "SELECT addressid, COUNT(*) FROM registers GROUP BY addressid ORDER BY name", "column name in ORDER BY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := Problems(tt.sql)
+			if tt.want == "" {
+				if len(problems) != 0 {
+					t.Errorf("Problems = %q, want none", problems)
+				}
+				return
+			}
+			if len(problems) == 0 || !strings.Contains(strings.Join(problems, "\n"), tt.want) {
+				t.Errorf("Problems = %q, want %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenRecordsTransactions(t *testing.T) {
+	database, recorder := Open(t)
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM company_metrics").Error; err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO company_metrics (regcode) SELECT regcode FROM registers WHERE regcode = ?", "40000000001").Error
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"DELETE FROM company_metrics", "INSERT INTO company_metrics (regcode) SELECT regcode FROM registers WHERE regcode = '40000000001'"}
+	if len(recorder.Statements) != len(want) || recorder.Statements[0] != want[0] || recorder.Statements[1] != want[1] {
+		t.Errorf("statements = %q, want %q", recorder.Statements, want)
+	}
+	Check(t, recorder.Statements)
+}
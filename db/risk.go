@@ -0,0 +1,29 @@
+// db/risk.go
+package db
+
+import (
+	"log"
+
+	"capital-view-api/models"
+	"capital-view-api/risk"
+
+	"gorm.io/gorm"
+)
+
+// EnsureCompanyRisks создает таблицы оценки риска и заполняет их, если они пусты
+// (например, база загружена импортером до появления оценки риска).
+func EnsureCompanyRisks(database *gorm.DB, rules risk.Rules) error {
+	if err := database.AutoMigrate(&models.CompanyRisk{}, &models.CompanyRiskFlag{}); err != nil {
+		return err
+	}
+	var count int64
+	if err := database.Model(&models.CompanyRisk{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		log.Println("INFO: company_risks is empty, evaluating risk rules...")
+		_, err := risk.Refresh(database, rules)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+// db/screening.go
+package db
+
+import (
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+// EnsureScreening создает таблицы списков санкций/PEP, их записей и найденных совпадений
+func EnsureScreening(database *gorm.DB) error {
+	return database.AutoMigrate(&models.ScreeningList{}, &models.ScreeningEntry{}, &models.ScreeningMatch{})
+}
@@ -0,0 +1,13 @@
+// db/webhooks.go
+package db
+
+import (
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+// EnsureWebhooks создает таблицы watchlist, подписок и журнала доставок вебхуков
+func EnsureWebhooks(database *gorm.DB) error {
+	return database.AutoMigrate(&models.WatchlistEntry{}, &models.WebhookSubscription{}, &models.WebhookDelivery{})
+}
@@ -24,9 +24,233 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/addresses/hotspots": {
+            "get": {
+                "description": "Рейтинг адресов регистрации (Addressid) по числу действующих компаний. Для каждого адреса возвращает число действующих и всех компаний, разбивку действующих по типам, даты первой и последней регистрации, разброс в днях и число регистраций за последний год. Адреса, где зарегистрированы сотни компаний, - типичный признак фирм-однодневок. Фильтры компаний (type, region, city, registered_from, registered_to и др.) применяются и к рейтингу, и к сводке.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "Адреса массовой регистрации",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 2,
+                        "description": "Минимальное число действующих компаний по адресу",
+                        "name": "min_companies",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по типу компании, например SIA,AS",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код региона (несколько через запятую)",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код города (несколько через запятую)",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Адреса по убыванию числа действующих компаний",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.AddressSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный min_companies или фильтр",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/addresses/{addressid}/companies": {
+            "get": {
+                "description": "Возвращает сводку по адресу (число действующих и всех компаний, разбивка действующих по типам, даты первой и последней регистрации и разброс в днях) и страницу компаний, зарегистрированных по этому Addressid, начиная с последних зарегистрированных. Сводка считается по всем компаниям адреса, фильтры (status, type, registered_from и др.) применяются только к списку.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "Компании по адресу регистрации",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Addressid адреса",
+                        "name": "addressid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "terminated",
+                            "closed"
+                        ],
+                        "type": "string",
+                        "description": "Статус компании",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по типу компании, например SIA,AS",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сводка и компании по адресу",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.AddressCompanies"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "companies": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "data": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/models.AddressCompany"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Addressid или фильтр",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "По адресу нет компаний",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/beneficial-owners/by-regcode/{regcode}": {
             "get": {
-                "description": "Возвращает пагинированный список бенефициаров (beneficial owners) для указанной компании.",
+                "description": "Возвращает пагинированный список бенефициаров (beneficial owners) для указанной компании. С as_of - бенефициаров на эту дату по версиям записей (entity_versions), которые ведет импортер.",
                 "produces": [
                     "application/json"
                 ],
@@ -42,6 +266,12 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
                     {
                         "minimum": 1,
                         "type": "integer",
@@ -83,7 +313,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Неверный Regcode",
+                        "description": "Неверный Regcode или as_of",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
@@ -322,70 +552,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/company/{regcode}": {
+        "/changes": {
             "get": {
-                "description": "Получает детальную информацию о компании, включая участников, бенефициаров и фин. отчеты, по её точному Regcode.",
+                "description": "Глобальная лента изменений, обнаруженных импортером, в порядке записи (по возрастанию id). Для инкрементальной синхронизации передавайте since_id = id последнего полученного события.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "company"
+                    "changes"
                 ],
-                "summary": "Получить полную информацию о компании по Regcode",
+                "summary": "Лента изменений",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Regcode компании",
-                        "name": "regcode",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Полная информация о компании (с вложенными данными)",
-                        "schema": {
-                            "$ref": "#/definitions/models.Registers"
-                        }
+                        "description": "Только изменения начиная с даты (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "since",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Неверный или отсутствующий Regcode",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HTTPError"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "Только события с id больше указанного",
+                        "name": "since_id",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Компания не найдена",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HTTPError"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "Только изменения одного запуска импортера",
+                        "name": "run_id",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HTTPError"
-                        }
-                    }
-                }
-            }
-        },
-        "/financial-statements/by-regcode/{regcode}": {
-            "get": {
-                "description": "Возвращает пагинированный список фин. отчетов (financial statements) для указанной компании.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "financial_statement"
-                ],
-                "summary": "Получить фин. отчеты компании по Regcode",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Regcode компании",
-                        "name": "regcode",
-                        "in": "path",
-                        "required": true
+                        "description": "Таблица: registers, members, beneficial_owners, officers, financial_statements, ...",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "insert",
+                            "update",
+                            "delete"
+                        ],
+                        "type": "string",
+                        "description": "Тип изменения",
+                        "name": "operation",
+                        "in": "query"
                     },
                     {
                         "minimum": 1,
@@ -407,7 +618,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пагинированный список фин. отчетов",
+                        "description": "Пагинированная лента изменений",
                         "schema": {
                             "allOf": [
                                 {
@@ -419,7 +630,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.FinancialStatement"
+                                                "$ref": "#/definitions/models.ChangeEvent"
                                             }
                                         }
                                     }
@@ -428,7 +639,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Неверный Regcode",
+                        "description": "Неверные параметры фильтра",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
@@ -442,72 +653,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/income-statements": {
+        "/company/{regcode}": {
             "get": {
-                "description": "Retrieve a list of all income statement records",
+                "description": "Получает детальную информацию о компании, включая участников, бенефициаров, должностных лиц и фин. отчеты, по её точному Regcode. С as_of компания восстанавливается на дату: реквизиты, участники, бенефициары и должностные лица - по версиям записей, фин. отчеты - за годы, закончившиеся не позже нее.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "income-statements"
+                    "company"
+                ],
+                "summary": "Получить полную информацию о компании по Regcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "eur"
+                        ],
+                        "type": "string",
+                        "description": "eur - привести суммы отчетов к единицам евро (rounded_to_nearest и LVL по курсу 0.702804)",
+                        "name": "normalize",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all income statement entries",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Полная информация о компании (с вложенными данными)",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.IncomeStatement"
-                            }
+                            "$ref": "#/definitions/models.Registers"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Неверный или отсутствующий Regcode, неверный normalize или as_of",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "Add a new income statement record to the database",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "income-statements"
-                ],
-                "summary": "Create a new income statement entry",
-                "parameters": [
-                    {
-                        "description": "Income Statement data to create",
-                        "name": "incomeStatement",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.IncomeStatement"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.IncomeStatement"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request - Invalid input data",
+                    "404": {
+                        "description": "Компания не найдена (или не существовала на дату as_of)",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
@@ -515,151 +715,183 @@ const docTemplate = `{
                 }
             }
         },
-        "/income-statements/{id}": {
+        "/company/{regcode}/benchmark": {
             "get": {
-                "description": "Retrieve details of a specific income statement record using its ID",
+                "description": "Показывает процентильный ранг выручки, чистой маржи, доли собственного капитала, числа сотрудников и выручки на сотрудника среди компаний того же года с тем же типом, регионом и/или размерной группой по числу сотрудников (micro 0-9, small 10-49, medium 50-249, large 250+). Считается по таблице company_metrics, которая пересчитывается после каждого запуска импортера; суммы в единицах евро.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "income-statements"
+                    "company"
                 ],
-                "summary": "Get a single income statement entry by ID",
+                "summary": "Сравнение компании с аналогами",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Income Statement ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год отчетности (по умолчанию последний)",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "type",
+                        "description": "Признаки группы аналогов через запятую: type, region, size",
+                        "name": "peer",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Положение компании в группе аналогов",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeStatement"
+                            "$ref": "#/definitions/models.CompanyBenchmark"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - Invalid ID format",
+                        "description": "Неверный Regcode, год или признак группы",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "404": {
-                        "description": "Not Found - Income Statement not found",
+                        "description": "Нет фин. отчета компании за год",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Modify the details of an existing income statement record by ID",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/company/{regcode}/effective-owners": {
+            "get": {
+                "description": "Обходит цепочки участников-юр. лиц вверх на глубину depth и для каждого конечного владельца (физ. лица, юр. лица без известных участников или на границе глубины) суммирует по всем цепочкам произведение долей: лицо с 50% компании, владеющей 60% данной, получает 30%. Цепочки, замыкающиеся в цикл, не учитываются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "income-statements"
+                    "ownership"
                 ],
-                "summary": "Update an existing income statement entry",
+                "summary": "Конечные владельцы компании с косвенными долями",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Income Statement ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Income Statement data to update",
-                        "name": "incomeStatement",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.IncomeStatement"
-                        }
+                        "maximum": 10,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Глубина обхода",
+                        "name": "depth",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Конечные владельцы по убыванию доли",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeStatement"
+                            "$ref": "#/definitions/models.EffectiveOwnership"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - Invalid ID format or input data",
+                        "description": "Неверный Regcode или depth",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "404": {
-                        "description": "Not Found - Income Statement not found",
+                        "description": "Компания не найдена",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove an income statement record from the database using its ID",
+            }
+        },
+        "/company/{regcode}/graph": {
+            "get": {
+                "description": "Строит подграф вокруг компании: по связям участников-юр. лиц в обе стороны на depth шагов, с бенефициарами и должностными лицами каждой раскрытой компании. Узлы - компании (company), физ. лица (person) и юр. лица без regcode (entity); ребра направлены от лица к компании (member, beneficial_owner, officer). Вес ребра участника - его доля в капитале, %. Для Graphviz (dot), Gephi и других инструментов; то же делает команда импортера export-graph.",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "income-statements"
+                    "ownership"
                 ],
-                "summary": "Delete an income statement entry by ID",
+                "summary": "Выгрузка сети связей компании (DOT, GraphML, GEXF)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Income Statement ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "dot",
+                            "graphml",
+                            "gexf"
+                        ],
+                        "type": "string",
+                        "default": "graphml",
+                        "description": "Формат выгрузки",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 5,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 2,
+                        "description": "Число шагов по компаниям",
+                        "name": "depth",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success message",
+                        "description": "Граф в выбранном формате",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Bad Request - Invalid ID format",
+                        "description": "Неверный Regcode, формат или depth",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "404": {
-                        "description": "Not Found - Income Statement not found",
+                        "description": "Компания не найдена",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
@@ -667,16 +899,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/members/by-regcode/{regcode}": {
+        "/company/{regcode}/history": {
             "get": {
-                "description": "Возвращает пагинированный список участников (members) для указанной компании.",
+                "description": "Возвращает изменения, обнаруженные импортером по компании и связанным с ней записям (участники, бенефициары, должностные лица, фин. отчеты), от новых к старым. Для update одно событие соответствует одному полю.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "member"
+                    "company"
                 ],
-                "summary": "Получить участников компании по Regcode",
+                "summary": "История изменений компании",
                 "parameters": [
                     {
                         "type": "string",
@@ -685,6 +917,18 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Только изменения начиная с даты (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Таблица: registers, members, beneficial_owners, officers, financial_statements, ...",
+                        "name": "entity",
+                        "in": "query"
+                    },
                     {
                         "minimum": 1,
                         "type": "integer",
@@ -705,7 +949,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пагинированный список участников",
+                        "description": "Пагинированный список изменений",
                         "schema": {
                             "allOf": [
                                 {
@@ -717,7 +961,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.Member"
+                                                "$ref": "#/definitions/models.ChangeEvent"
                                             }
                                         }
                                     }
@@ -726,7 +970,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Неверный Regcode",
+                        "description": "Неверный Regcode или since",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
@@ -740,64 +984,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/register/{regcode}": {
+        "/company/{regcode}/holdings": {
             "get": {
-                "description": "Получает детальную информацию о компании по её Regcode.",
+                "description": "Возвращает пагинированный список компаний, доли которых держит компания: записи members с legal_entity_registration_number = regcode. Название и статус - из реестра (если компания в нем есть). ownership_percent - доля в уставном капитале компании, посчитанная по всем ее участникам.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "register"
+                    "company"
                 ],
-                "summary": "Получить информацию о компании по Regcode",
+                "summary": "Доли компании в других компаниях",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Regcode компании",
+                        "description": "Regcode компании-участника",
                         "name": "regcode",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Информация о компании",
-                        "schema": {
-                            "$ref": "#/definitions/models.Registers"
-                        }
-                    },
-                    "400": {
-                        "description": "Неверный Regcode",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HTTPError"
-                        }
-                    },
-                    "404": {
-                        "description": "Компания не найдена",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HTTPError"
-                        }
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HTTPError"
-                        }
-                    }
-                }
-            }
-        },
-        "/registers": {
-            "get": {
-                "description": "Возвращает пагинированный список записей из таблицы registers.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "register"
-                ],
-                "summary": "Получить список всех записей регистра",
-                "parameters": [
                     {
                         "minimum": 1,
                         "type": "integer",
@@ -818,7 +1022,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пагинированный список записей",
+                        "description": "Пагинированный список долей",
                         "schema": {
                             "allOf": [
                                 {
@@ -830,7 +1034,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.Registers"
+                                                "$ref": "#/definitions/models.Holding"
                                             }
                                         }
                                     }
@@ -838,6 +1042,12 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Неверный Regcode",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -847,66 +1057,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/search/detailed": {
+        "/company/{regcode}/ownership-tree": {
             "get": {
-                "description": "Ищет компании **только** по полям таблицы регистра (Regcode, SEPA, Name). Возвращает пагинированный список с базовой информацией. // \u003c-- Описание изменено",
+                "description": "Рекурсивно обходит участников (members) компании вверх: участники-юр. лица раскрываются до их собственных участников на глубину depth. Узел, который уже встречается выше по пути, помечается cycle = true и не раскрывается, а цикл добавляется в cycles.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "search"
+                    "ownership"
                 ],
-                "summary": "Упрощенный поиск компаний (с пагинацией)",
+                "summary": "Дерево владельцев компании",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Поисковый запрос (Regcode, SEPA, Name)",
-                        "name": "q",
-                        "in": "query",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
                         "required": true
                     },
                     {
+                        "maximum": 10,
                         "minimum": 1,
                         "type": "integer",
-                        "default": 1,
-                        "description": "Номер страницы",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Записей на странице",
-                        "name": "limit",
+                        "default": 3,
+                        "description": "Глубина обхода",
+                        "name": "depth",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пагинированный список базовой информации о компаниях",
+                        "description": "Дерево владельцев",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/models.PaginatedResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/models.SimpleRegisterInfo"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/models.OwnershipTree"
                         }
                     },
                     "400": {
-                        "description": "Неверный запрос (отсутствует 'q')",
+                        "description": "Неверный Regcode или depth",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
@@ -919,577 +1112,3987 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.HTTPError": {
-            "type": "object",
+        },
+        "/company/{regcode}/ratios": {
+            "get": {
+                "description": "Считает по каждому фин. отчету компании: current ratio, debt-to-equity, ROA, ROE, чистую и валовую маржу и покрытие краткосрочных обязательств операционным денежным потоком. Суммы приводятся к единицам валюты по rounded_to_nearest; числитель и знаменатель всегда из одного отчета, поэтому валюта на коэффициенты не влияет. Если данных нет или знаменатель 0 (или отрицательный там, где это лишает коэффициент смысла), value = null, а status объясняет причину.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "company"
+                ],
+                "summary": "Финансовые коэффициенты компании по годам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Коэффициенты по годам (по возрастанию)",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CompanyRatiosYear"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{regcode}/risk": {
+            "get": {
+                "description": "Возвращает сработавшие для компании правила риска с объяснениями и итоговую оценку. score - сумма весов сработавших правил (не больше 100), level - уровень по порогам из файла правил. Правила: negative_equity (отрицательный собственный капитал), zero_assets_with_liabilities (нулевые активы при наличии обязательств), no_recent_statements (нет отчетности за последние годы), frequent_beneficial_owner_changes (частая смена бенефициаров), shared_address (адрес, по которому зарегистрировано много компаний), circular_ownership (циклическое владение). Правила, веса и пороги задаются в файле RISK_RULES_PATH (по умолчанию risk_rules.json), оценка пересчитывается после каждого импорта. Компания без флагов возвращается со score 0 и level low.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Флаги риска и оценка компании",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Оценка и флаги по убыванию веса",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanyRiskReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{regcode}/screening": {
+            "get": {
+                "description": "Возвращает совпадения названия компании и имен ее участников, бенефициаров и должностных лиц с загруженными списками санкций и PEP. Списки загружает импортер (load-screening-lists), скрининг выполняется после каждого импорта. score - сходство имен от 0 до 1 без учета порядка слов, регистра, диакритики и организационно-правовой формы; matched_field и matched_value - что именно совпало; birth_date_match - совпал ли год рождения. Сохраняются совпадения со score не ниже 0.85.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screening"
+                ],
+                "summary": "Совпадения компании со списками санкций и PEP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.85,
+                        "description": "Минимальный score (0-1)",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sanctions",
+                            "pep"
+                        ],
+                        "type": "string",
+                        "description": "Только списки категории",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Совпадения по убыванию score",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanyScreening"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode, min_score или category",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{regcode}/shareholders": {
+            "get": {
+                "description": "Возвращает пагинированный список тех, кто держит доли компании: записи members с at_legal_entity_registration_number = regcode. ownership_percent - доля участника в уставном капитале (число акций * номинал, LVL пересчитывается в EUR, если валюты участников различаются). С as_of - участники на эту дату по версиям записей (entity_versions).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "company"
+                ],
+                "summary": "Участники (владельцы долей) компании",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список участников",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Member"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode или as_of",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{regcode}/subsidiaries": {
+            "get": {
+                "description": "Рекурсивно обходит компании, в которых компания является участником (members.legal_entity_registration_number), и их дочерние компании на глубину depth. Циклы обрабатываются так же, как в ownership-tree.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ownership"
+                ],
+                "summary": "Дерево дочерних компаний",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 10,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Глубина обхода",
+                        "name": "depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Дерево дочерних компаний",
+                        "schema": {
+                            "$ref": "#/definitions/models.OwnershipTree"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode или depth",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{regcode}/timeseries": {
+            "get": {
+                "description": "Возвращает по одному ряду на каждое поле из ?fields= (json-имена строк IncomeStatement, BalanceSheet, CashFlowStatement и employees) по всем годам отчетности компании. Все ряды выровнены по общей оси лет; для каждой точки - абсолютное и процентное изменение к предыдущему году (только если у ряда есть значение за год year-1), для ряда - CAGR между первым и последним значением. change_pct и CAGR всегда считаются по суммам в евро, поэтому переход с LVL на EUR или с THOUSANDS на ONES их не искажает; абсолютное change без ?normalize=eur - в единицах отчета и null, если единицы двух лет различаются. С ?normalize=eur сами суммы приводятся к единицам евро.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "company"
+                ],
+                "summary": "Временные ряды строк фин. отчетов компании",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "net_turnover,net_income,total_assets,equity,employees",
+                        "description": "Поля через запятую",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "eur"
+                        ],
+                        "type": "string",
+                        "description": "eur - привести суммы к единицам евро",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ряды по годам",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompanyTimeSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode, неизвестное поле или неверный normalize",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/company/{regcode}/ubo-analysis": {
+            "get": {
+                "description": "Обходит цепочку участников вверх до физ. лиц (как effective-owners) и отбирает тех, чья косвенная доля не меньше порога (по умолчанию 25%). Сравнивает их с заявленными бенефициарами (beneficial_owners) и сообщает о расхождениях: undeclared_owner - вероятный бенефициар не заявлен; declared_without_ownership - заявленный бенефициар не найден среди владельцев; no_declaration - у компании (или у юр. лица в ее цепочке) нет заявленных бенефициаров; unresolved_owner - юр. лицо с долей не меньше порога, чьи участники неизвестны. Лица сопоставляются по person_id, иначе по маскированному коду и имени без учета порядка слов. Бенефициар может контролировать компанию и без доли, поэтому declared_without_ownership - повод для проверки, а не нарушение.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ownership"
+                ],
+                "summary": "Анализ конечных бенефициаров (UBO)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 10,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Глубина обхода",
+                        "name": "depth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 25,
+                        "description": "Порог косвенной доли, %",
+                        "name": "threshold",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вероятные и заявленные бенефициары, расхождения",
+                        "schema": {
+                            "$ref": "#/definitions/models.UBOAnalysis"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode, depth или threshold",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/financial-statements/by-regcode/{regcode}": {
+            "get": {
+                "description": "Возвращает пагинированный список фин. отчетов (financial statements) для указанной компании. С ?normalize=eur отчеты возвращаются вместе с IncomeStatement, BalanceSheet и CashFlowStatement, а их суммы приводятся к единицам евро; без него - только сами отчеты, суммы в единицах и валюте отчета (rounded_to_nearest, currency).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "financial_statement"
+                ],
+                "summary": "Получить фин. отчеты компании по Regcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "eur"
+                        ],
+                        "type": "string",
+                        "description": "eur - привести суммы отчетов к единицам евро (rounded_to_nearest и LVL по курсу 0.702804)",
+                        "name": "normalize",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список фин. отчетов",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FinancialStatement"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode или normalize",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/income-statements": {
+            "get": {
+                "description": "Retrieve a list of all income statement records",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "income-statements"
+                ],
+                "summary": "Get all income statement entries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.IncomeStatement"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new income statement record to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "income-statements"
+                ],
+                "summary": "Create a new income statement entry",
+                "parameters": [
+                    {
+                        "description": "Income Statement data to create",
+                        "name": "incomeStatement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IncomeStatement"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.IncomeStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/income-statements/{id}": {
+            "get": {
+                "description": "Retrieve details of a specific income statement record using its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "income-statements"
+                ],
+                "summary": "Get a single income statement entry by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Income Statement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.IncomeStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Income Statement not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Modify the details of an existing income statement record by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "income-statements"
+                ],
+                "summary": "Update an existing income statement entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Income Statement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Income Statement data to update",
+                        "name": "incomeStatement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IncomeStatement"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.IncomeStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid ID format or input data",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Income Statement not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove an income statement record from the database using its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "income-statements"
+                ],
+                "summary": "Delete an income statement entry by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Income Statement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Income Statement not found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/members/by-regcode/{regcode}": {
+            "get": {
+                "description": "Возвращает пагинированный список участников (members) указанной компании - тех, кто держит ее доли (at_legal_entity_registration_number = regcode). То же, что /company/{regcode}/shareholders. ownership_percent - доля участника в уставном капитале (число акций * номинал, LVL пересчитывается в EUR, если валюты участников различаются). С as_of - участники на эту дату по версиям записей (entity_versions).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "member"
+                ],
+                "summary": "Получить участников компании по Regcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список участников",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Member"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode или as_of",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/officers/by-regcode/{regcode}": {
+            "get": {
+                "description": "Возвращает пагинированный список должностных лиц (officers) указанной компании: правление, совет, ликвидаторы и их право представительства. С as_of - должностных лиц на эту дату по версиям записей (entity_versions), которые ведет импортер.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "officer"
+                ],
+                "summary": "Получить должностных лиц компании по Regcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список должностных лиц",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Officer"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode или as_of",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/persons/{id}": {
+            "get": {
+                "description": "Возвращает лицо, выделенное импортером при сопоставлении записей members, beneficial_owners и officers (по началу персонального кода, имени и дате рождения), и все его роли. confidence лица - минимальная уверенность среди его записей; match_confidence роли - уверенность для конкретной записи (0.99 - код, имя и дата рождения совпадают; ниже 0.6 - сопоставление только по имени).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Физическое лицо и все его роли в компаниях",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID лица (person_id)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лицо и его роли",
+                        "schema": {
+                            "$ref": "#/definitions/models.PersonProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Лицо не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/register/{regcode}": {
+            "get": {
+                "description": "Получает детальную информацию о компании по её Regcode.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "register"
+                ],
+                "summary": "Получить информацию о компании по Regcode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о компании",
+                        "schema": {
+                            "$ref": "#/definitions/models.Registers"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный Regcode",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/registers": {
+            "get": {
+                "description": "Возвращает пагинированный список записей из таблицы registers с необязательными фильтрами по типу, статусу, адресу, дате регистрации и наличию связанных данных.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "register"
+                ],
+                "summary": "Получить список всех записей регистра",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по regtype (несколько значений через запятую)",
+                        "name": "regtype",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по типу компании, например SIA,AS",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "terminated",
+                            "closed"
+                        ],
+                        "type": "string",
+                        "description": "Статус компании",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код региона (несколько через запятую)",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код города (несколько через запятую)",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код ATVK (несколько через запятую)",
+                        "name": "atvk",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть / нет фин. отчетов",
+                        "name": "has_financials",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть / нет бенефициаров",
+                        "name": "has_beneficial_owners",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть / нет должностных лиц",
+                        "name": "has_officers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сработавшее правило риска (несколько через запятую), например negative_equity,circular_ownership",
+                        "name": "risk_flag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Уровень риска (несколько через запятую): low, medium, high",
+                        "name": "risk_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальный score риска (0-100)",
+                        "name": "min_risk_score",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список записей",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Registers"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверное значение фильтра",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/screener": {
+            "get": {
+                "description": "Отбирает компании по строкам фин. отчета за год и производным показателям (current_ratio, debt_to_equity, return_on_assets, return_on_equity, net_margin, gross_margin, operating_cash_flow_coverage, equity_ratio, revenue_per_employee). Условие задается параметром condition вида поле:оператор:значение, например net_turnover:gt:1000000, equity:lt:0, employees:between:10,50; несколько условий объединяются через AND. Суммы сравниваются и возвращаются в единицах евро (rounded_to_nearest и LVL учитываются); отчеты с нераспознанной валютой или единицами не проходят условия по суммам. Дополнительно принимает фильтры /registers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "screener"
+                ],
+                "summary": "Скринер компаний по финансовым показателям",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Год отчетности",
+                        "name": "year",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Условие поле:оператор:значение (gt, gte, lt, lte, eq, ne, between)",
+                        "name": "condition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дополнительные показатели в ответе через запятую",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Показатель или name; префикс '-' - по убыванию (например -net_turnover). По умолчанию name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по типу компании, например SIA,AS",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "terminated",
+                            "closed"
+                        ],
+                        "type": "string",
+                        "description": "Статус компании",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код региона (несколько через запятую)",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код города (несколько через запятую)",
+                        "name": "city",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Компании, прошедшие условия",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ScreenerResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный год, условие, поле или фильтр",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/detailed": {
+            "get": {
+                "description": "Ищет компании по Regcode/SEPA (точное совпадение) и полнотекстово по названию и адресу. Принимает те же фильтры, что и /registers. Результаты упорядочены по релевантности (BM25). Поддерживаются префиксы (` + "`" + `balt*` + "`" + `) и фразы (` + "`" + `\"zemes iela\"` + "`" + `). Регистр и диакритика не учитываются (lusins находит Lūsiņš). Если FTS5 недоступен, используется поиск LIKE по нормализованному названию.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Упрощенный поиск компаний (с пагинацией)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос (Regcode, SEPA, название, адрес; префикс: слово*, фраза - в двойных кавычках)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по regtype (несколько значений через запятую)",
+                        "name": "regtype",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по типу компании, например SIA,AS",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "terminated",
+                            "closed"
+                        ],
+                        "type": "string",
+                        "description": "Статус компании",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код региона (несколько через запятую)",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код города (несколько через запятую)",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код ATVK (несколько через запятую)",
+                        "name": "atvk",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)",
+                        "name": "registered_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть / нет фин. отчетов",
+                        "name": "has_financials",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть / нет бенефициаров",
+                        "name": "has_beneficial_owners",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть / нет должностных лиц",
+                        "name": "has_officers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сработавшее правило риска (несколько через запятую), например negative_equity,circular_ownership",
+                        "name": "risk_flag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Уровень риска (несколько через запятую): low, medium, high",
+                        "name": "risk_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальный score риска (0-100)",
+                        "name": "min_risk_score",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список базовой информации о компаниях",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SimpleRegisterInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос (отсутствует 'q' или неверный фильтр)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/persons": {
+            "get": {
+                "description": "Ищет физических лиц среди участников (members), бенефициаров (beneficial owners) и должностных лиц (officers) по имени (без учета регистра, диакритики и порядка слов) или по маскированному персональному коду (\"090859\" или \"090859-*****\"). Записи одного лица (person_id, см. сопоставление лиц в импортере) возвращаются одним результатом со списком всех найденных компаний и ролей. Группировка, подсчет и пагинация выполняются в БД, поэтому total_records - точное число лиц.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Поиск физических лиц по всем компаниям",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя лица или начало персонального кода",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список лиц с их компаниями",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.PersonSearchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос (отсутствует 'q')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist": {
+            "get": {
+                "description": "Компании, об изменениях которых после импорта отправляются вебхуки (см. /webhooks).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Список отслеживаемых компаний",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный список компаний",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WatchlistEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Добавить компанию в watchlist",
+                "parameters": [
+                    {
+                        "description": "Regcode компании и комментарий",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверное тело запроса",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компания не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Компания уже в watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/{regcode}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Изменить комментарий к компании в watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый комментарий (regcode игнорируется)",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WatchlistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверное тело запроса",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Компании нет в watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Убрать компанию из watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Regcode компании",
+                        "name": "regcode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщение об успехе",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Компании нет в watchlist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Список подписок на вебхуки",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "После каждого импорта на url отправляется POST по каждой компании из watchlist, у которой есть изменения нужных типов (beneficial_owner.added, beneficial_owner.removed, member.added, member.removed, member.shares_changed, financial_statement.added, company.terminated). Заголовок X-Webhook-Signature содержит \"sha256=\" + HMAC-SHA256 от \"\u003cX-Webhook-Timestamp\u003e.\u003cтело\u003e\" с секретом подписки. Неудачные доставки повторяются с удвоением паузы (30s, 1m, 2m, ...), всего до 6 попыток. Секрет возвращается только в этом ответе.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Создать подписку на вебхуки",
+                "parameters": [
+                    {
+                        "description": "url обязателен; без secret он будет сгенерирован",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionWithSecret"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный url или тип события",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Подписка на вебхуки по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Меняются только переданные поля. event_types: [] - все типы событий.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Изменить подписку на вебхуки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения полей",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID, url или тип события",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет подписку вместе с журналом ее доставок.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удалить подписку на вебхуки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сообщение об успехе",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Уведомления подписки с результатом последней попытки, от новых к старым. Доставки в статусе pending повторяются сервером в next_attempt_at.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Журнал доставок подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Статус доставки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пагинированный журнал доставок",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WebhookDelivery"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID или статус",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.HTTPError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AddressCompanies": {
+            "type": "object",
+            "properties": {
+                "companies": {
+                    "$ref": "#/definitions/models.PaginatedResponse"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.AddressSummary"
+                }
+            }
+        },
+        "models.AddressCompany": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "closed": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "regcode": {
+                    "type": "string"
+                },
+                "registered": {
+                    "type": "string"
+                },
+                "regtype": {
+                    "type": "string"
+                },
+                "terminated": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "type_text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AddressSummary": {
+            "type": "object",
+            "properties": {
+                "active_companies": {
+                    "description": "Без terminated и closed",
+                    "type": "integer"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "addressid": {
+                    "type": "string"
+                },
+                "by_type": {
+                    "description": "По убыванию числа компаний",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AddressTypeCount"
+                    }
+                },
+                "first_registered": {
+                    "type": "string"
+                },
+                "last_registered": {
+                    "type": "string"
+                },
+                "registered_last_year": {
+                    "description": "Зарегистрировано за последние 365 дней",
+                    "type": "integer"
+                },
+                "spread_days": {
+                    "description": "Дней между первой и последней регистрацией",
+                    "type": "integer"
+                },
+                "total_companies": {
+                    "description": "Включая ликвидированные",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AddressTypeCount": {
+            "type": "object",
+            "properties": {
+                "companies": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "type_text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BalanceSheet": {
+            "type": "object",
+            "properties": {
+                "accounts_receivable": {
+                    "type": "integer"
+                },
+                "cash": {
+                    "type": "integer"
+                },
+                "current_liabilities": {
+                    "type": "integer"
+                },
+                "equity": {
+                    "type": "integer"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "fixed_assets": {
+                    "type": "integer"
+                },
+                "future_housing_repairs_payments": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "intangible_assets": {
+                    "type": "integer"
+                },
+                "inventories": {
+                    "type": "integer"
+                },
+                "investments": {
+                    "type": "integer"
+                },
+                "marketable_securities": {
+                    "type": "integer"
+                },
+                "non_current_liabilities": {
+                    "type": "integer"
+                },
+                "provisions": {
+                    "type": "integer"
+                },
+                "statementID": {
+                    "description": "\u003c--- Добавить/обновить тег",
+                    "type": "integer"
+                },
+                "total_assets": {
+                    "type": "integer"
+                },
+                "total_current_assets": {
+                    "type": "integer"
+                },
+                "total_equities": {
+                    "type": "integer"
+                },
+                "total_non_current_assets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BenchmarkMetric": {
+            "type": "object",
+            "properties": {
+                "median": {
+                    "type": "number"
+                },
+                "metric": {
+                    "type": "string"
+                },
+                "p25": {
+                    "type": "number"
+                },
+                "p75": {
+                    "type": "number"
+                },
+                "peer_count": {
+                    "description": "Аналогов, у которых показатель посчитан",
+                    "type": "integer"
+                },
+                "percentile_rank": {
+                    "description": "0-100: доля аналогов ниже значения (равные считаются наполовину)",
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BenchmarkPeerGroup": {
+            "type": "object",
+            "properties": {
+                "by": {
+                    "description": "type | region | size",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "companies": {
+                    "description": "Компаний в группе (включая саму компанию)",
+                    "type": "integer"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "size_band": {
+                    "type": "string",
+                    "enum": [
+                        "micro",
+                        "small",
+                        "medium",
+                        "large"
+                    ]
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BeneficialOwner": {
+            "type": "object",
+            "properties": {
+                "birth_date": {
+                    "type": "string"
+                },
+                "forename": {
+                    "description": "\u003c-- Индекс для поиска",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_modified_at": {
+                    "type": "string"
+                },
+                "latvian_identity_number_masked": {
+                    "description": "... остальные поля ...",
+                    "type": "string"
+                },
+                "legal_entity_registration_number": {
+                    "description": "Индекс нужен для поиска по компании и для связи в Registers",
+                    "type": "string"
+                },
+                "nationality": {
+                    "type": "string"
+                },
+                "person_confidence": {
+                    "description": "Уверенность сопоставления, 0-1",
+                    "type": "number"
+                },
+                "person_id": {
+                    "description": "Лицо после сопоставления записей members/beneficial_owners/officers (заполняет импортер, см. persons)",
+                    "type": "integer"
+                },
+                "registered_on": {
+                    "type": "string"
+                },
+                "residence": {
+                    "type": "string"
+                },
+                "surname": {
+                    "description": "\u003c-- Индекс для поиска",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CashFlowStatement": {
+            "type": "object",
+            "properties": {
+                "at_beginning_of_year": {
+                    "type": "integer"
+                },
+                "at_end_of_year": {
+                    "type": "integer"
+                },
+                "cff_dividends_paid": {
+                    "type": "integer"
+                },
+                "cff_loans_received": {
+                    "type": "integer"
+                },
+                "cff_net_financing_cash_flow": {
+                    "type": "integer"
+                },
+                "cff_proceeds_from_stocks_bonds_issuance_or_contributed_capital": {
+                    "type": "integer"
+                },
+                "cff_repayments_of_lease_obligations": {
+                    "type": "integer"
+                },
+                "cff_repayments_of_loans_made": {
+                    "type": "integer"
+                },
+                "cff_subsidies_grants_donations_received": {
+                    "type": "integer"
+                },
+                "cfi_acquisition_of_fixed_assets_intangible_assets": {
+                    "type": "integer"
+                },
+                "cfi_acquisition_of_stocks_shares": {
+                    "type": "integer"
+                },
+                "cfi_dividends_received": {
+                    "type": "integer"
+                },
+                "cfi_interest_received": {
+                    "type": "integer"
+                },
+                "cfi_loans_made": {
+                    "type": "integer"
+                },
+                "cfi_net_investing_cash_flow": {
+                    "type": "integer"
+                },
+                "cfi_repayments_of_loans_received": {
+                    "type": "integer"
+                },
+                "cfi_sale_proceeds_from_fixed_assets_intangible_assets": {
+                    "type": "integer"
+                },
+                "cfi_sale_proceeds_from_stocks_shares": {
+                    "type": "integer"
+                },
+                "cfo_dm_cash_paid_to_suppliers_employees": {
+                    "type": "integer"
+                },
+                "cfo_dm_cash_received_from_customers": {
+                    "type": "integer"
+                },
+                "cfo_dm_extra_items_cash_flow": {
+                    "type": "integer"
+                },
+                "cfo_dm_income_taxes_paid": {
+                    "type": "integer"
+                },
+                "cfo_dm_interest_paid": {
+                    "type": "integer"
+                },
+                "cfo_dm_net_operating_cash_flow": {
+                    "type": "integer"
+                },
+                "cfo_dm_operating_cash_flow": {
+                    "type": "integer"
+                },
+                "cfo_dm_other_cash_received_paid": {
+                    "type": "integer"
+                },
+                "cfo_im_extra_items_cash_flow": {
+                    "type": "integer"
+                },
+                "cfo_im_income_before_changes_in_working_capital": {
+                    "type": "integer"
+                },
+                "cfo_im_income_before_income_taxes": {
+                    "type": "integer"
+                },
+                "cfo_im_income_taxes_paid": {
+                    "type": "integer"
+                },
+                "cfo_im_interest_paid": {
+                    "type": "integer"
+                },
+                "cfo_im_net_operating_cash_flow": {
+                    "type": "integer"
+                },
+                "cfo_im_operating_cash_flow": {
+                    "type": "integer"
+                },
+                "effect_of_exchange_rate_change": {
+                    "type": "integer"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "net_increase": {
+                    "type": "integer"
+                },
+                "statementID": {
+                    "description": "\u003c--- Добавить/обновить тег",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ChangeEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "entity": {
+                    "description": "Таблица: registers, members, beneficial_owners, ...",
+                    "type": "string"
+                },
+                "entity_id": {
+                    "description": "Ключ записи (значения колонок ON CONFLICT через \"|\")",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Только для update",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "import_run_id": {
+                    "type": "integer"
+                },
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string",
+                    "enum": [
+                        "insert",
+                        "update",
+                        "delete"
+                    ]
+                },
+                "regcode": {
+                    "description": "Компания, к которой относится запись",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CompanyBenchmark": {
+            "type": "object",
+            "properties": {
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BenchmarkMetric"
+                    }
+                },
+                "peer_group": {
+                    "$ref": "#/definitions/models.BenchmarkPeerGroup"
+                },
+                "refreshed_at": {
+                    "description": "Когда показатели были пересчитаны (последний импорт)",
+                    "type": "string"
+                },
+                "regcode": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanyRatiosYear": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "ratios": {
+                    "$ref": "#/definitions/models.FinancialRatios"
+                },
+                "rounded_to_nearest": {
+                    "type": "string"
+                },
+                "statement_id": {
+                    "type": "integer"
+                },
+                "units_known": {
+                    "description": "false, если RoundedToNearest не распознан (коэффициенты все равно корректны)",
+                    "type": "boolean"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanyRiskFlag": {
+            "type": "object",
+            "properties": {
+                "evaluated_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Объяснение на русском",
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string",
+                    "enum": [
+                        "negative_equity",
+                        "zero_assets_with_liabilities",
+                        "no_recent_statements",
+                        "frequent_beneficial_owner_changes",
+                        "shared_address",
+                        "circular_ownership"
+                    ]
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high"
+                    ]
+                },
+                "value": {
+                    "description": "Значение, по которому сработало правило (капитал, число компаний и т.п.)",
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "integer"
+                },
+                "year": {
+                    "description": "Год отчета, если правило по отчетности",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanyRiskReport": {
+            "type": "object",
+            "properties": {
+                "evaluated_at": {
+                    "description": "nil - оценка еще не выполнялась",
+                    "type": "string"
+                },
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CompanyRiskFlag"
+                    }
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "regcode": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompanyScreening": {
+            "type": "object",
+            "properties": {
+                "lists": {
+                    "description": "Загруженные списки, по которым проверялись записи",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScreeningList"
+                    }
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScreeningMatch"
+                    }
+                },
+                "min_score": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "regcode": {
+                    "type": "string"
+                },
+                "screened_at": {
+                    "description": "Последний пакетный скрининг; nil - еще не выполнялся",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CompanyTimeSeries": {
+            "type": "object",
+            "properties": {
+                "normalized": {
+                    "description": "true - суммы приведены к единицам евро (?normalize=eur)",
+                    "type": "boolean"
+                },
+                "regcode": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimeSeries"
+                    }
+                },
+                "years": {
+                    "description": "Общая ось лет для всех рядов",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.DeclaredUBO": {
+            "type": "object",
+            "properties": {
+                "beneficial_owner_id": {
+                    "type": "integer"
+                },
+                "effective_percent": {
+                    "description": "Косвенная доля, если найдена (может быть ниже порога)",
+                    "type": "number"
+                },
+                "forename": {
+                    "type": "string"
+                },
+                "latvian_identity_number_masked": {
+                    "type": "string"
+                },
+                "ownership_found": {
+                    "description": "Лицо есть среди конечных владельцев",
+                    "type": "boolean"
+                },
+                "person_id": {
+                    "type": "integer"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DerivedUBO": {
+            "type": "object",
+            "properties": {
+                "beneficial_owner_id": {
+                    "description": "Запись beneficial_owners, с которой сопоставлено лицо",
+                    "type": "integer"
+                },
+                "declared": {
+                    "description": "Есть среди заявленных бенефициаров",
+                    "type": "boolean"
+                },
+                "effective_percent": {
+                    "type": "number"
+                },
+                "incomplete": {
+                    "description": "В какой-то цепочке доля неизвестна или обход остановлен по depth",
+                    "type": "boolean"
+                },
+                "latvian_identity_number_masked": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paths": {
+                    "type": "integer"
+                },
+                "person_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EffectiveOwner": {
+            "type": "object",
+            "properties": {
+                "effective_percent": {
+                    "description": "Сумма по цепочкам произведений долей, %",
+                    "type": "number"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "incomplete": {
+                    "description": "В какой-то цепочке доля неизвестна или обход остановлен по depth",
+                    "type": "boolean"
+                },
+                "latvian_identity_number_masked": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paths": {
+                    "description": "Число цепочек до компании",
+                    "type": "integer"
+                },
+                "person_id": {
+                    "description": "Для физ. лиц, сопоставленных импортером",
+                    "type": "integer"
+                },
+                "regcode": {
+                    "description": "Для юр. лиц",
+                    "type": "string"
+                }
+            }
+        },
+        "models.EffectiveOwnership": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EffectiveOwner"
+                    }
+                },
+                "regcode": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "description": "Обход обрезан по лимиту узлов",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.FinancialRatios": {
+            "type": "object",
+            "properties": {
+                "current_ratio": {
+                    "description": "TotalCurrentAssets / CurrentLiabilities",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatioValue"
+                        }
+                    ]
+                },
+                "debt_to_equity": {
+                    "description": "(CurrentLiabilities + NonCurrentLiabilities) / Equity",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatioValue"
+                        }
+                    ]
+                },
+                "gross_margin": {
+                    "description": "ByFunctionGrossProfit / NetTurnover",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatioValue"
+                        }
+                    ]
+                },
+                "net_margin": {
+                    "description": "NetIncome / NetTurnover",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatioValue"
+                        }
+                    ]
+                },
+                "operating_cash_flow_coverage": {
+                    "description": "Чистый операционный денежный поток / CurrentLiabilities",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatioValue"
+                        }
+                    ]
+                },
+                "return_on_assets": {
+                    "description": "NetIncome / TotalAssets",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatioValue"
+                        }
+                    ]
+                },
+                "return_on_equity": {
+                    "description": "NetIncome / Equity",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RatioValue"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.FinancialStatement": {
+            "type": "object",
+            "properties": {
+                "balanceSheet": {
+                    "$ref": "#/definitions/models.BalanceSheet"
+                },
+                "cashFlowStatement": {
+                    "$ref": "#/definitions/models.CashFlowStatement"
+                },
+                "created_at": {
+                    "description": "Дата из CSV, GORM не должен её подставлять",
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "employees": {
+                    "type": "integer"
+                },
+                "file_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "incomeStatement": {
+                    "description": "--- Связи (Один к одному/нулю с деталями) ---\nУказываем, что у Statement есть детали, связанные по ID этого Statement\nи колонке 'statement_id' в таблицах деталей.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.IncomeStatement"
+                        }
+                    ]
+                },
+                "legalEntityRegistrationNumber": {
+                    "description": "Индекс нужен для поиска по компании и для связи в Registers",
+                    "type": "string"
+                },
+                "rounded_to_nearest": {
+                    "type": "string"
+                },
+                "source_schema": {
+                    "description": "... остальные поля ...",
+                    "type": "string"
+                },
+                "source_type": {
+                    "type": "string"
+                },
+                "year": {
+                    "description": "\u003c-- Составной уникальный индекс",
+                    "type": "integer"
+                },
+                "year_ended_on": {
+                    "type": "string"
+                },
+                "year_started_on": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Holding": {
+            "type": "object",
+            "properties": {
+                "date_from": {
+                    "type": "string"
+                },
+                "member_id": {
+                    "description": "Запись members, которая задает долю",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Из registers; пусто, если компании нет в реестре",
+                    "type": "string"
+                },
+                "number_of_shares": {
+                    "type": "number"
+                },
+                "ownership_percent": {
+                    "description": "Доля в уставном капитале компании, %",
+                    "type": "number"
+                },
+                "regcode": {
+                    "description": "Компания, в которой держится доля (at_legal_entity_registration_number)",
+                    "type": "string"
+                },
+                "registered_on": {
+                    "type": "string"
+                },
+                "regtype_text": {
+                    "type": "string"
+                },
+                "share_currency": {
+                    "type": "string"
+                },
+                "share_nominal_value": {
+                    "type": "number"
+                },
+                "terminated": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IncomeStatement": {
+            "type": "object",
             "properties": {
-                "error": {
+                "by_function_administrative_expenses": {
+                    "type": "integer"
+                },
+                "by_function_cost_of_goods_sold": {
+                    "type": "integer"
+                },
+                "by_function_gross_profit": {
+                    "type": "integer"
+                },
+                "by_function_other_operating_revenues": {
+                    "type": "integer"
+                },
+                "by_function_selling_expenses": {
+                    "type": "integer"
+                },
+                "by_nature_depreciation_expenses": {
+                    "type": "integer"
+                },
+                "by_nature_inventory_change": {
+                    "type": "integer"
+                },
+                "by_nature_labour_expenses": {
+                    "type": "integer"
+                },
+                "by_nature_long_term_investment_expenses": {
+                    "type": "integer"
+                },
+                "by_nature_material_expenses": {
+                    "type": "integer"
+                },
+                "by_nature_other_operating_revenues": {
+                    "type": "integer"
+                },
+                "equity_investment_earnings": {
+                    "type": "integer"
+                },
+                "extra_dividends": {
+                    "type": "integer"
+                },
+                "extra_expenses": {
+                    "type": "integer"
+                },
+                "extra_revenues": {
+                    "type": "integer"
+                },
+                "file_id": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "income_after_income_taxes": {
+                    "type": "integer"
+                },
+                "income_before_income_taxes": {
+                    "type": "integer"
+                },
+                "interest_expenses": {
+                    "type": "integer"
+                },
+                "investment_fair_value_adjustments": {
+                    "type": "integer"
+                },
+                "net_income": {
+                    "type": "integer"
+                },
+                "net_turnover": {
+                    "type": "integer"
+                },
+                "other_interest_revenues": {
+                    "type": "integer"
+                },
+                "other_long_term_investment_earnings": {
+                    "type": "integer"
+                },
+                "other_operating_expenses": {
+                    "type": "integer"
+                },
+                "other_taxes": {
+                    "type": "integer"
+                },
+                "provision_for_income_taxes": {
+                    "type": "integer"
+                },
+                "statementID": {
+                    "description": "\u003c--- Добавить/обновить тег",
+                    "type": "integer"
                 }
             }
         },
-        "models.BalanceSheet": {
+        "models.Member": {
             "type": "object",
             "properties": {
-                "accounts_receivable": {
+                "at_legal_entity_registration_number": {
+                    "description": "Компания, в которой участник держит доли (связь Registers.Members и Member.Company)",
                     "type": "string"
                 },
-                "cash": {
+                "birth_date": {
                     "type": "string"
                 },
-                "current_liabilities": {
+                "company": {
+                    "description": "Компания, в которой участник держит доли (загружается только через Preload(\"Company\")).\nconstraint:- - в members есть компании, которых нет в registers, внешний ключ не создаем",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Registers"
+                        }
+                    ]
+                },
+                "date_from": {
                     "type": "string"
                 },
-                "equity": {
+                "entity_type": {
                     "type": "string"
                 },
-                "file_id": {
+                "id": {
+                    "type": "integer"
+                },
+                "last_modified_at": {
                     "type": "string"
                 },
-                "fixed_assets": {
+                "latvian_identity_number_masked": {
+                    "description": "... остальные поля ...",
+                    "type": "string"
+                },
+                "legal_entity_registration_number": {
+                    "description": "Regcode самого участника, если он - юр. лицо (связь Registers.Holdings); у физ. лиц пусто",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "\u003c-- Индекс для поиска по имени",
+                    "type": "string"
+                },
+                "number_of_shares": {
+                    "type": "number"
+                },
+                "ownership_percent": {
+                    "description": "Доля в уставном капитале компании, % (не хранится: считается по всем участникам компании)",
+                    "type": "number"
+                },
+                "person_confidence": {
+                    "description": "Уверенность сопоставления, 0-1",
+                    "type": "number"
+                },
+                "person_id": {
+                    "description": "Лицо после сопоставления записей members/beneficial_owners/officers (заполняет импортер, см. persons)",
+                    "type": "integer"
+                },
+                "registered_on": {
+                    "type": "string"
+                },
+                "share_currency": {
+                    "type": "string"
+                },
+                "share_nominal_value": {
+                    "type": "number"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Officer": {
+            "type": "object",
+            "properties": {
+                "at_legal_entity_registration_number": {
+                    "description": "Regcode компании, в которой лицо занимает должность - по нему связь с Registers",
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "governing_body": {
+                    "description": "EXECUTIVE_BOARD, COUNCIL, ...",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_modified_at": {
+                    "type": "string"
+                },
+                "latvian_identity_number_masked": {
+                    "type": "string"
+                },
+                "legal_entity_registration_number": {
+                    "description": "Заполнено, только если должностное лицо само является юр. лицом",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "\u003c-- Индекс для поиска по имени",
+                    "type": "string"
+                },
+                "person_confidence": {
+                    "description": "Уверенность сопоставления, 0-1",
+                    "type": "number"
+                },
+                "person_id": {
+                    "description": "Лицо после сопоставления записей members/beneficial_owners/officers (заполняет импортер, см. persons)",
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "BOARD_MEMBER, CHAIR_OF_BOARD, LIQUIDATOR, ...",
+                    "type": "string"
+                },
+                "registered_on": {
+                    "type": "string"
+                },
+                "representation_with_at_least": {
+                    "description": "Сколько ещё лиц нужно для совместной подписи",
+                    "type": "integer"
+                },
+                "rights_of_representation_type": {
+                    "description": "INDIVIDUALLY, JOINTLY, ...",
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OwnershipNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OwnershipNode"
+                    }
+                },
+                "cycle": {
+                    "description": "Узел уже есть выше по пути - дальше не раскрывается",
+                    "type": "boolean"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "depth_limit_reached": {
+                    "description": "У узла есть связи глубже, чем depth",
+                    "type": "boolean"
+                },
+                "effective_percent": {
+                    "description": "Произведение долей по пути от корня, %",
+                    "type": "number"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "member_id": {
+                    "description": "Запись members, которая задает связь",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "number_of_shares": {
+                    "type": "number"
+                },
+                "ownership_percent": {
+                    "description": "Прямая доля в капитале дочерней компании связи, %",
+                    "type": "number"
+                },
+                "regcode": {
+                    "description": "Regcode юр. лица; у физ. лиц пусто",
                     "type": "string"
                 },
-                "future_housing_repairs_payments": {
+                "share_currency": {
+                    "type": "string"
+                },
+                "share_nominal_value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OwnershipTree": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OwnershipNode"
+                    }
+                },
+                "cycles": {
+                    "description": "Найденные циклы: regcode по пути, первый = последний",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "direction": {
+                    "type": "string",
+                    "enum": [
+                        "owners",
+                        "subsidiaries"
+                    ]
+                },
+                "name": {
                     "type": "string"
                 },
-                "id": {
+                "nodes": {
+                    "description": "Узлов в дереве (без корня)",
                     "type": "integer"
                 },
-                "intangible_assets": {
+                "regcode": {
                     "type": "string"
                 },
-                "inventories": {
-                    "type": "string"
+                "truncated": {
+                    "description": "Дерево обрезано по лимиту узлов",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.PaginatedResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Срез данных для текущей страницы"
                 },
-                "investments": {
-                    "type": "string"
+                "limit": {
+                    "description": "Лимит записей на странице",
+                    "type": "integer"
                 },
-                "marketable_securities": {
-                    "type": "string"
+                "page": {
+                    "description": "Текущий номер страницы",
+                    "type": "integer"
                 },
-                "non_current_liabilities": {
+                "total_records": {
+                    "description": "Общее количество записей (не только на странице)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Person": {
+            "type": "object",
+            "properties": {
+                "birth_date": {
                     "type": "string"
                 },
-                "provisions": {
-                    "type": "string"
+                "confidence": {
+                    "description": "Минимальная уверенность среди записей лица, 0-1",
+                    "type": "number"
                 },
-                "statementID": {
-                    "description": "\u003c--- Добавить/обновить тег",
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "total_assets": {
+                "latvian_identity_number_masked": {
                     "type": "string"
                 },
-                "total_current_assets": {
+                "name": {
                     "type": "string"
                 },
-                "total_equities": {
-                    "type": "string"
+                "records": {
+                    "description": "Сколько записей сопоставлено с лицом",
+                    "type": "integer"
                 },
-                "total_non_current_assets": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.BeneficialOwner": {
+        "models.PersonCompanyRole": {
             "type": "object",
             "properties": {
-                "birth_date": {
+                "company_name": {
                     "type": "string"
                 },
-                "forename": {
-                    "description": "\u003c-- Индекс для поиска",
+                "governing_body": {
+                    "description": "Только для officer",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "last_modified_at": {
-                    "type": "string"
+                "match_confidence": {
+                    "description": "Уверенность сопоставления записи с лицом",
+                    "type": "number"
                 },
-                "latvian_identity_number_masked": {
-                    "description": "... остальные поля ...",
-                    "type": "string"
+                "number_of_shares": {
+                    "description": "Только для member",
+                    "type": "number"
                 },
-                "legal_entity_registration_number": {
-                    "description": "Индекс нужен для поиска по компании и для связи в Registers",
+                "position": {
+                    "description": "Только для officer",
                     "type": "string"
                 },
-                "nationality": {
+                "regcode": {
                     "type": "string"
                 },
-                "registered_on": {
-                    "type": "string"
+                "role": {
+                    "description": "member - участник (дольщик), beneficial_owner - бенефициар, officer - должностное лицо",
+                    "type": "string",
+                    "enum": [
+                        "member",
+                        "beneficial_owner",
+                        "officer"
+                    ]
                 },
-                "residence": {
+                "since": {
+                    "description": "date_from / registered_on",
                     "type": "string"
+                }
+            }
+        },
+        "models.PersonProfile": {
+            "type": "object",
+            "properties": {
+                "companies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PersonCompanyRole"
+                    }
                 },
-                "surname": {
-                    "description": "\u003c-- Индекс для поиска",
-                    "type": "string"
+                "person": {
+                    "$ref": "#/definitions/models.Person"
                 }
             }
         },
-        "models.CashFlowStatement": {
+        "models.PersonSearchResult": {
             "type": "object",
             "properties": {
-                "at_beginning_of_year": {
+                "birth_date": {
                     "type": "string"
                 },
-                "at_end_of_year": {
-                    "type": "string"
+                "companies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PersonCompanyRole"
+                    }
                 },
-                "cff_dividends_paid": {
+                "latvian_identity_number_masked": {
                     "type": "string"
                 },
-                "cff_loans_received": {
+                "name": {
                     "type": "string"
                 },
-                "cff_net_financing_cash_flow": {
-                    "type": "string"
+                "person_id": {
+                    "description": "См. /persons/{id}",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RatioValue": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "missing_data",
+                        "zero_denominator",
+                        "negative_denominator"
+                    ]
                 },
-                "cff_proceeds_from_stocks_bonds_issuance_or_contributed_capital": {
-                    "type": "string"
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Registers": {
+            "type": "object",
+            "properties": {
+                "Holdings": {
+                    "description": "Доли компании в других компаниях: записи members, где компания сама участник\n('legal_entity_registration_number' = regcode). В детали компании не загружается.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Member"
+                    }
                 },
-                "cff_repayments_of_lease_obligations": {
+                "address": {
                     "type": "string"
                 },
-                "cff_repayments_of_loans_made": {
+                "addressid": {
                     "type": "string"
                 },
-                "cff_subsidies_grants_donations_received": {
+                "atvk": {
                     "type": "string"
                 },
-                "cfi_acquisition_of_fixed_assets_intangible_assets": {
-                    "type": "string"
+                "beneficialOwners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BeneficialOwner"
+                    }
                 },
-                "cfi_acquisition_of_stocks_shares": {
+                "city": {
                     "type": "string"
                 },
-                "cfi_dividends_received": {
+                "closed": {
                     "type": "string"
                 },
-                "cfi_interest_received": {
-                    "type": "string"
+                "financialStatements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FinancialStatement"
+                    }
                 },
-                "cfi_loans_made": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "cfi_net_investing_cash_flow": {
+                "indexCompany": {
                     "type": "string"
                 },
-                "cfi_repayments_of_loans_received": {
-                    "type": "string"
+                "latitude": {
+                    "type": "number"
                 },
-                "cfi_sale_proceeds_from_fixed_assets_intangible_assets": {
-                    "type": "string"
+                "longitude": {
+                    "type": "number"
                 },
-                "cfi_sale_proceeds_from_stocks_shares": {
-                    "type": "string"
+                "members": {
+                    "description": "--- Связи ---\nУчастники компании: записи members, где 'at_legal_entity_registration_number' = regcode\n(кто держит доли компании). constraint:- - внешний ключ в БД не создаем: в members есть\nкомпании, которых нет в registers.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Member"
+                    }
                 },
-                "cfo_dm_cash_paid_to_suppliers_employees": {
+                "name": {
+                    "description": "\u003c-- Добавим индекс для поиска по имени",
                     "type": "string"
                 },
-                "cfo_dm_cash_received_from_customers": {
+                "nameAfterQuotes": {
                     "type": "string"
                 },
-                "cfo_dm_extra_items_cash_flow": {
+                "nameBeforeQuotes": {
                     "type": "string"
                 },
-                "cfo_dm_income_taxes_paid": {
+                "nameInQuotes": {
+                    "description": "\u003c-- Добавим индекс",
                     "type": "string"
                 },
-                "cfo_dm_interest_paid": {
-                    "type": "string"
+                "officers": {
+                    "description": "Должностные лица связаны по 'at_legal_entity_registration_number' (компания, где лицо занимает должность)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Officer"
+                    }
                 },
-                "cfo_dm_net_operating_cash_flow": {
+                "regcode": {
+                    "description": "Уникальный индекс уже есть",
                     "type": "string"
                 },
-                "cfo_dm_operating_cash_flow": {
+                "region": {
                     "type": "string"
                 },
-                "cfo_dm_other_cash_received_paid": {
+                "registered": {
                     "type": "string"
                 },
-                "cfo_im_extra_items_cash_flow": {
+                "regtype": {
                     "type": "string"
                 },
-                "cfo_im_income_before_changes_in_working_capital": {
+                "regtypeText": {
                     "type": "string"
                 },
-                "cfo_im_income_before_income_taxes": {
+                "reregistrationTerm": {
                     "type": "string"
                 },
-                "cfo_im_income_taxes_paid": {
+                "sepa": {
                     "type": "string"
                 },
-                "cfo_im_interest_paid": {
+                "terminated": {
                     "type": "string"
                 },
-                "cfo_im_net_operating_cash_flow": {
+                "type": {
                     "type": "string"
                 },
-                "cfo_im_operating_cash_flow": {
+                "typeText": {
                     "type": "string"
                 },
-                "effect_of_exchange_rate_change": {
+                "withoutQuotes": {
+                    "description": "\u003c-- Добавим индекс",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScreenerResult": {
+            "type": "object",
+            "properties": {
+                "name": {
                     "type": "string"
                 },
-                "file_id": {
+                "regcode": {
                     "type": "string"
                 },
-                "id": {
+                "statement_id": {
                     "type": "integer"
                 },
-                "net_increase": {
+                "type": {
                     "type": "string"
                 },
-                "statementID": {
-                    "description": "\u003c--- Добавить/обновить тег",
-                    "type": "string"
+                "values": {
+                    "description": "Поля из условий, сортировки и fields; суммы в единицах евро",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "models.FinancialStatement": {
+        "models.ScreeningEntry": {
             "type": "object",
             "properties": {
-                "balanceSheet": {
-                    "$ref": "#/definitions/models.BalanceSheet"
-                },
-                "cashFlowStatement": {
-                    "$ref": "#/definitions/models.CashFlowStatement"
-                },
-                "created_at": {
+                "aliases": {
                     "type": "string"
                 },
-                "currency": {
+                "birth_dates": {
+                    "description": "Как в списке: дата или только год",
                     "type": "string"
                 },
-                "employees": {
+                "countries": {
                     "type": "string"
                 },
-                "file_id": {
+                "entry_type": {
+                    "type": "string",
+                    "enum": [
+                        "person",
+                        "entity"
+                    ]
+                },
+                "external_id": {
+                    "description": "Идентификатор записи в исходном списке",
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "incomeStatement": {
-                    "description": "--- Связи (Один к одному/нулю с деталями) ---\nУказываем, что у Statement есть детали, связанные по ID этого Statement\nи колонке 'statement_id' в таблицах деталей.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.IncomeStatement"
-                        }
-                    ]
+                "list_id": {
+                    "type": "integer"
                 },
-                "legalEntityRegistrationNumber": {
-                    "description": "Индекс нужен для поиска по компании и для связи в Registers",
+                "name": {
                     "type": "string"
                 },
-                "rounded_to_nearest": {
+                "programs": {
+                    "description": "Санкционные режимы или должности PEP",
                     "type": "string"
                 },
-                "source_schema": {
-                    "description": "... остальные поля ...",
+                "remarks": {
                     "type": "string"
+                }
+            }
+        },
+        "models.ScreeningList": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "sanctions",
+                        "pep"
+                    ]
                 },
-                "source_type": {
-                    "type": "string"
+                "entries": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "csv",
+                        "un-xml",
+                        "eu-xml",
+                        "ofac-xml"
+                    ]
                 },
-                "year": {
-                    "description": "\u003c-- Составной уникальный индекс",
+                "id": {
+                    "type": "integer"
+                },
+                "loaded_at": {
                     "type": "string"
                 },
-                "year_ended_on": {
+                "name": {
+                    "description": "Имя файла: повторная загрузка файла заменяет список",
                     "type": "string"
                 },
-                "year_started_on": {
+                "screened_at": {
+                    "description": "Последний пакетный скрининг реестра по этому списку; nil - еще не выполнялся",
                     "type": "string"
                 }
             }
         },
-        "models.IncomeStatement": {
+        "models.ScreeningMatch": {
             "type": "object",
             "properties": {
-                "by_function_administrative_expenses": {
-                    "type": "string"
+                "birth_date_match": {
+                    "description": "Год рождения из списка совпал с датой рождения (или кодом) лица; nil - сравнить нечем",
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "sanctions",
+                        "pep"
+                    ]
                 },
-                "by_function_cost_of_goods_sold": {
-                    "type": "string"
+                "entity": {
+                    "type": "string",
+                    "enum": [
+                        "registers",
+                        "members",
+                        "beneficial_owners",
+                        "officers"
+                    ]
                 },
-                "by_function_gross_profit": {
-                    "type": "string"
+                "entry": {
+                    "$ref": "#/definitions/models.ScreeningEntry"
                 },
-                "by_function_other_operating_revenues": {
-                    "type": "string"
+                "entry_id": {
+                    "type": "integer"
                 },
-                "by_function_selling_expenses": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "by_nature_depreciation_expenses": {
-                    "type": "string"
+                "import_run_id": {
+                    "description": "Запуск импорта, после которого выполнен скрининг",
+                    "type": "integer"
                 },
-                "by_nature_inventory_change": {
-                    "type": "string"
+                "list_id": {
+                    "type": "integer"
                 },
-                "by_nature_labour_expenses": {
+                "list_name": {
                     "type": "string"
                 },
-                "by_nature_long_term_investment_expenses": {
+                "matched_field": {
+                    "description": "Колонка записи реестра, по которой найдено совпадение",
                     "type": "string"
                 },
-                "by_nature_material_expenses": {
+                "matched_name": {
+                    "description": "Имя или псевдоним из списка, с которым совпало значение",
                     "type": "string"
                 },
-                "by_nature_other_operating_revenues": {
+                "matched_value": {
                     "type": "string"
                 },
-                "equity_investment_earnings": {
-                    "type": "string"
+                "record_id": {
+                    "type": "integer"
                 },
-                "extra_dividends": {
+                "regcode": {
+                    "description": "Компания, к которой относится запись реестра",
                     "type": "string"
                 },
-                "extra_expenses": {
-                    "type": "string"
+                "score": {
+                    "description": "Сходство имен, 0-1",
+                    "type": "number"
                 },
-                "extra_revenues": {
+                "screened_at": {
                     "type": "string"
-                },
-                "file_id": {
+                }
+            }
+        },
+        "models.SimpleRegisterInfo": {
+            "type": "object",
+            "properties": {
+                "Address": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "income_after_income_taxes": {
+                "Name": {
                     "type": "string"
                 },
-                "income_before_income_taxes": {
+                "Regcode": {
                     "type": "string"
                 },
-                "interest_expenses": {
+                "RegtypeText": {
                     "type": "string"
                 },
-                "investment_fair_value_adjustments": {
+                "TypeText": {
                     "type": "string"
+                }
+            }
+        },
+        "models.TimeSeries": {
+            "type": "object",
+            "properties": {
+                "cagr_from": {
+                    "description": "Год первого значения, по которому считался CAGR",
+                    "type": "integer"
                 },
-                "net_income": {
-                    "type": "string"
+                "cagr_pct": {
+                    "description": "Среднегодовой темп роста в % между первым и последним значением ряда (по суммам в евро)",
+                    "type": "number"
                 },
-                "net_turnover": {
-                    "type": "string"
+                "cagr_to": {
+                    "description": "Год последнего значения",
+                    "type": "integer"
                 },
-                "other_interest_revenues": {
+                "field": {
                     "type": "string"
                 },
-                "other_long_term_investment_earnings": {
-                    "type": "string"
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimeSeriesPoint"
+                    }
                 },
-                "other_operating_expenses": {
-                    "type": "string"
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "financial_statement",
+                        "income_statement",
+                        "balance_sheet",
+                        "cash_flow_statement"
+                    ]
+                }
+            }
+        },
+        "models.TimeSeriesPoint": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "description": "Абсолютное изменение к году year-1 (null, если одного из значений нет или у отчетов разные валюта/единицы)",
+                    "type": "number"
                 },
-                "other_taxes": {
-                    "type": "string"
+                "change_pct": {
+                    "description": "Изменение в % к |значению за year-1| по суммам в евро (null, если предыдущее 0 или нет)",
+                    "type": "number"
                 },
-                "provision_for_income_taxes": {
-                    "type": "string"
+                "value": {
+                    "type": "number"
                 },
-                "statementID": {
-                    "description": "\u003c--- Добавить/обновить тег",
-                    "type": "string"
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Member": {
+        "models.UBOAnalysis": {
             "type": "object",
             "properties": {
-                "at_legal_entity_registration_number": {
-                    "description": "Индекс нужен для поиска по компании и для связи в Registers",
-                    "type": "string"
+                "declared": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeclaredUBO"
+                    }
                 },
-                "birth_date": {
-                    "type": "string"
+                "depth": {
+                    "type": "integer"
                 },
-                "date_from": {
+                "derived": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DerivedUBO"
+                    }
+                },
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UBODiscrepancy"
+                    }
+                },
+                "name": {
                     "type": "string"
                 },
-                "entity_type": {
+                "regcode": {
                     "type": "string"
                 },
-                "id": {
+                "threshold_percent": {
+                    "type": "number"
+                },
+                "truncated": {
+                    "description": "Обход обрезан по лимиту узлов - результат может быть неполным",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UBODiscrepancy": {
+            "type": "object",
+            "properties": {
+                "beneficial_owner_id": {
                     "type": "integer"
                 },
-                "last_modified_at": {
-                    "type": "string"
+                "effective_percent": {
+                    "type": "number"
                 },
                 "latvian_identity_number_masked": {
-                    "description": "... остальные поля ...",
                     "type": "string"
                 },
-                "legal_entity_registration_number": {
-                    "description": "Если это поле = regcode компании, где лицо является участником, то нужен индекс",
+                "message": {
                     "type": "string"
                 },
                 "name": {
-                    "description": "\u003c-- Индекс для поиска по имени",
                     "type": "string"
                 },
-                "number_of_shares": {
+                "regcode": {
+                    "description": "Компания (no_declaration) или юр. лицо-владелец (unresolved_owner)",
                     "type": "string"
                 },
-                "registered_on": {
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "undeclared_owner",
+                        "declared_without_ownership",
+                        "no_declaration",
+                        "unresolved_owner"
+                    ]
+                }
+            }
+        },
+        "models.WatchlistEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "share_currency": {
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "description": "Комментарий (зачем компания добавлена)",
                     "type": "string"
                 },
-                "share_nominal_value": {
+                "regcode": {
                     "type": "string"
                 },
-                "uri": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.PaginatedResponse": {
+        "models.WatchlistInput": {
             "type": "object",
             "properties": {
-                "data": {
-                    "description": "Срез данных для текущей страницы"
-                },
-                "limit": {
-                    "description": "Лимит записей на странице",
-                    "type": "integer"
-                },
-                "page": {
-                    "description": "Текущий номер страницы",
-                    "type": "integer"
+                "note": {
+                    "type": "string"
                 },
-                "total_records": {
-                    "description": "Общее количество записей (не только на странице)",
-                    "type": "integer"
+                "regcode": {
+                    "description": "Только для POST",
+                    "type": "string"
                 }
             }
         },
-        "models.Registers": {
+        "models.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "addressid": {
-                    "type": "string"
+                "attempts": {
+                    "type": "integer"
                 },
-                "atvk": {
+                "created_at": {
                     "type": "string"
                 },
-                "beneficialOwners": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.BeneficialOwner"
-                    }
-                },
-                "city": {
+                "delivered_at": {
                     "type": "string"
                 },
-                "closed": {
+                "event_types": {
+                    "description": "Типы событий в payload через запятую",
                     "type": "string"
                 },
-                "financialStatements": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.FinancialStatement"
-                    }
-                },
                 "id": {
                     "type": "integer"
                 },
-                "indexCompany": {
-                    "type": "string"
-                },
-                "latitude": {
-                    "type": "string"
-                },
-                "longitude": {
-                    "type": "string"
-                },
-                "members": {
-                    "description": "--- Связи ---\nУказываем, что у одной записи Registers может быть много записей Member,\nсвязанных по колонке 'legal_entity_registration_number' в таблице members,\nкоторая соответствует колонке 'regcode' в таблице registers.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Member"
-                    }
+                "import_run_id": {
+                    "type": "integer"
                 },
-                "name": {
-                    "description": "\u003c-- Добавим индекс для поиска по имени",
+                "last_error": {
                     "type": "string"
                 },
-                "nameAfterQuotes": {
-                    "type": "string"
+                "last_status_code": {
+                    "type": "integer"
                 },
-                "nameBeforeQuotes": {
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "nameInQuotes": {
-                    "description": "\u003c-- Добавим индекс",
+                "payload": {
+                    "description": "Тело POST-запроса (JSON)",
                     "type": "string"
                 },
                 "regcode": {
-                    "description": "Уникальный индекс уже есть",
                     "type": "string"
                 },
-                "region": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "delivered",
+                        "failed"
+                    ]
                 },
-                "registered": {
-                    "type": "string"
+                "subscription_id": {
+                    "type": "integer"
                 },
-                "regtype": {
+                "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "models.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
                 },
-                "regtypeText": {
+                "created_at": {
                     "type": "string"
                 },
-                "reregistrationTerm": {
+                "event_types": {
+                    "description": "Типы событий через запятую; пусто - все",
                     "type": "string"
                 },
-                "sepa": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "terminated": {
+                "updated_at": {
                     "type": "string"
                 },
-                "type": {
+                "url": {
                     "type": "string"
+                }
+            }
+        },
+        "models.WebhookSubscriptionInput": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "По умолчанию true",
+                    "type": "boolean"
                 },
-                "typeText": {
+                "event_types": {
+                    "description": "Пусто - все типы",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Если не задан при создании, генерируется",
                     "type": "string"
                 },
-                "withoutQuotes": {
-                    "description": "\u003c-- Добавим индекс",
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "models.SimpleRegisterInfo": {
+        "models.WebhookSubscriptionWithSecret": {
             "type": "object",
             "properties": {
-                "Address": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "Name": {
+                "event_types": {
+                    "description": "Типы событий через запятую; пусто - все",
                     "type": "string"
                 },
-                "Regcode": {
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
                     "type": "string"
                 },
-                "RegtypeText": {
+                "updated_at": {
                     "type": "string"
                 },
-                "TypeText": {
+                "url": {
                     "type": "string"
                 }
             }
@@ -0,0 +1,279 @@
+// graphexport/formats.go
+package graphexport
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format - формат выгрузки графа
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	write       func(w io.Writer, graph *Graph) error
+}
+
+// Formats - поддерживаемые форматы по имени (?format=, -format)
+var Formats = map[string]Format{
+	"dot":     {Name: "dot", ContentType: "text/vnd.graphviz; charset=utf-8", Extension: "dot", write: writeDOT},
+	"graphml": {Name: "graphml", ContentType: "application/graphml+xml; charset=utf-8", Extension: "graphml", write: writeGraphML},
+	"gexf":    {Name: "gexf", ContentType: "application/gexf+xml; charset=utf-8", Extension: "gexf", write: writeGEXF},
+}
+
+// FormatNames возвращает отсортированный список форматов (для сообщений об ошибках)
+func FormatNames() []string {
+	names := make([]string, 0, len(Formats))
+	for name := range Formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write выгружает граф в формате format
+func (format Format) Write(w io.Writer, graph *Graph) error {
+	return format.write(w, graph)
+}
+
+// --- DOT (Graphviz) ---
+
+// writeDOT пишет орграф. Graphviz (dot) принимает только целый weight, поэтому
+// weight - округленная доля, а точное значение - в атрибуте share.
+func writeDOT(w io.Writer, graph *Graph) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "digraph %s {\n", dotQuote("company_"+graph.Root))
+	fmt.Fprintln(out, "\tnode [fontname=\"Helvetica\"];")
+	for _, node := range graph.Nodes {
+		shape := "ellipse"
+		if node.Kind != NodePerson {
+			shape = "box"
+		}
+		fmt.Fprintf(out, "\t%s [label=%s, shape=%s, kind=%s", dotQuote(node.ID), dotQuote(node.Label), shape, dotQuote(node.Kind))
+		if node.Regcode != "" {
+			fmt.Fprintf(out, ", regcode=%s", dotQuote(node.Regcode))
+		}
+		fmt.Fprintln(out, "];")
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(out, "\t%s -> %s [kind=%s", dotQuote(edge.Source), dotQuote(edge.Target), dotQuote(edge.Kind))
+		if edge.Label != "" {
+			fmt.Fprintf(out, ", label=%s", dotQuote(edge.Label))
+		}
+		if edge.Weight != nil {
+			fmt.Fprintf(out, ", weight=%d, share=%s", int(math.Round(*edge.Weight)), formatFloat(*edge.Weight))
+		}
+		fmt.Fprintln(out, "];")
+	}
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// dotQuote - строка DOT в кавычках с экранированием
+func dotQuote(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ", "\r", " ").Replace(value)
+	return `"` + value + `"`
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// --- GraphML ---
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func writeGraphML(w io.Writer, graph *Graph) error {
+	document := graphMLDocument{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "kind", For: "node", Name: "kind", Type: "string"},
+			{ID: "regcode", For: "node", Name: "regcode", Type: "string"},
+			{ID: "entity_type", For: "node", Name: "entity_type", Type: "string"},
+			{ID: "edge_kind", For: "edge", Name: "kind", Type: "string"},
+			{ID: "edge_label", For: "edge", Name: "label", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "double"},
+		},
+		Graph: graphMLGraph{ID: "company_" + graph.Root, EdgeDefault: "directed"},
+	}
+	for _, node := range graph.Nodes {
+		data := []graphMLData{{Key: "label", Value: node.Label}, {Key: "kind", Value: node.Kind}}
+		if node.Regcode != "" {
+			data = append(data, graphMLData{Key: "regcode", Value: node.Regcode})
+		}
+		if node.EntityType != "" {
+			data = append(data, graphMLData{Key: "entity_type", Value: node.EntityType})
+		}
+		document.Graph.Nodes = append(document.Graph.Nodes, graphMLNode{ID: node.ID, Data: data})
+	}
+	for _, edge := range graph.Edges {
+		data := []graphMLData{{Key: "edge_kind", Value: edge.Kind}}
+		if edge.Label != "" {
+			data = append(data, graphMLData{Key: "edge_label", Value: edge.Label})
+		}
+		if edge.Weight != nil {
+			data = append(data, graphMLData{Key: "weight", Value: formatFloat(*edge.Weight)})
+		}
+		document.Graph.Edges = append(document.Graph.Edges, graphMLEdge{ID: edge.ID, Source: edge.Source, Target: edge.Target, Data: data})
+	}
+	return writeXML(w, document)
+}
+
+// --- GEXF 1.3 (Gephi) ---
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator     string `xml:"creator"`
+	Description string `xml:"description"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr,omitempty"`
+	Weight    string         `xml:"weight,attr,omitempty"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func writeGEXF(w io.Writer, graph *Graph) error {
+	document := gexfDocument{
+		Xmlns:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Meta: gexfMeta{
+			Creator:     "capital-view-api",
+			Description: fmt.Sprintf("Связи компании %s (глубина %d)", graph.Root, graph.Depth),
+		},
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: []gexfAttribute{
+					{ID: "kind", Title: "kind", Type: "string"},
+					{ID: "regcode", Title: "regcode", Type: "string"},
+					{ID: "entity_type", Title: "entity_type", Type: "string"},
+				}},
+				{Class: "edge", Attributes: []gexfAttribute{
+					{ID: "kind", Title: "kind", Type: "string"},
+				}},
+			},
+		},
+	}
+	for _, node := range graph.Nodes {
+		values := []gexfAttValue{{For: "kind", Value: node.Kind}}
+		if node.Regcode != "" {
+			values = append(values, gexfAttValue{For: "regcode", Value: node.Regcode})
+		}
+		if node.EntityType != "" {
+			values = append(values, gexfAttValue{For: "entity_type", Value: node.EntityType})
+		}
+		document.Graph.Nodes = append(document.Graph.Nodes, gexfNode{ID: node.ID, Label: node.Label, AttValues: values})
+	}
+	for _, edge := range graph.Edges {
+		gexf := gexfEdge{
+			ID:        edge.ID,
+			Source:    edge.Source,
+			Target:    edge.Target,
+			Label:     edge.Label,
+			AttValues: []gexfAttValue{{For: "kind", Value: edge.Kind}},
+		}
+		if edge.Weight != nil {
+			gexf.Weight = formatFloat(*edge.Weight)
+		}
+		document.Graph.Edges = append(document.Graph.Edges, gexf)
+	}
+	return writeXML(w, document)
+}
+
+func writeXML(w io.Writer, document interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
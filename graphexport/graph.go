@@ -0,0 +1,292 @@
+// graphexport/graph.go
+// Пакет graphexport строит подграф связей вокруг компании (участники, бенефициары,
+// должностные лица) и выгружает его в DOT, GraphML и GEXF для Graphviz/Gephi.
+// Используется эндпоинтом /company/:regcode/graph и командой импортера export-graph.
+package graphexport
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultDepth = 2
+	MaxDepth     = 5
+	MaxNodes     = 5000 // Ограничение размера подграфа
+)
+
+// Типы узлов
+const (
+	NodeCompany = "company" // Юр. лицо из registers (или с regcode)
+	NodePerson  = "person"  // Физ. лицо
+	NodeEntity  = "entity"  // Юр. лицо без regcode (обычно иностранное)
+)
+
+// Типы ребер: всегда от лица к компании
+const (
+	EdgeMember          = "member"
+	EdgeBeneficialOwner = "beneficial_owner"
+	EdgeOfficer         = "officer"
+)
+
+// ErrCompanyNotFound - компании нет в registers и у нее нет связей
+var ErrCompanyNotFound = errors.New("компания с таким regcode не найдена")
+
+// Node - узел графа
+type Node struct {
+	ID         string
+	Label      string
+	Kind       string
+	Regcode    string
+	EntityType string
+}
+
+// Edge - связь лица с компанией. Weight - доля в капитале, % (только для участников с известной долей).
+type Edge struct {
+	ID     string
+	Source string
+	Target string
+	Kind   string
+	Label  string
+	Weight *float64
+
+	memberID uint // Для ребер участников - members.id (для расчета доли)
+}
+
+// Graph - подграф вокруг компании Root
+type Graph struct {
+	Root      string
+	Depth     int
+	Truncated bool // Подграф обрезан по MaxNodes
+	Nodes     []Node
+	Edges     []Edge
+
+	nodeIndex map[string]int
+	edgeIndex map[string]bool
+}
+
+func (g *Graph) addNode(node Node) {
+	if _, ok := g.nodeIndex[node.ID]; ok {
+		return
+	}
+	if len(g.Nodes) >= MaxNodes {
+		g.Truncated = true
+		return
+	}
+	g.nodeIndex[node.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, node)
+}
+
+func (g *Graph) addEdge(edge Edge) {
+	_, hasSource := g.nodeIndex[edge.Source]
+	_, hasTarget := g.nodeIndex[edge.Target]
+	if g.edgeIndex[edge.ID] || !hasSource || !hasTarget {
+		return
+	}
+	g.edgeIndex[edge.ID] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+// Build собирает подграф: от компании regcode по связям members (в обе стороны) на depth шагов
+// по компаниям; для каждой раскрытой компании добавляются ее бенефициары и должностные лица.
+func Build(database *gorm.DB, regcode string, depth int) (*Graph, error) {
+	graph := &Graph{
+		Root:      regcode,
+		Depth:     depth,
+		nodeIndex: make(map[string]int),
+		edgeIndex: make(map[string]bool),
+	}
+	graph.addNode(Node{ID: companyNodeID(regcode), Label: regcode, Kind: NodeCompany, Regcode: regcode})
+
+	var registered int64
+	if err := database.Model(&models.Registers{}).Where("regcode = ?", regcode).Count(&registered).Error; err != nil {
+		return nil, err
+	}
+
+	var members []models.Member
+	seenMembers := make(map[uint]bool)
+	visited := map[string]bool{regcode: true}
+	frontier := []string{regcode}
+
+	for level := 0; level < depth && len(frontier) > 0 && !graph.Truncated; level++ {
+		var levelMembers []models.Member
+		err := database.
+			Where("at_legal_entity_registration_number IN ? OR legal_entity_registration_number IN ?", frontier, frontier).
+			Order("id asc").
+			Find(&levelMembers).Error
+		if err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, member := range levelMembers {
+			if seenMembers[member.ID] {
+				continue
+			}
+			seenMembers[member.ID] = true
+
+			company := strings.TrimSpace(utils.StringValue(member.AtLegalEntityRegistrationNumber))
+			owner := strings.TrimSpace(utils.StringValue(member.LegalEntityRegistrationNumber))
+			if company == "" {
+				continue
+			}
+			members = append(members, member)
+			for _, neighbour := range []string{company, owner} {
+				if neighbour != "" && !visited[neighbour] {
+					visited[neighbour] = true
+					next = append(next, neighbour)
+				}
+			}
+			graph.addNode(Node{ID: companyNodeID(company), Label: company, Kind: NodeCompany, Regcode: company})
+			source := partyNode(member.EntityType, member.LegalEntityRegistrationNumber, member.Name, member.LatvianIdentityNumberMasked)
+			graph.addNode(source)
+			graph.addEdge(Edge{
+				ID:       "member:" + strconv.FormatUint(uint64(member.ID), 10),
+				Source:   source.ID,
+				Target:   companyNodeID(company),
+				Kind:     EdgeMember,
+				memberID: member.ID,
+			})
+		}
+
+		if err := addBeneficialOwners(database, graph, frontier); err != nil {
+			return nil, err
+		}
+		if err := addOfficers(database, graph, frontier); err != nil {
+			return nil, err
+		}
+		frontier = next
+	}
+
+	if err := setCompanyLabels(database, graph); err != nil {
+		return nil, err
+	}
+	if registered == 0 && len(graph.Edges) == 0 {
+		return nil, ErrCompanyNotFound
+	}
+	if err := setMemberWeights(database, graph, members); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// addBeneficialOwners добавляет бенефициаров компаний companies
+func addBeneficialOwners(database *gorm.DB, graph *Graph, companies []string) error {
+	var owners []models.BeneficialOwner
+	if err := database.Where("legal_entity_registration_number IN ?", companies).Order("id asc").Find(&owners).Error; err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		name := strings.TrimSpace(utils.StringValue(owner.Forename) + " " + utils.StringValue(owner.Surname))
+		person := partyNode(nil, nil, &name, owner.LatvianIdentityNumberMasked)
+		graph.addNode(person)
+		graph.addEdge(Edge{
+			ID:     "beneficial_owner:" + strconv.FormatUint(uint64(owner.ID), 10),
+			Source: person.ID,
+			Target: companyNodeID(utils.StringValue(owner.LegalEntityRegistrationNumber)),
+			Kind:   EdgeBeneficialOwner,
+		})
+	}
+	return nil
+}
+
+// addOfficers добавляет должностных лиц компаний companies (подпись ребра - должность)
+func addOfficers(database *gorm.DB, graph *Graph, companies []string) error {
+	var officers []models.Officer
+	if err := database.Where("at_legal_entity_registration_number IN ?", companies).Order("id asc").Find(&officers).Error; err != nil {
+		return err
+	}
+	for _, officer := range officers {
+		party := partyNode(officer.EntityType, officer.LegalEntityRegistrationNumber, officer.Name, officer.LatvianIdentityNumberMasked)
+		graph.addNode(party)
+		graph.addEdge(Edge{
+			ID:     "officer:" + strconv.FormatUint(uint64(officer.ID), 10),
+			Source: party.ID,
+			Target: companyNodeID(utils.StringValue(officer.AtLegalEntityRegistrationNumber)),
+			Kind:   EdgeOfficer,
+			Label:  utils.StringValue(officer.Position),
+		})
+	}
+	return nil
+}
+
+// setCompanyLabels подставляет названия компаний из registers
+func setCompanyLabels(database *gorm.DB, graph *Graph) error {
+	var regcodes []string
+	for _, node := range graph.Nodes {
+		if node.Kind == NodeCompany {
+			regcodes = append(regcodes, node.Regcode)
+		}
+	}
+	var registers []models.Registers
+	if err := database.Select("regcode", "name").Where("regcode IN ?", regcodes).Find(&registers).Error; err != nil {
+		return err
+	}
+	for _, register := range registers {
+		if register.Regcode == nil || register.Name == nil {
+			continue
+		}
+		if index, ok := graph.nodeIndex[companyNodeID(*register.Regcode)]; ok {
+			graph.Nodes[index].Label = *register.Name
+		}
+	}
+	return nil
+}
+
+// setMemberWeights проставляет долю в капитале на ребрах участников.
+// Доля считается по всем участникам компании, поэтому они догружаются целиком.
+func setMemberWeights(database *gorm.DB, graph *Graph, members []models.Member) error {
+	var companies []string
+	seen := make(map[string]bool)
+	for _, member := range members {
+		company := strings.TrimSpace(utils.StringValue(member.AtLegalEntityRegistrationNumber))
+		if company != "" && !seen[company] {
+			seen[company] = true
+			companies = append(companies, company)
+		}
+	}
+	if len(companies) == 0 {
+		return nil
+	}
+	var allMembers []models.Member
+	if err := database.Where("at_legal_entity_registration_number IN ?", companies).Find(&allMembers).Error; err != nil {
+		return err
+	}
+	percents := models.MemberOwnershipPercents(allMembers)
+	for i := range graph.Edges {
+		edge := &graph.Edges[i]
+		if edge.Kind != EdgeMember {
+			continue
+		}
+		if percent := percents[edge.memberID]; percent != nil {
+			edge.Weight = percent
+			edge.Label = strconv.FormatFloat(math.Round(*percent*100)/100, 'f', -1, 64) + "%"
+		}
+	}
+	return nil
+}
+
+func companyNodeID(regcode string) string {
+	return "company:" + regcode
+}
+
+// partyNode - узел участника/бенефициара/должностного лица: компания по regcode,
+// физ. лицо по маскированному коду и имени, иначе юр. лицо без regcode по имени
+func partyNode(entityType, regcode, name, masked *string) Node {
+	if value := strings.TrimSpace(utils.StringValue(regcode)); value != "" {
+		return Node{ID: companyNodeID(value), Label: value, Kind: NodeCompany, Regcode: value, EntityType: utils.StringValue(entityType)}
+	}
+	label := strings.TrimSpace(utils.StringValue(name))
+	kind := utils.StringValue(entityType)
+	if kind == "" || kind == "NATURAL_PERSON" {
+		return Node{ID: "person:" + utils.PersonKey(label, masked), Label: label, Kind: NodePerson, EntityType: kind}
+	}
+	return Node{ID: "entity:" + utils.PersonKey(label, nil), Label: label, Kind: NodeEntity, EntityType: kind}
+}
@@ -0,0 +1,278 @@
+// handlers/address_handlers.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// defaultHotspotMinCompanies - по умолчанию в рейтинг попадают адреса хотя бы с двумя действующими компаниями
+const defaultHotspotMinCompanies = 2
+
+// GetAddressHotspots godoc
+// @Summary Адреса массовой регистрации
+// @Description Рейтинг адресов регистрации (Addressid) по числу действующих компаний. Для каждого адреса возвращает число действующих и всех компаний, разбивку действующих по типам, даты первой и последней регистрации, разброс в днях и число регистраций за последний год. Адреса, где зарегистрированы сотни компаний, - типичный признак фирм-однодневок. Фильтры компаний (type, region, city, registered_from, registered_to и др.) применяются и к рейтингу, и к сводке.
+// @Tags addresses
+// @Produce json
+// @Param min_companies query int false "Минимальное число действующих компаний по адресу" default(2) minimum(1)
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Param type query string false "Фильтр по типу компании, например SIA,AS"
+// @Param region query string false "Код региона (несколько через запятую)"
+// @Param city query string false "Код города (несколько через запятую)"
+// @Param registered_from query string false "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param registered_to query string false "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)"
+// @Success 200 {object} models.PaginatedResponse{data=[]models.AddressSummary} "Адреса по убыванию числа действующих компаний"
+// @Failure 400 {object} HTTPError "Неверный min_companies или фильтр"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /addresses/hotspots [get]
+func GetAddressHotspots(c *gin.Context) {
+	pagination := utils.GetPaginationParams(c)
+
+	minCompanies := defaultHotspotMinCompanies
+	if value := c.Query("min_companies"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("min_companies должен быть целым числом не меньше 1")))
+			return
+		}
+		minCompanies = parsed
+	}
+
+	rankQuery, err := applyRegisterFilters(c, db.DB.Table("registers").
+		Where("registers.addressid IS NOT NULL AND registers.addressid <> ''").
+		Where(activeRegisterCondition))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	rankQuery = rankQuery.
+		Select("registers.addressid AS addressid, COUNT(*) AS active_companies").
+		Group("registers.addressid").
+		Having("COUNT(*) >= ?", minCompanies)
+
+	var totalRecords int64
+	if err := db.DB.Table("(?) AS hotspots", rankQuery).Count(&totalRecords).Error; err != nil {
+		log.Printf("GetAddressHotspots: Error counting addresses: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	var ranked []struct {
+		Addressid       string
+		ActiveCompanies int64
+	}
+	if err := rankQuery.
+		Order("active_companies DESC").
+		Order("registers.addressid").
+		Limit(pagination.Limit).
+		Offset(pagination.Offset).
+		Scan(&ranked).Error; err != nil {
+		log.Printf("GetAddressHotspots: Error ranking addresses: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	hotspots := make([]models.AddressSummary, 0, len(ranked))
+	if len(ranked) > 0 {
+		addressids := make([]string, len(ranked))
+		for i, row := range ranked {
+			addressids[i] = row.Addressid
+		}
+		// Фильтры уже проверены при построении рейтинга
+		summaryQuery, _ := applyRegisterFilters(c, db.DB.Table("registers"))
+		summaries, err := summarizeAddresses(summaryQuery, addressids)
+		if err != nil {
+			log.Printf("GetAddressHotspots: Error summarizing addresses: %v", err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		for _, addressid := range addressids {
+			if summary, ok := summaries[addressid]; ok {
+				hotspots = append(hotspots, *summary)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         hotspots,
+	})
+}
+
+// GetAddressCompanies godoc
+// @Summary Компании по адресу регистрации
+// @Description Возвращает сводку по адресу (число действующих и всех компаний, разбивка действующих по типам, даты первой и последней регистрации и разброс в днях) и страницу компаний, зарегистрированных по этому Addressid, начиная с последних зарегистрированных. Сводка считается по всем компаниям адреса, фильтры (status, type, registered_from и др.) применяются только к списку.
+// @Tags addresses
+// @Produce json
+// @Param addressid path string true "Addressid адреса"
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Param status query string false "Статус компании" Enums(active, terminated, closed)
+// @Param type query string false "Фильтр по типу компании, например SIA,AS"
+// @Param registered_from query string false "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param registered_to query string false "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)"
+// @Success 200 {object} models.AddressCompanies{companies=models.PaginatedResponse{data=[]models.AddressCompany}} "Сводка и компании по адресу"
+// @Failure 400 {object} HTTPError "Неверный Addressid или фильтр"
+// @Failure 404 {object} HTTPError "По адресу нет компаний"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /addresses/{addressid}/companies [get]
+func GetAddressCompanies(c *gin.Context) {
+	addressid := c.Param("addressid")
+	if addressid == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("addressid не может быть пустым")))
+		return
+	}
+	pagination := utils.GetPaginationParams(c)
+
+	queryBuilder, err := applyRegisterFilters(c, db.DB.Table("registers").Where("registers.addressid = ?", addressid))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	summaries, err := summarizeAddresses(db.DB.Table("registers"), []string{addressid})
+	if err != nil {
+		log.Printf("GetAddressCompanies: Error summarizing address %s: %v", addressid, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	summary, ok := summaries[addressid]
+	if !ok {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("по этому addressid не зарегистрировано ни одной компании")))
+		return
+	}
+
+	var totalRecords int64
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("GetAddressCompanies: Error counting companies at address %s: %v", addressid, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	companies := []models.AddressCompany{}
+	if err := queryBuilder.
+		Select("registers.regcode, registers.name, registers.regtype, registers.type, registers.type_text, registers.registered, registers.terminated, registers.closed").
+		Order("(registers.registered IS NULL), registers.registered DESC, registers.regcode").
+		Limit(pagination.Limit).
+		Offset(pagination.Offset).
+		Scan(&companies).Error; err != nil {
+		log.Printf("GetAddressCompanies: Error loading companies at address %s: %v", addressid, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	for i := range companies {
+		companies[i].Active = isActiveRegister(companies[i].Terminated, companies[i].Closed)
+	}
+
+	c.JSON(http.StatusOK, models.AddressCompanies{
+		Summary: *summary,
+		Companies: models.PaginatedResponse{
+			TotalRecords: totalRecords,
+			Page:         pagination.Page,
+			Limit:        pagination.Limit,
+			Data:         companies,
+		},
+	})
+}
+
+// summarizeAddresses считает сводку по адресам из addressids. query - запрос по registers
+// (с фильтрами или без), в сводку попадают только найденные им компании.
+func summarizeAddresses(query *gorm.DB, addressids []string) (map[string]*models.AddressSummary, error) {
+	var rows []struct {
+		Addressid  string
+		Address    *string
+		Type       *string
+		TypeText   *string
+		Registered *time.Time
+		Terminated *time.Time
+		Closed     *string
+	}
+	err := query.
+		Select("registers.addressid, registers.address, registers.type, registers.type_text, registers.registered, registers.terminated, registers.closed").
+		Where("registers.addressid IN ?", addressids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	yearAgo := time.Now().AddDate(-1, 0, 0)
+	summaries := make(map[string]*models.AddressSummary)
+	byType := make(map[string]map[string]*models.AddressTypeCount)
+	for _, row := range rows {
+		summary, ok := summaries[row.Addressid]
+		if !ok {
+			summary = &models.AddressSummary{Addressid: row.Addressid, ByType: []models.AddressTypeCount{}}
+			summaries[row.Addressid] = summary
+			byType[row.Addressid] = make(map[string]*models.AddressTypeCount)
+		}
+		if summary.Address == nil {
+			summary.Address = row.Address
+		}
+		summary.TotalCompanies++
+		if !isActiveRegister(row.Terminated, row.Closed) {
+			continue
+		}
+		summary.ActiveCompanies++
+
+		typeKey := ""
+		if row.Type != nil {
+			typeKey = *row.Type
+		}
+		count, ok := byType[row.Addressid][typeKey]
+		if !ok {
+			count = &models.AddressTypeCount{Type: row.Type, TypeText: row.TypeText}
+			byType[row.Addressid][typeKey] = count
+		}
+		count.Companies++
+
+		if row.Registered != nil {
+			registered := *row.Registered
+			if summary.FirstRegistered == nil || registered.Before(*summary.FirstRegistered) {
+				summary.FirstRegistered = &registered
+			}
+			if summary.LastRegistered == nil || registered.After(*summary.LastRegistered) {
+				summary.LastRegistered = &registered
+			}
+			if registered.After(yearAgo) {
+				summary.RegisteredLastYear++
+			}
+		}
+	}
+
+	for addressid, summary := range summaries {
+		for _, count := range byType[addressid] {
+			summary.ByType = append(summary.ByType, *count)
+		}
+		sort.Slice(summary.ByType, func(i, j int) bool {
+			a, b := summary.ByType[i], summary.ByType[j]
+			if a.Companies != b.Companies {
+				return a.Companies > b.Companies
+			}
+			return utils.StringValue(a.Type) < utils.StringValue(b.Type)
+		})
+		if summary.FirstRegistered != nil {
+			days := int(summary.LastRegistered.Sub(*summary.FirstRegistered).Hours() / 24)
+			summary.SpreadDays = &days
+		}
+	}
+	return summaries, nil
+}
+
+// isActiveRegister - та же проверка, что activeRegisterCondition, для загруженной записи
+func isActiveRegister(terminated *time.Time, closed *string) bool {
+	return terminated == nil && (closed == nil || *closed == "")
+}
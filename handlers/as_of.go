@@ -0,0 +1,112 @@
+// handlers/as_of.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseAsOf разбирает ?as_of= (nil - текущее состояние)
+func parseAsOf(c *gin.Context) (*time.Time, error) {
+	value := strings.TrimSpace(c.Query("as_of"))
+	if value == "" {
+		return nil, nil
+	}
+	asOf, err := utils.ParseDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("неверный as_of: %w", err)
+	}
+	return &asOf, nil
+}
+
+// loadVersionsAsOf загружает версии записей entity компании regcode, действовавшие на дату asOf
+// (valid_from <= asOf < valid_to), и раскладывает их JSON в target - указатель на срез моделей
+func loadVersionsAsOf(entity, regcode string, asOf time.Time, target interface{}) error {
+	var data []string
+	err := db.DB.Model(&models.EntityVersion{}).
+		Where("entity = ? AND regcode = ?", entity, regcode).
+		Where("(valid_from IS NULL OR valid_from <= ?) AND (valid_to IS NULL OR valid_to > ?)", asOf, asOf).
+		Order("id").
+		Pluck("data", &data).Error
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte("["+strings.Join(data, ",")+"]"), target)
+}
+
+// membersAsOf - участники компании на дату asOf с долями в капитале на ту же дату
+func membersAsOf(regcode string, asOf time.Time) ([]models.Member, error) {
+	members := []models.Member{}
+	if err := loadVersionsAsOf("members", regcode, asOf, &members); err != nil {
+		return nil, err
+	}
+	percents := models.MemberOwnershipPercents(members)
+	for i := range members {
+		members[i].OwnershipPercent = percents[members[i].ID]
+	}
+	return members, nil
+}
+
+// pageOf возвращает страницу уже загруженного списка
+func pageOf(total int, pagination utils.PaginationParams) (start, end int) {
+	start = pagination.Offset
+	if start > total {
+		start = total
+	}
+	end = start + pagination.Limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// companyAsOf собирает компанию на дату asOf: реквизиты, участники, бенефициары и должностные лица -
+// из версий записей, фин. отчеты - за годы, закончившиеся не позже asOf.
+// nil - компании на эту дату не было.
+func companyAsOf(regcode string, asOf time.Time) (*models.Registers, error) {
+	companies := []models.Registers{}
+	if err := loadVersionsAsOf("registers", regcode, asOf, &companies); err != nil {
+		return nil, err
+	}
+	if len(companies) == 0 {
+		return nil, nil
+	}
+	company := companies[0]
+
+	members, err := membersAsOf(regcode, asOf)
+	if err != nil {
+		return nil, err
+	}
+	company.Members = members
+
+	company.BeneficialOwners = []models.BeneficialOwner{}
+	if err := loadVersionsAsOf("beneficial_owners", regcode, asOf, &company.BeneficialOwners); err != nil {
+		return nil, err
+	}
+
+	company.Officers = []models.Officer{}
+	if err := loadVersionsAsOf("officers", regcode, asOf, &company.Officers); err != nil {
+		return nil, err
+	}
+
+	err = db.DB.
+		Preload("IncomeStatement").
+		Preload("BalanceSheet").
+		Preload("CashFlowStatement").
+		Where("legal_entity_registration_number = ?", regcode).
+		Where("year_ended_on IS NULL OR year_ended_on <= ?", asOf).
+		Order("year DESC").
+		Find(&company.FinancialStatements).Error
+	if err != nil {
+		return nil, err
+	}
+	return &company, nil
+}
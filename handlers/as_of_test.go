@@ -0,0 +1,104 @@
+// handlers/as_of_test.go
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useTestSQLite подменяет db.DB временной базой SQLite с таблицами models на время теста
+func useTestSQLite(t *testing.T, tables ...interface{}) *gorm.DB {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AutoMigrate(tables...); err != nil {
+		t.Fatal(err)
+	}
+	previous := db.DB
+	db.DB = database
+	t.Cleanup(func() {
+		db.DB = previous
+		if sqlDB, err := database.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return database
+}
+
+func TestLoadVersionsAsOfBoundaries(t *testing.T) {
+	database := useTestSQLite(t, &models.EntityVersion{})
+	changed := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	versions := []models.EntityVersion{
+		// Участник 1: доля изменилась 01.05.2023
+		{Entity: "members", EntityID: "1", Regcode: ptr("40000000001"), ValidFrom: ptr(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), ValidTo: &changed, Data: `{"id":1,"number_of_shares":10}`},
+		{Entity: "members", EntityID: "1", Regcode: ptr("40000000001"), ValidFrom: &changed, Data: `{"id":1,"number_of_shares":20}`},
+		// Участник 2: дата вступления неизвестна, вышел 01.01.2021
+		{Entity: "members", EntityID: "2", Regcode: ptr("40000000001"), ValidTo: ptr(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)), Data: `{"id":2}`},
+		// Другая компания и другая таблица не попадают в выборку
+		{Entity: "members", EntityID: "3", Regcode: ptr("40000000002"), Data: `{"id":3}`},
+		{Entity: "beneficial_owners", EntityID: "4", Regcode: ptr("40000000001"), Data: `{"id":4}`},
+	}
+	if err := database.Create(&versions).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		asOf   string
+		want   []uint
+		shares []float64 // number_of_shares участника 1, если он есть в выборке
+	}{
+		{"2019-12-31", []uint{2}, nil},
+		{"2020-01-01", []uint{1, 2}, []float64{10}}, // valid_from включается
+		{"2020-12-31", []uint{1, 2}, []float64{10}},
+		{"2021-01-01", []uint{1}, []float64{10}}, // valid_to не включается
+		{"2023-04-30", []uint{1}, []float64{10}},
+		{"2023-05-01", []uint{1}, []float64{20}}, // Граница версий: только новая
+		{"2030-01-01", []uint{1}, []float64{20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.asOf, func(t *testing.T) {
+			asOf, err := utils.ParseDate(tt.asOf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			members := []models.Member{}
+			if err := loadVersionsAsOf("members", "40000000001", asOf, &members); err != nil {
+				t.Fatal(err)
+			}
+			var ids []uint
+			var shares []float64
+			for _, member := range members {
+				ids = append(ids, member.ID)
+				if member.ID == 1 && member.NumberOfShares != nil {
+					shares = append(shares, *member.NumberOfShares)
+				}
+			}
+			if len(ids) != len(tt.want) || len(shares) != len(tt.shares) {
+				t.Fatalf("members = %v with shares %v, want %v with shares %v", ids, shares, tt.want, tt.shares)
+			}
+			for i := range tt.want {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("members = %v, want %v", ids, tt.want)
+				}
+			}
+			for i := range tt.shares {
+				if shares[i] != tt.shares[i] {
+					t.Errorf("shares = %v, want %v", shares, tt.shares)
+				}
+			}
+		})
+	}
+}
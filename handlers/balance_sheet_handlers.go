@@ -9,6 +9,7 @@ import (
 
 	"capital-view-api/db"
 	"capital-view-api/models"
+	"capital-view-api/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -16,13 +17,15 @@ import (
 
 // GetCompanyDetailsByRegcode godoc
 // @Summary Получить полную информацию о компании по Regcode
-// @Description Получает детальную информацию о компании, включая участников, бенефициаров и фин. отчеты, по её точному Regcode.
+// @Description Получает детальную информацию о компании, включая участников, бенефициаров, должностных лиц и фин. отчеты, по её точному Regcode. С as_of компания восстанавливается на дату: реквизиты, участники, бенефициары и должностные лица - по версиям записей, фин. отчеты - за годы, закончившиеся не позже нее.
 // @Tags company
 // @Produce json
 // @Param regcode path string true "Regcode компании"
+// @Param as_of query string false "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param normalize query string false "eur - привести суммы отчетов к единицам евро (rounded_to_nearest и LVL по курсу 0.702804)" Enums(eur)
 // @Success 200 {object} models.Registers "Полная информация о компании (с вложенными данными)"
-// @Failure 400 {object} HTTPError "Неверный или отсутствующий Regcode"
-// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 400 {object} HTTPError "Неверный или отсутствующий Regcode, неверный normalize или as_of"
+// @Failure 404 {object} HTTPError "Компания не найдена (или не существовала на дату as_of)"
 // @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
 // @Router /company/{regcode} [get] // <-- Новый роут
 func GetCompanyDetailsByRegcode(c *gin.Context) {
@@ -31,13 +34,43 @@ func GetCompanyDetailsByRegcode(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
 		return
 	}
+	normalize, err := parseNormalizeParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
 	log.Printf("GetCompanyDetailsByRegcode: Fetching details for regcode: %s", regcode)
 
+	// Состояние на дату собирается из версий записей
+	if asOf != nil {
+		company, err := companyAsOf(regcode, *asOf)
+		if err != nil {
+			log.Printf("GetCompanyDetailsByRegcode: Error fetching details for %s as of %s: %v", regcode, asOf.Format("2006-01-02"), err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка получения данных компании: %w", err)))
+			return
+		}
+		if company == nil {
+			c.JSON(http.StatusNotFound, NewHTTPError(fmt.Errorf("компании с таким regcode на %s не было", asOf.Format("02.01.2006"))))
+			return
+		}
+		if normalize {
+			normalizeCompanyStatements(company)
+		}
+		c.JSON(http.StatusOK, company)
+		return
+	}
+
 	var company models.Registers // Используем основную модель Registers
-	err := db.DB.
+	err = db.DB.
 		// Предзагружаем все необходимые связанные данные
 		Preload("Members").
 		Preload("BeneficialOwners").
+		Preload("Officers").
 		Preload("FinancialStatements", func(db *gorm.DB) *gorm.DB {
 			return db.Order("financial_statements.year DESC") // Сортируем отчеты
 		}).
@@ -58,7 +91,27 @@ func GetCompanyDetailsByRegcode(c *gin.Context) {
 		return
 	}
 
+	if err := applyOwnershipPercents(company.Members); err != nil {
+		log.Printf("GetCompanyDetailsByRegcode: Error computing ownership percentages for %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка расчета долей участников: %w", err)))
+		return
+	}
+
+	// Приводим суммы к евро, если запрошено (?normalize=eur)
+	if normalize {
+		normalizeCompanyStatements(&company)
+	}
+
 	log.Printf("GetCompanyDetailsByRegcode: Successfully fetched details for regcode %s", regcode)
 	// Возвращаем найденный объект Registers со всеми предзагруженными данными
 	c.JSON(http.StatusOK, company)
 }
+
+// normalizeCompanyStatements приводит суммы всех отчетов компании к евро
+func normalizeCompanyStatements(company *models.Registers) {
+	for i := range company.FinancialStatements {
+		if !normalizeStatementToEUR(&company.FinancialStatements[i]) {
+			log.Printf("GetCompanyDetailsByRegcode: Statement %d of %s left as is: unknown currency/rounding", company.FinancialStatements[i].ID, utils.StringValue(company.Regcode))
+		}
+	}
+}
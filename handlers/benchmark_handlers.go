@@ -0,0 +1,204 @@
+// handlers/benchmark_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// benchmarkMetrics - показатели сравнения (колонки company_metrics) в порядке вывода
+var benchmarkMetrics = []string{"net_turnover", "net_margin", "equity_ratio", "employees", "revenue_per_employee"}
+
+// benchmarkPeerColumns - признаки группы аналогов и соответствующие колонки company_metrics
+var benchmarkPeerColumns = map[string]string{
+	"type":   "type",
+	"region": "region",
+	"size":   "size_band",
+}
+
+// GetCompanyBenchmark godoc
+// @Summary Сравнение компании с аналогами
+// @Description Показывает процентильный ранг выручки, чистой маржи, доли собственного капитала, числа сотрудников и выручки на сотрудника среди компаний того же года с тем же типом, регионом и/или размерной группой по числу сотрудников (micro 0-9, small 10-49, medium 50-249, large 250+). Считается по таблице company_metrics, которая пересчитывается после каждого запуска импортера; суммы в единицах евро.
+// @Tags company
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param year query int false "Год отчетности (по умолчанию последний)"
+// @Param peer query string false "Признаки группы аналогов через запятую: type, region, size" default(type)
+// @Success 200 {object} models.CompanyBenchmark "Положение компании в группе аналогов"
+// @Failure 400 {object} HTTPError "Неверный Regcode, год или признак группы"
+// @Failure 404 {object} HTTPError "Нет фин. отчета компании за год"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/benchmark [get]
+func GetCompanyBenchmark(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	peerBy := splitFilterValues(c.DefaultQuery("peer", "type"))
+	if len(peerBy) == 0 {
+		peerBy = []string{"type"}
+	}
+	for _, by := range peerBy {
+		if _, ok := benchmarkPeerColumns[by]; !ok {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неизвестный признак группы '%s' (допустимо: type, region, size)", by)))
+			return
+		}
+	}
+
+	companyQuery := db.DB.Where("regcode = ?", regcode)
+	if value := c.Query("year"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("year должен быть числом")))
+			return
+		}
+		companyQuery = companyQuery.Where("year = ?", year)
+	}
+
+	var company models.CompanyMetric
+	if err := companyQuery.Order("year desc").First(&company).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, NewHTTPError(errors.New("нет фин. отчета компании за этот год")))
+			return
+		}
+		log.Printf("GetCompanyBenchmark: Error loading metrics for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	// Группа аналогов: тот же год и те же значения выбранных признаков
+	peerGroup := models.BenchmarkPeerGroup{By: peerBy}
+	peers := db.DB.Model(&models.CompanyMetric{}).Where("year = ?", company.Year)
+	for _, by := range peerBy {
+		var value *string
+		switch by {
+		case "type":
+			value, peerGroup.Type = company.Type, company.Type
+		case "region":
+			value, peerGroup.Region = company.Region, company.Region
+		case "size":
+			value, peerGroup.SizeBand = company.SizeBand, company.SizeBand
+		}
+		if value == nil {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("у компании не задан признак '%s', сравнение по нему невозможно", by)))
+			return
+		}
+		peers = peers.Where(benchmarkPeerColumns[by]+" = ?", *value)
+	}
+	peers = peers.Session(&gorm.Session{})
+
+	if err := peers.Count(&peerGroup.Companies).Error; err != nil {
+		log.Printf("GetCompanyBenchmark: Error counting peers for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	response := models.CompanyBenchmark{
+		Regcode:     regcode,
+		Year:        company.Year,
+		PeerGroup:   peerGroup,
+		Metrics:     make([]models.BenchmarkMetric, 0, len(benchmarkMetrics)),
+		RefreshedAt: company.RefreshedAt,
+	}
+	for _, metric := range benchmarkMetrics {
+		result, err := benchmarkMetric(peers, metric, companyMetricValue(company, metric))
+		if err != nil {
+			log.Printf("GetCompanyBenchmark: Error computing %s for regcode %s: %v", metric, regcode, err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		response.Metrics = append(response.Metrics, result)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// companyMetricValue возвращает значение показателя по имени колонки company_metrics
+func companyMetricValue(company models.CompanyMetric, metric string) *float64 {
+	switch metric {
+	case "net_turnover":
+		return company.NetTurnover
+	case "net_margin":
+		return company.NetMargin
+	case "equity_ratio":
+		return company.EquityRatio
+	case "employees":
+		return company.Employees
+	case "revenue_per_employee":
+		return company.RevenuePerEmployee
+	}
+	return nil
+}
+
+// benchmarkMetric считает процентильный ранг value и квартили показателя в группе peers
+func benchmarkMetric(peers *gorm.DB, metric string, value *float64) (models.BenchmarkMetric, error) {
+	result := models.BenchmarkMetric{Metric: metric, Value: value}
+	withValue := peers.Where(metric + " IS NOT NULL").Session(&gorm.Session{})
+
+	if err := withValue.Count(&result.PeerCount).Error; err != nil {
+		return result, err
+	}
+	if result.PeerCount == 0 {
+		return result, nil
+	}
+
+	if value != nil {
+		var counts struct {
+			Below int64
+			Equal int64
+		}
+		err := withValue.
+			Select(fmt.Sprintf("SUM(CASE WHEN %s < ? THEN 1 ELSE 0 END) AS below, SUM(CASE WHEN %s = ? THEN 1 ELSE 0 END) AS equal", metric, metric), *value, *value).
+			Scan(&counts).Error
+		if err != nil {
+			return result, err
+		}
+		rank := (float64(counts.Below) + float64(counts.Equal)/2) / float64(result.PeerCount) * 100
+		result.PercentileRank = &rank
+	}
+
+	for _, quantile := range []struct {
+		q      float64
+		target **float64
+	}{{0.25, &result.P25}, {0.5, &result.Median}, {0.75, &result.P75}} {
+		value, err := peerQuantile(withValue, metric, result.PeerCount, quantile.q)
+		if err != nil {
+			return result, err
+		}
+		*quantile.target = value
+	}
+	return result, nil
+}
+
+// peerQuantile - квантиль q с линейной интерполяцией между соседними значениями (как PERCENTILE_CONT)
+func peerQuantile(withValue *gorm.DB, metric string, count int64, q float64) (*float64, error) {
+	position := q * float64(count-1)
+	lower := math.Floor(position)
+
+	var values []float64
+	err := withValue.
+		Order(metric+" asc").
+		Offset(int(lower)).
+		Limit(2).
+		Pluck(metric, &values).Error
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	quantile := values[0]
+	if len(values) == 2 {
+		quantile += (values[1] - values[0]) * (position - lower)
+	}
+	return &quantile, nil
+}
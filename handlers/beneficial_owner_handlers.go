@@ -15,14 +15,15 @@ import (
 
 // GetBeneficialOwnersByRegcode godoc
 // @Summary Получить бенефициаров компании по Regcode
-// @Description Возвращает пагинированный список бенефициаров (beneficial owners) для указанной компании.
+// @Description Возвращает пагинированный список бенефициаров (beneficial owners) для указанной компании. С as_of - бенефициаров на эту дату по версиям записей (entity_versions), которые ведет импортер.
 // @Tags beneficial_owner
 // @Produce json
 // @Param regcode path string true "Regcode компании"
+// @Param as_of query string false "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)"
 // @Param page query int false "Номер страницы" default(1) minimum(1)
 // @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
 // @Success 200 {object} models.PaginatedResponse{data=[]models.BeneficialOwner} "Пагинированный список бенефициаров"
-// @Failure 400 {object} HTTPError "Неверный Regcode"
+// @Failure 400 {object} HTTPError "Неверный Regcode или as_of"
 // @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
 // @Router /beneficial-owners/by-regcode/{regcode} [get] // <-- Пример роута
 func GetBeneficialOwnersByRegcode(c *gin.Context) {
@@ -32,8 +33,32 @@ func GetBeneficialOwnersByRegcode(c *gin.Context) {
 		return
 	}
 
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
 	pagination := utils.GetPaginationParams(c)
 
+	// На дату: бенефициары из версий записей
+	if asOf != nil {
+		owners := []models.BeneficialOwner{}
+		if err := loadVersionsAsOf("beneficial_owners", regcode, *asOf, &owners); err != nil {
+			log.Printf("Error loading beneficial owners of regcode %s as of %s: %v", regcode, asOf.Format("2006-01-02"), err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		start, end := pageOf(len(owners), pagination)
+		c.JSON(http.StatusOK, models.PaginatedResponse{
+			TotalRecords: int64(len(owners)),
+			Page:         pagination.Page,
+			Limit:        pagination.Limit,
+			Data:         owners[start:end],
+		})
+		return
+	}
+
 	var owners []models.BeneficialOwner
 	var totalRecords int64
 
@@ -48,7 +73,7 @@ func GetBeneficialOwnersByRegcode(c *gin.Context) {
 	}
 
 	// Получаем данные для страницы
-	err := queryBuilder.Limit(pagination.Limit).Offset(pagination.Offset).Find(&owners).Error
+	err = queryBuilder.Limit(pagination.Limit).Offset(pagination.Offset).Find(&owners).Error
 	if err != nil {
 		log.Printf("Error finding beneficial owners for regcode %s with pagination: %v", regcode, err)
 		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
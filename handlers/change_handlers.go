@@ -0,0 +1,145 @@
+// handlers/change_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCompanyHistory godoc
+// @Summary История изменений компании
+// @Description Возвращает изменения, обнаруженные импортером по компании и связанным с ней записям (участники, бенефициары, должностные лица, фин. отчеты), от новых к старым. Для update одно событие соответствует одному полю.
+// @Tags company
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param since query string false "Только изменения начиная с даты (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param entity query string false "Таблица: registers, members, beneficial_owners, officers, financial_statements, ..."
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.ChangeEvent} "Пагинированный список изменений"
+// @Failure 400 {object} HTTPError "Неверный Regcode или since"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/history [get]
+func GetCompanyHistory(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	queryBuilder, err := changeEventsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	queryBuilder = queryBuilder.Where("regcode = ?", regcode)
+
+	respondChangeEvents(c, queryBuilder, "id DESC")
+}
+
+// GetChanges godoc
+// @Summary Лента изменений
+// @Description Глобальная лента изменений, обнаруженных импортером, в порядке записи (по возрастанию id). Для инкрементальной синхронизации передавайте since_id = id последнего полученного события.
+// @Tags changes
+// @Produce json
+// @Param since query string false "Только изменения начиная с даты (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param since_id query int false "Только события с id больше указанного"
+// @Param run_id query int false "Только изменения одного запуска импортера"
+// @Param entity query string false "Таблица: registers, members, beneficial_owners, officers, financial_statements, ..."
+// @Param operation query string false "Тип изменения" Enums(insert, update, delete)
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.ChangeEvent} "Пагинированная лента изменений"
+// @Failure 400 {object} HTTPError "Неверные параметры фильтра"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /changes [get]
+func GetChanges(c *gin.Context) {
+	queryBuilder, err := changeEventsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	if sinceID := strings.TrimSpace(c.Query("since_id")); sinceID != "" {
+		id, err := strconv.ParseUint(sinceID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неверный since_id '%s'", sinceID)))
+			return
+		}
+		queryBuilder = queryBuilder.Where("id > ?", id)
+	}
+	if runID := strings.TrimSpace(c.Query("run_id")); runID != "" {
+		id, err := strconv.ParseUint(runID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неверный run_id '%s'", runID)))
+			return
+		}
+		queryBuilder = queryBuilder.Where("import_run_id = ?", id)
+	}
+	if operation := strings.TrimSpace(c.Query("operation")); operation != "" {
+		switch operation {
+		case models.ChangeOperationInsert, models.ChangeOperationUpdate, models.ChangeOperationDelete:
+			queryBuilder = queryBuilder.Where("operation = ?", operation)
+		default:
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неверный operation '%s': ожидается insert, update или delete", operation)))
+			return
+		}
+	}
+
+	respondChangeEvents(c, queryBuilder, "id ASC")
+}
+
+// changeEventsQuery - общие фильтры since и entity для обоих эндпоинтов
+func changeEventsQuery(c *gin.Context) (*gorm.DB, error) {
+	queryBuilder := db.DB.Model(&models.ChangeEvent{})
+
+	if since := strings.TrimSpace(c.Query("since")); since != "" {
+		t, err := utils.ParseDate(since)
+		if err != nil {
+			return nil, fmt.Errorf("неверный since: %w", err)
+		}
+		queryBuilder = queryBuilder.Where("created_at >= ?", t)
+	}
+	if entity := strings.TrimSpace(c.Query("entity")); entity != "" {
+		queryBuilder = queryBuilder.Where("entity = ?", entity)
+	}
+	return queryBuilder, nil
+}
+
+// respondChangeEvents считает, выбирает страницу и отдает PaginatedResponse
+func respondChangeEvents(c *gin.Context, queryBuilder *gorm.DB, order string) {
+	pagination := utils.GetPaginationParams(c)
+
+	var totalRecords int64
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting change events: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	events := []models.ChangeEvent{}
+	err := queryBuilder.Order(order).Limit(pagination.Limit).Offset(pagination.Offset).Find(&events).Error
+	if err != nil {
+		log.Printf("Error finding change events with pagination: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         events,
+	})
+}
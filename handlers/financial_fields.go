@@ -0,0 +1,84 @@
+// handlers/financial_fields.go
+package handlers
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"capital-view-api/models"
+)
+
+// financialField описывает строку фин. отчета, доступную по имени (json-имя = имя колонки в БД)
+type financialField struct {
+	Name   string // "net_turnover"
+	Source string // financial_statement | income_statement | balance_sheet | cash_flow_statement
+	Table  string // Таблица, в которой лежит колонка
+	get    func(statement *models.FinancialStatement) *float64
+}
+
+// financialFields - все строки отчетов по имени. Собирается из json-тегов моделей,
+// поэтому новые поля в IncomeStatement/BalanceSheet/CashFlowStatement подхватываются автоматически.
+var financialFields = buildFinancialFields()
+
+func buildFinancialFields() map[string]financialField {
+	fields := map[string]financialField{
+		"employees": {
+			Name: "employees", Source: "financial_statement", Table: "financial_statements",
+			get: func(statement *models.FinancialStatement) *float64 {
+				if statement.Employees == nil {
+					return nil
+				}
+				value := float64(*statement.Employees)
+				return &value
+			},
+		},
+	}
+
+	addDetailFields := func(source, table string, model interface{}, detail func(*models.FinancialStatement) reflect.Value) {
+		modelType := reflect.TypeOf(model)
+		for i := 0; i < modelType.NumField(); i++ {
+			structField := modelType.Field(i)
+			if structField.Type != int64PtrType {
+				continue // StatementID, FileID и ID - не суммы
+			}
+			name := strings.Split(structField.Tag.Get("json"), ",")[0]
+			index := i
+			fields[name] = financialField{
+				Name: name, Source: source, Table: table,
+				get: func(statement *models.FinancialStatement) *float64 {
+					value := detail(statement)
+					if !value.IsValid() || value.IsNil() {
+						return nil
+					}
+					field := value.Elem().Field(index)
+					if field.IsNil() {
+						return nil
+					}
+					amount := float64(field.Elem().Int())
+					return &amount
+				},
+			}
+		}
+	}
+	addDetailFields("income_statement", "income_statements", models.IncomeStatement{}, func(statement *models.FinancialStatement) reflect.Value {
+		return reflect.ValueOf(statement.IncomeStatement)
+	})
+	addDetailFields("balance_sheet", "balance_sheets", models.BalanceSheet{}, func(statement *models.FinancialStatement) reflect.Value {
+		return reflect.ValueOf(statement.BalanceSheet)
+	})
+	addDetailFields("cash_flow_statement", "cash_flow_statements", models.CashFlowStatement{}, func(statement *models.FinancialStatement) reflect.Value {
+		return reflect.ValueOf(statement.CashFlowStatement)
+	})
+	return fields
+}
+
+// financialFieldNames возвращает отсортированный список доступных полей (для сообщений об ошибках)
+func financialFieldNames() []string {
+	names := make([]string, 0, len(financialFields))
+	for name := range financialFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
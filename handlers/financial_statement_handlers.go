@@ -15,14 +15,15 @@ import (
 
 // GetFinancialStatementsByRegcode godoc
 // @Summary Получить фин. отчеты компании по Regcode
-// @Description Возвращает пагинированный список фин. отчетов (financial statements) для указанной компании.
+// @Description Возвращает пагинированный список фин. отчетов (financial statements) для указанной компании. С ?normalize=eur отчеты возвращаются вместе с IncomeStatement, BalanceSheet и CashFlowStatement, а их суммы приводятся к единицам евро; без него - только сами отчеты, суммы в единицах и валюте отчета (rounded_to_nearest, currency).
 // @Tags financial_statement
 // @Produce json
 // @Param regcode path string true "Regcode компании"
+// @Param normalize query string false "eur - привести суммы отчетов к единицам евро (rounded_to_nearest и LVL по курсу 0.702804)" Enums(eur)
 // @Param page query int false "Номер страницы" default(1) minimum(1)
 // @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
 // @Success 200 {object} models.PaginatedResponse{data=[]models.FinancialStatement} "Пагинированный список фин. отчетов"
-// @Failure 400 {object} HTTPError "Неверный Regcode"
+// @Failure 400 {object} HTTPError "Неверный Regcode или normalize"
 // @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
 // @Router /financial-statements/by-regcode/{regcode} [get] // <-- Пример роута
 func GetFinancialStatementsByRegcode(c *gin.Context) {
@@ -32,6 +33,12 @@ func GetFinancialStatementsByRegcode(c *gin.Context) {
 		return
 	}
 
+	normalize, err := parseNormalizeParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
 	pagination := utils.GetPaginationParams(c)
 
 	var statements []models.FinancialStatement
@@ -47,8 +54,16 @@ func GetFinancialStatementsByRegcode(c *gin.Context) {
 		return
 	}
 
+	// Детальные отчеты нужны только для пересчета в евро: без normalize ответ прежний
+	if normalize {
+		queryBuilder = queryBuilder.
+			Preload("IncomeStatement").
+			Preload("BalanceSheet").
+			Preload("CashFlowStatement")
+	}
+
 	// Получаем данные для страницы + сортировка по убыванию года
-	err := queryBuilder.Order("year desc").
+	err = queryBuilder.Order("year desc").
 		Limit(pagination.Limit).
 		Offset(pagination.Offset).
 		Find(&statements).Error
@@ -58,6 +73,15 @@ func GetFinancialStatementsByRegcode(c *gin.Context) {
 		return
 	}
 
+	// Приводим суммы к евро, если запрошено (?normalize=eur)
+	if normalize {
+		for i := range statements {
+			if !normalizeStatementToEUR(&statements[i]) {
+				log.Printf("GetFinancialStatementsByRegcode: Statement %d of %s left as is: unknown currency/rounding", statements[i].ID, regcode)
+			}
+		}
+	}
+
 	response := models.PaginatedResponse{
 		TotalRecords: totalRecords,
 		Page:         pagination.Page,
@@ -67,3 +91,24 @@ func GetFinancialStatementsByRegcode(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// loadStatementsWithDetails загружает все фин. отчеты компании (по возрастанию года)
+// вместе с IncomeStatement, BalanceSheet и CashFlowStatement
+func loadStatementsWithDetails(regcode string) ([]models.FinancialStatement, error) {
+	var statements []models.FinancialStatement
+	err := db.DB.
+		Preload("IncomeStatement").
+		Preload("BalanceSheet").
+		Preload("CashFlowStatement").
+		Where("legal_entity_registration_number = ?", regcode).
+		Order("year asc").
+		Find(&statements).Error
+	return statements, err
+}
+
+// companyExists проверяет, есть ли компания с таким regcode в registers
+func companyExists(regcode string) (bool, error) {
+	var count int64
+	err := db.DB.Model(&models.Registers{}).Where("regcode = ?", regcode).Count(&count).Error
+	return count > 0, err
+}
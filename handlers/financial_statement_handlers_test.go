@@ -0,0 +1,70 @@
+// handlers/financial_statement_handlers_test.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"capital-view-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestFinancialStatementsDetailsOnlyWhenNormalized: без normalize ответ прежний (только отчеты),
+// детальные отчеты загружаются и пересчитываются только с ?normalize=eur
+func TestFinancialStatementsDetailsOnlyWhenNormalized(t *testing.T) {
+	database := useTestSQLite(t, &models.FinancialStatement{}, &models.IncomeStatement{}, &models.BalanceSheet{}, &models.CashFlowStatement{})
+	statement := models.FinancialStatement{
+		LegalEntityRegistrationNumber: ptr("40000000001"),
+		Year:                          ptr(2023),
+		RoundedToNearest:              ptr("THOUSANDS"),
+		Currency:                      ptr("EUR"),
+		IncomeStatement:               &models.IncomeStatement{NetTurnover: ptr(int64(125))},
+	}
+	if err := database.Create(&statement).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target   string
+		turnover *int64 // nil - IncomeStatement в ответе нет
+	}{
+		{"/financial-statements/by-regcode/40000000001", nil},
+		{"/financial-statements/by-regcode/40000000001?normalize=eur", ptr(int64(125000))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+			c.Params = gin.Params{{Key: "regcode", Value: "40000000001"}}
+			GetFinancialStatementsByRegcode(c)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body.String())
+			}
+
+			var response struct {
+				Data []models.FinancialStatement `json:"data"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Data) != 1 {
+				t.Fatalf("statements = %d, want 1", len(response.Data))
+			}
+			got := response.Data[0]
+			if tt.turnover == nil {
+				if got.IncomeStatement != nil || got.BalanceSheet != nil || got.CashFlowStatement != nil {
+					t.Errorf("details without normalize = %+v, want none", got)
+				}
+				return
+			}
+			if got.IncomeStatement == nil || got.IncomeStatement.NetTurnover == nil || *got.IncomeStatement.NetTurnover != *tt.turnover {
+				t.Errorf("income statement = %+v, want net_turnover %d", got.IncomeStatement, *tt.turnover)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// handlers/graph_export_handlers.go
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/graphexport"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportCompanyGraph godoc
+// @Summary Выгрузка сети связей компании (DOT, GraphML, GEXF)
+// @Description Строит подграф вокруг компании: по связям участников-юр. лиц в обе стороны на depth шагов, с бенефициарами и должностными лицами каждой раскрытой компании. Узлы - компании (company), физ. лица (person) и юр. лица без regcode (entity); ребра направлены от лица к компании (member, beneficial_owner, officer). Вес ребра участника - его доля в капитале, %. Для Graphviz (dot), Gephi и других инструментов; то же делает команда импортера export-graph.
+// @Tags ownership
+// @Produce plain
+// @Param regcode path string true "Regcode компании"
+// @Param format query string false "Формат выгрузки" Enums(dot, graphml, gexf) default(graphml)
+// @Param depth query int false "Число шагов по компаниям" default(2) minimum(1) maximum(5)
+// @Success 200 {string} string "Граф в выбранном формате"
+// @Failure 400 {object} HTTPError "Неверный Regcode, формат или depth"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/graph [get]
+func ExportCompanyGraph(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	format, ok := graphexport.Formats[strings.ToLower(c.DefaultQuery("format", "graphml"))]
+	if !ok {
+		c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неизвестный формат '%s' (допустимо: %s)", c.Query("format"), strings.Join(graphexport.FormatNames(), ", "))))
+		return
+	}
+	depth := graphexport.DefaultDepth
+	if value := c.Query("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > graphexport.MaxDepth {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("depth должен быть числом от 1 до %d", graphexport.MaxDepth)))
+			return
+		}
+		depth = parsed
+	}
+
+	graph, err := graphexport.Build(db.DB, regcode, depth)
+	if err != nil {
+		if errors.Is(err, graphexport.ErrCompanyNotFound) {
+			c.JSON(http.StatusNotFound, NewHTTPError(err))
+			return
+		}
+		log.Printf("ExportCompanyGraph: Error building graph for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка построения графа: %w", err)))
+		return
+	}
+
+	var body bytes.Buffer
+	if err := format.Write(&body, graph); err != nil {
+		log.Printf("ExportCompanyGraph: Error writing %s for regcode %s: %v", format.Name, regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	if graph.Truncated {
+		c.Header("X-Graph-Truncated", "true")
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "company_"+regcode+"."+format.Extension))
+	c.Data(http.StatusOK, format.ContentType, body.Bytes())
+}
@@ -0,0 +1,71 @@
+// handlers/normalize.go
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// normalizeEUR - значение параметра ?normalize=, при котором суммы приводятся к единицам евро
+const normalizeEUR = "eur"
+
+// parseNormalizeParam разбирает ?normalize=. Сейчас поддерживается только "eur".
+func parseNormalizeParam(c *gin.Context) (bool, error) {
+	switch value := strings.ToLower(strings.TrimSpace(c.Query("normalize"))); value {
+	case "":
+		return false, nil
+	case normalizeEUR:
+		return true, nil
+	default:
+		return false, fmt.Errorf("неверное значение normalize '%s' (допустимо: eur)", value)
+	}
+}
+
+// normalizeStatementToEUR приводит суммы IncomeStatement, BalanceSheet и CashFlowStatement отчета
+// к целым евро (с учетом rounded_to_nearest и курса LVL) и меняет currency/rounded_to_nearest на EUR/ONES.
+// Если валюта или единицы не распознаны, отчет не меняется (возвращает false) -
+// его исходные currency/rounded_to_nearest остаются в ответе.
+func normalizeStatementToEUR(statement *models.FinancialStatement) bool {
+	multiplier, ok := utils.EURMultiplier(statement.Currency, statement.RoundedToNearest)
+	if !ok {
+		return false
+	}
+	if multiplier != 1 {
+		if statement.IncomeStatement != nil {
+			scaleAmounts(statement.IncomeStatement, multiplier)
+		}
+		if statement.BalanceSheet != nil {
+			scaleAmounts(statement.BalanceSheet, multiplier)
+		}
+		if statement.CashFlowStatement != nil {
+			scaleAmounts(statement.CashFlowStatement, multiplier)
+		}
+	}
+	currency, ones := "EUR", "ONES"
+	statement.Currency = &currency
+	statement.RoundedToNearest = &ones
+	return true
+}
+
+// int64PtrType - тип строк отчетов (*int64); идентификаторы (StatementID, FileID) другого типа и не масштабируются
+var int64PtrType = reflect.TypeOf((*int64)(nil))
+
+// scaleAmounts умножает все суммы (*int64 поля) структуры отчета на multiplier с округлением до целого
+func scaleAmounts(details interface{}, multiplier float64) {
+	value := reflect.ValueOf(details).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Type() != int64PtrType || field.IsNil() {
+			continue
+		}
+		scaled := int64(math.Round(float64(field.Elem().Int()) * multiplier))
+		field.Set(reflect.ValueOf(&scaled))
+	}
+}
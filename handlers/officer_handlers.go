@@ -0,0 +1,91 @@
+// handlers/officer_handlers.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOfficersByRegcode godoc
+// @Summary Получить должностных лиц компании по Regcode
+// @Description Возвращает пагинированный список должностных лиц (officers) указанной компании: правление, совет, ликвидаторы и их право представительства. С as_of - должностных лиц на эту дату по версиям записей (entity_versions), которые ведет импортер.
+// @Tags officer
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param as_of query string false "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.Officer} "Пагинированный список должностных лиц"
+// @Failure 400 {object} HTTPError "Неверный Regcode или as_of"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /officers/by-regcode/{regcode} [get]
+func GetOfficersByRegcode(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	pagination := utils.GetPaginationParams(c)
+
+	// На дату: должностные лица из версий записей
+	if asOf != nil {
+		officers := []models.Officer{}
+		if err := loadVersionsAsOf("officers", regcode, *asOf, &officers); err != nil {
+			log.Printf("Error loading officers of regcode %s as of %s: %v", regcode, asOf.Format("2006-01-02"), err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		start, end := pageOf(len(officers), pagination)
+		c.JSON(http.StatusOK, models.PaginatedResponse{
+			TotalRecords: int64(len(officers)),
+			Page:         pagination.Page,
+			Limit:        pagination.Limit,
+			Data:         officers[start:end],
+		})
+		return
+	}
+
+	var officers []models.Officer
+	var totalRecords int64
+
+	// Базовый запрос: компания, в которой лицо занимает должность
+	queryBuilder := db.DB.Model(&models.Officer{}).Where("at_legal_entity_registration_number = ?", regcode)
+
+	// Считаем общее количество
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting officers for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	// Получаем данные для страницы
+	err = queryBuilder.Limit(pagination.Limit).Offset(pagination.Offset).Find(&officers).Error
+	if err != nil {
+		log.Printf("Error finding officers for regcode %s with pagination: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	response := models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         officers,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,398 @@
+// handlers/ownership_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultOwnershipDepth = 3
+	maxOwnershipDepth     = 10
+	maxOwnershipNodes     = 2000 // Защита от разрастания дерева при большом числе общих владельцев
+)
+
+// GetOwnershipTree godoc
+// @Summary Дерево владельцев компании
+// @Description Рекурсивно обходит участников (members) компании вверх: участники-юр. лица раскрываются до их собственных участников на глубину depth. Узел, который уже встречается выше по пути, помечается cycle = true и не раскрывается, а цикл добавляется в cycles.
+// @Tags ownership
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param depth query int false "Глубина обхода" default(3) minimum(1) maximum(10)
+// @Success 200 {object} models.OwnershipTree "Дерево владельцев"
+// @Failure 400 {object} HTTPError "Неверный Regcode или depth"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/ownership-tree [get]
+func GetOwnershipTree(c *gin.Context) {
+	respondOwnershipTree(c, models.OwnershipDirectionOwners)
+}
+
+// GetSubsidiaries godoc
+// @Summary Дерево дочерних компаний
+// @Description Рекурсивно обходит компании, в которых компания является участником (members.legal_entity_registration_number), и их дочерние компании на глубину depth. Циклы обрабатываются так же, как в ownership-tree.
+// @Tags ownership
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param depth query int false "Глубина обхода" default(3) minimum(1) maximum(10)
+// @Success 200 {object} models.OwnershipTree "Дерево дочерних компаний"
+// @Failure 400 {object} HTTPError "Неверный Regcode или depth"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/subsidiaries [get]
+func GetSubsidiaries(c *gin.Context) {
+	respondOwnershipTree(c, models.OwnershipDirectionSubsidiaries)
+}
+
+func respondOwnershipTree(c *gin.Context, direction string) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+	depth, err := parseOwnershipDepth(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	graph, err := loadOwnershipGraph(regcode, direction, depth)
+	if err != nil {
+		log.Printf("respondOwnershipTree: Error loading %s of regcode %s: %v", direction, regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки связей: %w", err)))
+		return
+	}
+	if _, ok := graph.names[regcode]; !ok && len(graph.edges[regcode]) == 0 {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+		return
+	}
+
+	c.JSON(http.StatusOK, graph.tree(regcode, depth))
+}
+
+// parseOwnershipDepth читает ?depth= (по умолчанию defaultOwnershipDepth)
+func parseOwnershipDepth(c *gin.Context) (int, error) {
+	value := c.Query("depth")
+	if value == "" {
+		return defaultOwnershipDepth, nil
+	}
+	depth, err := strconv.Atoi(value)
+	if err != nil || depth < 1 || depth > maxOwnershipDepth {
+		return 0, fmt.Errorf("depth должен быть числом от 1 до %d", maxOwnershipDepth)
+	}
+	return depth, nil
+}
+
+// ownershipGraph - связи members, загруженные обходом в ширину от корня
+type ownershipGraph struct {
+	direction string
+	edges     map[string][]models.Member // regcode -> записи members, ведущие к соседям в направлении обхода
+	names     map[string]*string         // regcode -> название из registers
+	percents  map[uint]*float64          // members.id -> доля участника в капитале компании, %
+}
+
+// loadOwnershipGraph загружает связи по уровням (один запрос на уровень) на depth уровней
+// и еще один уровень, чтобы знать, есть ли связи за пределами глубины.
+func loadOwnershipGraph(root, direction string, depth int) (*ownershipGraph, error) {
+	graph := &ownershipGraph{
+		direction: direction,
+		edges:     make(map[string][]models.Member),
+		names:     make(map[string]*string),
+	}
+	visited := map[string]bool{root: true}
+	allRegcodes := []string{root}
+	frontier := []string{root}
+
+	for level := 0; level <= depth && len(frontier) > 0; level++ {
+		var members []models.Member
+		query := db.DB.Model(&models.Member{})
+		if direction == models.OwnershipDirectionOwners {
+			query = query.Where("at_legal_entity_registration_number IN ?", frontier)
+		} else {
+			query = query.Where("legal_entity_registration_number IN ?", frontier)
+		}
+		if err := query.Order("id asc").Find(&members).Error; err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, member := range members {
+			from, to := graph.endpoints(member)
+			graph.edges[from] = append(graph.edges[from], member)
+			if to != "" && !visited[to] {
+				visited[to] = true
+				allRegcodes = append(allRegcodes, to)
+				next = append(next, to)
+			}
+		}
+		frontier = next
+	}
+
+	var registers []models.Registers
+	if err := db.DB.Select("regcode", "name").Where("regcode IN ?", allRegcodes).Find(&registers).Error; err != nil {
+		return nil, err
+	}
+	for _, register := range registers {
+		if register.Regcode != nil {
+			graph.names[*register.Regcode] = register.Name
+		}
+	}
+
+	// Для владельцев в edges уже все участники каждой компании, для дочерних - только связи с ними
+	if direction == models.OwnershipDirectionOwners {
+		var members []models.Member
+		for _, companyMembers := range graph.edges {
+			members = append(members, companyMembers...)
+		}
+		graph.percents = models.MemberOwnershipPercents(members)
+	} else {
+		percents, err := loadOwnershipPercents(allRegcodes[1:]) // Все, кроме корня - дочерние компании
+		if err != nil {
+			return nil, err
+		}
+		graph.percents = percents
+	}
+	return graph, nil
+}
+
+// endpoints возвращает regcode узла, от которого идет связь, и regcode соседа ("" - физ. лицо)
+func (g *ownershipGraph) endpoints(member models.Member) (from, to string) {
+	owner := strings.TrimSpace(utils.StringValue(member.LegalEntityRegistrationNumber))
+	company := strings.TrimSpace(utils.StringValue(member.AtLegalEntityRegistrationNumber))
+	if g.direction == models.OwnershipDirectionOwners {
+		return company, owner
+	}
+	return owner, company
+}
+
+// tree строит вложенное дерево от root с обнаружением циклов по текущему пути
+func (g *ownershipGraph) tree(root string, depth int) models.OwnershipTree {
+	result := models.OwnershipTree{
+		Regcode:   root,
+		Name:      g.names[root],
+		Direction: g.direction,
+		Depth:     depth,
+		Cycles:    [][]string{},
+	}
+	seenCycles := make(map[string]bool)
+	path := []string{root}
+	onPath := map[string]bool{root: true}
+
+	var build func(regcode string, level int, effective *float64) []models.OwnershipNode
+	build = func(regcode string, level int, effective *float64) []models.OwnershipNode {
+		var children []models.OwnershipNode
+		for _, member := range g.edges[regcode] {
+			if result.Nodes >= maxOwnershipNodes {
+				result.Truncated = true
+				break
+			}
+			result.Nodes++
+
+			_, to := g.endpoints(member)
+			node := g.node(member, to)
+			node.EffectivePercent = effectivePercent(effective, node.OwnershipPercent)
+			switch {
+			case to == "":
+			case onPath[to]:
+				node.Cycle = true
+				cycle := append(cyclePath(path, to), to)
+				if key := strings.Join(cycle, ">"); !seenCycles[key] {
+					seenCycles[key] = true
+					result.Cycles = append(result.Cycles, cycle)
+				}
+			case level == depth:
+				node.DepthLimitReached = len(g.edges[to]) > 0
+			default:
+				path = append(path, to)
+				onPath[to] = true
+				node.Children = build(to, level+1, node.EffectivePercent)
+				path = path[:len(path)-1]
+				delete(onPath, to)
+			}
+			children = append(children, node)
+		}
+		return children
+	}
+
+	full := 100.0
+	result.Children = build(root, 1, &full)
+	if result.Children == nil {
+		result.Children = []models.OwnershipNode{}
+	}
+	return result
+}
+
+// node - узел дерева для записи members; to - regcode соседа или ""
+func (g *ownershipGraph) node(member models.Member, to string) models.OwnershipNode {
+	node := models.OwnershipNode{
+		MemberID:          member.ID,
+		NumberOfShares:    member.NumberOfShares,
+		ShareNominalValue: member.ShareNominalValue,
+		ShareCurrency:     member.ShareCurrency,
+		DateFrom:          member.DateFrom,
+		OwnershipPercent:  g.percents[member.ID],
+	}
+	if g.direction == models.OwnershipDirectionOwners {
+		node.Name, node.EntityType = member.Name, member.EntityType
+	} else {
+		node.Name = g.names[to] // Дочерняя компания - название из registers
+	}
+	if to != "" {
+		node.Regcode = &to
+		if node.Name == nil {
+			node.Name = g.names[to]
+		}
+	}
+	return node
+}
+
+// cyclePath возвращает часть пути, начиная с regcode, на котором замкнулся цикл
+func cyclePath(path []string, regcode string) []string {
+	for i, item := range path {
+		if item == regcode {
+			return append([]string(nil), path[i:]...)
+		}
+	}
+	return []string{regcode}
+}
+
+// effectivePercent - доля percent от доли parent (оба в %); nil, если одна из них неизвестна
+func effectivePercent(parent, percent *float64) *float64 {
+	if parent == nil || percent == nil {
+		return nil
+	}
+	value := *parent * *percent / 100
+	return &value
+}
+
+// GetEffectiveOwners godoc
+// @Summary Конечные владельцы компании с косвенными долями
+// @Description Обходит цепочки участников-юр. лиц вверх на глубину depth и для каждого конечного владельца (физ. лица, юр. лица без известных участников или на границе глубины) суммирует по всем цепочкам произведение долей: лицо с 50% компании, владеющей 60% данной, получает 30%. Цепочки, замыкающиеся в цикл, не учитываются.
+// @Tags ownership
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param depth query int false "Глубина обхода" default(3) minimum(1) maximum(10)
+// @Success 200 {object} models.EffectiveOwnership "Конечные владельцы по убыванию доли"
+// @Failure 400 {object} HTTPError "Неверный Regcode или depth"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/effective-owners [get]
+func GetEffectiveOwners(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+	depth, err := parseOwnershipDepth(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	graph, err := loadOwnershipGraph(regcode, models.OwnershipDirectionOwners, depth)
+	if err != nil {
+		log.Printf("GetEffectiveOwners: Error loading owners of regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки связей: %w", err)))
+		return
+	}
+	if _, ok := graph.names[regcode]; !ok && len(graph.edges[regcode]) == 0 {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+		return
+	}
+
+	c.JSON(http.StatusOK, graph.effectiveOwners(regcode, depth))
+}
+
+// effectiveOwners суммирует косвенные доли конечных владельцев по всем цепочкам от root
+func (g *ownershipGraph) effectiveOwners(root string, depth int) models.EffectiveOwnership {
+	result := models.EffectiveOwnership{Regcode: root, Name: g.names[root], Depth: depth}
+	owners := make(map[string]*models.EffectiveOwner)
+	var order []string
+	onPath := map[string]bool{root: true}
+	visits := 0
+
+	var walk func(regcode string, level int, effective *float64, incomplete bool)
+	walk = func(regcode string, level int, effective *float64, incomplete bool) {
+		for _, member := range g.edges[regcode] {
+			if visits >= maxOwnershipNodes {
+				result.Truncated = true
+				return
+			}
+			visits++
+
+			_, to := g.endpoints(member)
+			percent := g.percents[member.ID]
+			memberEffective := effectivePercent(effective, percent)
+			memberIncomplete := incomplete || percent == nil
+
+			if to != "" {
+				if onPath[to] {
+					continue // Цикл: компания косвенно владеет сама собой
+				}
+				if len(g.edges[to]) > 0 {
+					if level < depth {
+						onPath[to] = true
+						walk(to, level+1, memberEffective, memberIncomplete)
+						delete(onPath, to)
+						continue
+					}
+					memberIncomplete = true // Есть участники глубже depth
+				}
+			}
+
+			key := "regcode:" + to
+			if to == "" {
+				key = "person:" + utils.PersonKey(utils.StringValue(member.Name), member.LatvianIdentityNumberMasked)
+			}
+			owner, ok := owners[key]
+			if !ok {
+				node := g.node(member, to)
+				owner = &models.EffectiveOwner{
+					Regcode:                     node.Regcode,
+					Name:                        node.Name,
+					EntityType:                  member.EntityType,
+					LatvianIdentityNumberMasked: member.LatvianIdentityNumberMasked,
+					PersonID:                    member.PersonID,
+				}
+				owners[key] = owner
+				order = append(order, key)
+			}
+			owner.Paths++
+			if memberEffective != nil {
+				sum := *memberEffective
+				if owner.EffectivePercent != nil {
+					sum += *owner.EffectivePercent
+				}
+				owner.EffectivePercent = &sum
+			}
+			owner.Incomplete = owner.Incomplete || memberIncomplete
+		}
+	}
+	full := 100.0
+	walk(root, 1, &full, false)
+
+	result.Owners = make([]models.EffectiveOwner, 0, len(order))
+	for _, key := range order {
+		result.Owners = append(result.Owners, *owners[key])
+	}
+	// По убыванию доли, неизвестные доли - в конце (порядок обхода сохраняется при равенстве)
+	sort.SliceStable(result.Owners, func(i, j int) bool {
+		left, right := result.Owners[i].EffectivePercent, result.Owners[j].EffectivePercent
+		if left == nil || right == nil {
+			return left != nil && right == nil
+		}
+		return *left > *right
+	})
+	return result
+}
@@ -0,0 +1,44 @@
+// handlers/ownership_percent.go
+package handlers
+
+import (
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+)
+
+// loadOwnershipPercents загружает всех участников компаний companies и считает их доли
+func loadOwnershipPercents(companies []string) (map[uint]*float64, error) {
+	if len(companies) == 0 {
+		return map[uint]*float64{}, nil
+	}
+	var members []models.Member
+	if err := db.DB.Where("at_legal_entity_registration_number IN ?", companies).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return models.MemberOwnershipPercents(members), nil
+}
+
+// applyOwnershipPercents заполняет OwnershipPercent у members (например, у одной страницы списка),
+// считая доли по всем участникам соответствующих компаний
+func applyOwnershipPercents(members []models.Member) error {
+	var companies []string
+	seen := make(map[string]bool)
+	for _, member := range members {
+		company := strings.TrimSpace(utils.StringValue(member.AtLegalEntityRegistrationNumber))
+		if company != "" && !seen[company] {
+			seen[company] = true
+			companies = append(companies, company)
+		}
+	}
+	percents, err := loadOwnershipPercents(companies)
+	if err != nil {
+		return err
+	}
+	for i := range members {
+		members[i].OwnershipPercent = percents[members[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// handlers/person_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetPersonByID godoc
+// @Summary Физическое лицо и все его роли в компаниях
+// @Description Возвращает лицо, выделенное импортером при сопоставлении записей members, beneficial_owners и officers (по началу персонального кода, имени и дате рождения), и все его роли. confidence лица - минимальная уверенность среди его записей; match_confidence роли - уверенность для конкретной записи (0.99 - код, имя и дата рождения совпадают; ниже 0.6 - сопоставление только по имени).
+// @Tags person
+// @Produce json
+// @Param id path int true "ID лица (person_id)"
+// @Success 200 {object} models.PersonProfile "Лицо и его роли"
+// @Failure 400 {object} HTTPError "Неверный ID"
+// @Failure 404 {object} HTTPError "Лицо не найдено"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /persons/{id} [get]
+func GetPersonByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("id должен быть положительным числом")))
+		return
+	}
+
+	var person models.Person
+	if err := db.DB.First(&person, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, NewHTTPError(errors.New("лицо с таким id не найдено")))
+			return
+		}
+		log.Printf("GetPersonByID: Error fetching person %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	var members []models.Member
+	var owners []models.BeneficialOwner
+	var officers []models.Officer
+	for _, query := range []struct {
+		name string
+		dest interface{}
+	}{{"members", &members}, {"beneficial owners", &owners}, {"officers", &officers}} {
+		if err := db.DB.Where("person_id = ?", id).Order("id asc").Find(query.dest).Error; err != nil {
+			log.Printf("GetPersonByID: Error fetching %s of person %d: %v", query.name, id, err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки ролей лица: %w", err)))
+			return
+		}
+	}
+
+	roles := make([]models.PersonCompanyRole, 0, len(members)+len(owners)+len(officers))
+	for _, m := range members {
+		roles = append(roles, memberRole(m))
+	}
+	for _, o := range owners {
+		roles = append(roles, beneficialOwnerRole(o))
+	}
+	for _, o := range officers {
+		roles = append(roles, officerRole(o))
+	}
+
+	var regcodes []string
+	for _, role := range roles {
+		regcodes = appendUniqueRegcodes(regcodes, role.Regcode)
+	}
+	companyNames, err := loadCompanyNames(regcodes)
+	if err != nil {
+		log.Printf("GetPersonByID: Error fetching company names for person %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки названий компаний: %w", err)))
+		return
+	}
+	for i := range roles {
+		roles[i].CompanyName = companyNames[roles[i].Regcode]
+	}
+
+	c.JSON(http.StatusOK, models.PersonProfile{Person: person, Companies: roles})
+}
@@ -0,0 +1,137 @@
+// handlers/postgres_sql_test.go
+package handlers
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"capital-view-api/db"
+	"capital-view-api/db/postgrestest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// usePostgresSQL подменяет db.DB соединением postgrestest: запросы обработчиков не выполняются,
+// а записываются для проверки парсером PostgreSQL. result - строки ответа на запрос (может быть nil).
+func usePostgresSQL(t *testing.T, result func(query string) ([]string, [][]driver.Value)) *postgrestest.Recorder {
+	t.Helper()
+	database, recorder := postgrestest.Open(t)
+	recorder.Result = result
+	previous := db.DB
+	db.DB = database
+	t.Cleanup(func() { db.DB = previous })
+	return recorder
+}
+
+// serve вызывает обработчик с запросом target и параметрами пути params, возвращает код ответа
+func serve(handler gin.HandlerFunc, target string, params ...gin.Param) int {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	c.Params = params
+	handler(c)
+	return recorder.Code
+}
+
+// countResult отвечает на COUNT одной строкой со значением 1, чтобы обработчик дошел до следующих запросов
+func countResult(query string) ([]string, [][]driver.Value, bool) {
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "SELECT COUNT(") {
+		return []string{"count"}, [][]driver.Value{{int64(1)}}, true
+	}
+	return nil, nil, false
+}
+
+// assertRecorded проверяет, что среди запросов есть каждый фрагмент из fragments
+func assertRecorded(t *testing.T, statements []string, fragments ...string) {
+	t.Helper()
+	for _, fragment := range fragments {
+		found := false
+		for _, statement := range statements {
+			if strings.Contains(statement, fragment) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no recorded SQL contains %q:\n%s", fragment, strings.Join(statements, "\n"))
+		}
+	}
+}
+
+func TestScreenerPostgresSQL(t *testing.T) {
+	recorder := usePostgresSQL(t, func(query string) ([]string, [][]driver.Value) {
+		columns, rows, _ := countResult(query)
+		return columns, rows
+	})
+	target := "/screener?year=2023&status=active&type=SIA&region=0100000" +
+		"&condition=net_turnover:gt:1000000&condition=employees:between:10,50&condition=debt_to_equity:lt:2" +
+		"&fields=equity,revenue_per_employee,operating_cash_flow_coverage&sort=-net_turnover"
+	if code := serve(Screener, target); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	assertRecorded(t, recorder.Statements, "WHEN 'LVL' THEN", "AS screener_value_0", "BETWEEN 10 AND 50")
+	postgrestest.Check(t, recorder.Statements)
+}
+
+func TestAddressHotspotsPostgresSQL(t *testing.T) {
+	recorder := usePostgresSQL(t, func(query string) ([]string, [][]driver.Value) {
+		if columns, rows, ok := countResult(query); ok {
+			return columns, rows
+		}
+		switch {
+		case strings.Contains(query, "AS active_companies"):
+			return []string{"addressid", "active_companies"}, [][]driver.Value{{"100000001", int64(2)}}
+		case strings.Contains(query, "registers.addressid IN"):
+			return []string{"addressid", "address"}, [][]driver.Value{{"100000001", "Rīga, Brīvības iela 1"}}
+		}
+		return nil, nil
+	})
+	if code := serve(GetAddressHotspots, "/addresses/hotspots?min_companies=3&type=SIA&registered_from=2020-01-01"); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	assertRecorded(t, recorder.Statements, "AS hotspots", "HAVING COUNT(*) >= 3", "registers.addressid IN ('100000001')")
+	postgrestest.Check(t, recorder.Statements)
+}
+
+func TestAddressCompaniesPostgresSQL(t *testing.T) {
+	recorder := usePostgresSQL(t, func(query string) ([]string, [][]driver.Value) {
+		if columns, rows, ok := countResult(query); ok {
+			return columns, rows
+		}
+		if strings.Contains(query, "registers.addressid IN") {
+			return []string{"addressid", "address"}, [][]driver.Value{{"100000001", "Rīga, Brīvības iela 1"}}
+		}
+		return nil, nil
+	})
+	code := serve(GetAddressCompanies, "/addresses/100000001/companies?status=active", gin.Param{Key: "addressid", Value: "100000001"})
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	assertRecorded(t, recorder.Statements, "registers.addressid IN ('100000001')", "(registers.registered IS NULL), registers.registered DESC")
+	postgrestest.Check(t, recorder.Statements)
+}
+
+func TestSearchPersonsPostgresSQL(t *testing.T) {
+	for _, q := range []string{"Bērziņš", "010170-*****"} {
+		t.Run(q, func(t *testing.T) {
+			recorder := usePostgresSQL(t, func(query string) ([]string, [][]driver.Value) {
+				if strings.Contains(query, "COUNT(DISTINCT person_key)") {
+					return []string{"count"}, [][]driver.Value{{int64(2)}}
+				}
+				if strings.Contains(query, "GROUP BY") && strings.Contains(query, "person_key") {
+					return []string{"person_key"}, [][]driver.Value{{"p:1"}, {"m:5"}}
+				}
+				return nil, nil
+			})
+			if code := serve(SearchPersons, "/search/persons?q="+q); code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", code)
+			}
+			assertRecorded(t, recorder.Statements, "UNION ALL", "GROUP BY", "person_id IN (1)")
+			postgrestest.Check(t, recorder.Statements)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// handlers/ratio_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCompanyRatios godoc
+// @Summary Финансовые коэффициенты компании по годам
+// @Description Считает по каждому фин. отчету компании: current ratio, debt-to-equity, ROA, ROE, чистую и валовую маржу и покрытие краткосрочных обязательств операционным денежным потоком. Суммы приводятся к единицам валюты по rounded_to_nearest; числитель и знаменатель всегда из одного отчета, поэтому валюта на коэффициенты не влияет. Если данных нет или знаменатель 0 (или отрицательный там, где это лишает коэффициент смысла), value = null, а status объясняет причину.
+// @Tags company
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Success 200 {array} models.CompanyRatiosYear "Коэффициенты по годам (по возрастанию)"
+// @Failure 400 {object} HTTPError "Неверный Regcode"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/ratios [get]
+func GetCompanyRatios(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	statements, err := loadStatementsWithDetails(regcode)
+	if err != nil {
+		log.Printf("GetCompanyRatios: Error loading statements for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки фин. отчетов: %w", err)))
+		return
+	}
+	if len(statements) == 0 {
+		exists, err := companyExists(regcode)
+		if err != nil {
+			log.Printf("GetCompanyRatios: Error checking company %s: %v", regcode, err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+			return
+		}
+	}
+
+	result := make([]models.CompanyRatiosYear, 0, len(statements))
+	for _, statement := range statements {
+		result = append(result, computeRatios(statement))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// computeRatios считает коэффициенты по одному отчету
+func computeRatios(statement models.FinancialStatement) models.CompanyRatiosYear {
+	multiplier, unitsKnown := utils.UnitMultiplier(statement.RoundedToNearest)
+	amount := func(value *int64) *float64 {
+		if value == nil {
+			return nil
+		}
+		scaled := float64(*value) * multiplier
+		return &scaled
+	}
+
+	var (
+		totalCurrentAssets, currentLiabilities, nonCurrentLiabilities *float64
+		totalAssets, equity, netIncome, netTurnover, grossProfit      *float64
+		operatingCashFlow                                             *float64
+	)
+	if bs := statement.BalanceSheet; bs != nil {
+		totalCurrentAssets = amount(bs.TotalCurrentAssets)
+		currentLiabilities = amount(bs.CurrentLiabilities)
+		nonCurrentLiabilities = amount(bs.NonCurrentLiabilities)
+		totalAssets = amount(bs.TotalAssets)
+		equity = amount(bs.Equity)
+	}
+	if is := statement.IncomeStatement; is != nil {
+		netIncome = amount(is.NetIncome)
+		netTurnover = amount(is.NetTurnover)
+		grossProfit = amount(is.ByFunctionGrossProfit)
+	}
+	if cfs := statement.CashFlowStatement; cfs != nil {
+		// Отчет о движении денег составляется прямым (dm) или косвенным (im) методом
+		operatingCashFlow = amount(cfs.CfoDmNetOperatingCashFlow)
+		if operatingCashFlow == nil {
+			operatingCashFlow = amount(cfs.CfoImNetOperatingCashFlow)
+		}
+	}
+
+	// Общий долг = краткосрочные + долгосрочные обязательства (нет долгосрочных - считаем 0)
+	var totalLiabilities *float64
+	if currentLiabilities != nil {
+		sum := *currentLiabilities
+		if nonCurrentLiabilities != nil {
+			sum += *nonCurrentLiabilities
+		}
+		totalLiabilities = &sum
+	}
+
+	return models.CompanyRatiosYear{
+		Year:             statement.Year,
+		StatementID:      statement.ID,
+		Currency:         statement.Currency,
+		RoundedToNearest: statement.RoundedToNearest,
+		UnitsKnown:       unitsKnown,
+		Ratios: models.FinancialRatios{
+			CurrentRatio:              ratio(totalCurrentAssets, currentLiabilities, false),
+			DebtToEquity:              ratio(totalLiabilities, equity, true),
+			ReturnOnAssets:            ratio(netIncome, totalAssets, true),
+			ReturnOnEquity:            ratio(netIncome, equity, true),
+			NetMargin:                 ratio(netIncome, netTurnover, true),
+			GrossMargin:               ratio(grossProfit, netTurnover, true),
+			OperatingCashFlowCoverage: ratio(operatingCashFlow, currentLiabilities, false),
+		},
+	}
+}
+
+// ratio делит numerator на denominator и объясняет, почему результата нет.
+// positiveDenominator - знаменатель должен быть > 0 (капитал, активы, выручка).
+func ratio(numerator, denominator *float64, positiveDenominator bool) models.RatioValue {
+	switch {
+	case numerator == nil || denominator == nil:
+		return models.RatioValue{Status: models.RatioStatusMissingData}
+	case *denominator == 0:
+		return models.RatioValue{Status: models.RatioStatusZeroDenominator}
+	case positiveDenominator && *denominator < 0:
+		return models.RatioValue{Status: models.RatioStatusNegativeDenominator}
+	}
+	value := *numerator / *denominator
+	return models.RatioValue{Value: &value, Status: models.RatioStatusOK}
+}
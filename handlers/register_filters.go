@@ -0,0 +1,129 @@
+// handlers/register_filters.go
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// activeRegisterCondition - действующая компания: не ликвидирована и не закрыта (status=active)
+const activeRegisterCondition = models.ActiveRegisterCondition
+
+// applyRegisterFilters добавляет к запросу по таблице registers фильтры из query-параметров.
+// Используется в GetAllRegisters и DetailedSearch, поэтому колонки указаны с префиксом "registers.".
+//
+//	regtype, type           - точное совпадение, несколько значений через запятую (type=SIA,AS)
+//	status                  - active | terminated | closed (по колонкам terminated и closed)
+//	region, city, atvk      - коды адреса, несколько значений через запятую
+//	registered_from/_to     - диапазон даты регистрации (включительно), dd/mm/yyyy или yyyy-mm-dd
+//	has_financials, has_beneficial_owners, has_officers - true/false, наличие связанных записей
+//	risk_flag, risk_level   - сработавшее правило риска / уровень риска, несколько через запятую (company_risks)
+//	min_risk_score          - минимальный score риска (0-100)
+func applyRegisterFilters(c *gin.Context, queryBuilder *gorm.DB) (*gorm.DB, error) {
+	valueFilters := []struct {
+		param  string
+		column string
+	}{
+		{"regtype", "registers.regtype"},
+		{"type", "registers.type"},
+		{"region", "registers.region"},
+		{"city", "registers.city"},
+		{"atvk", "registers.atvk"},
+	}
+	for _, filter := range valueFilters {
+		if values := splitFilterValues(c.Query(filter.param)); len(values) > 0 {
+			queryBuilder = queryBuilder.Where(filter.column+" IN ?", values)
+		}
+	}
+
+	switch status := strings.ToLower(strings.TrimSpace(c.Query("status"))); status {
+	case "":
+	case "active":
+		queryBuilder = queryBuilder.Where(activeRegisterCondition)
+	case "terminated":
+		queryBuilder = queryBuilder.Where("registers.terminated IS NOT NULL")
+	case "closed":
+		queryBuilder = queryBuilder.Where("registers.closed IS NOT NULL AND registers.closed <> ''")
+	default:
+		return nil, fmt.Errorf("неверное значение status '%s' (допустимо: active, terminated, closed)", status)
+	}
+
+	if value := c.Query("registered_from"); value != "" {
+		from, err := utils.ParseDate(value)
+		if err != nil {
+			return nil, fmt.Errorf("registered_from: %w", err)
+		}
+		queryBuilder = queryBuilder.Where("registers.registered >= ?", from)
+	}
+	if value := c.Query("registered_to"); value != "" {
+		to, err := utils.ParseDate(value)
+		if err != nil {
+			return nil, fmt.Errorf("registered_to: %w", err)
+		}
+		queryBuilder = queryBuilder.Where("registers.registered <= ?", to)
+	}
+
+	existenceFilters := []struct {
+		param    string
+		subquery string
+	}{
+		{"has_financials", "SELECT 1 FROM financial_statements fs WHERE fs.legal_entity_registration_number = registers.regcode"},
+		{"has_beneficial_owners", "SELECT 1 FROM beneficial_owners bo WHERE bo.legal_entity_registration_number = registers.regcode"},
+		{"has_officers", "SELECT 1 FROM officers o WHERE o.at_legal_entity_registration_number = registers.regcode"},
+	}
+	for _, filter := range existenceFilters {
+		value := c.Query(filter.param)
+		if value == "" {
+			continue
+		}
+		exists, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: ожидается true или false, получено '%s'", filter.param, value)
+		}
+		if exists {
+			queryBuilder = queryBuilder.Where("EXISTS (" + filter.subquery + ")")
+		} else {
+			queryBuilder = queryBuilder.Where("NOT EXISTS (" + filter.subquery + ")")
+		}
+	}
+
+	// Риск (company_risks заполняет risk.Refresh). Компании без флагов в company_risks отсутствуют
+	if values := splitFilterValues(c.Query("risk_flag")); len(values) > 0 {
+		queryBuilder = queryBuilder.Where("EXISTS (SELECT 1 FROM company_risk_flags rf WHERE rf.regcode = registers.regcode AND rf.rule IN ?)", values)
+	}
+	if values := splitFilterValues(strings.ToLower(c.Query("risk_level"))); len(values) > 0 {
+		for _, level := range values {
+			if level != models.RiskLevelLow && level != models.RiskLevelMedium && level != models.RiskLevelHigh {
+				return nil, fmt.Errorf("неверное значение risk_level '%s' (допустимо: low, medium, high)", level)
+			}
+		}
+		queryBuilder = queryBuilder.Where("EXISTS (SELECT 1 FROM company_risks cr WHERE cr.regcode = registers.regcode AND cr.level IN ?)", values)
+	}
+	if value := c.Query("min_risk_score"); value != "" {
+		score, err := strconv.Atoi(value)
+		if err != nil || score < 0 {
+			return nil, fmt.Errorf("min_risk_score: ожидается неотрицательное целое число, получено '%s'", value)
+		}
+		queryBuilder = queryBuilder.Where("EXISTS (SELECT 1 FROM company_risks cr WHERE cr.regcode = registers.regcode AND cr.score >= ?)", score)
+	}
+
+	return queryBuilder, nil
+}
+
+// splitFilterValues разбирает значение фильтра вида "SIA, AS" в список непустых значений
+func splitFilterValues(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
@@ -61,12 +61,27 @@ func GetRegisterByID(c *gin.Context) {
 
 // GetAllRegisters godoc
 // @Summary Получить список всех записей регистра
-// @Description Возвращает пагинированный список записей из таблицы registers.
+// @Description Возвращает пагинированный список записей из таблицы registers с необязательными фильтрами по типу, статусу, адресу, дате регистрации и наличию связанных данных.
 // @Tags register
 // @Produce json
 // @Param page query int false "Номер страницы" default(1) minimum(1)
 // @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Param regtype query string false "Фильтр по regtype (несколько значений через запятую)"
+// @Param type query string false "Фильтр по типу компании, например SIA,AS"
+// @Param status query string false "Статус компании" Enums(active, terminated, closed)
+// @Param region query string false "Код региона (несколько через запятую)"
+// @Param city query string false "Код города (несколько через запятую)"
+// @Param atvk query string false "Код ATVK (несколько через запятую)"
+// @Param registered_from query string false "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param registered_to query string false "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param has_financials query bool false "Есть / нет фин. отчетов"
+// @Param has_beneficial_owners query bool false "Есть / нет бенефициаров"
+// @Param has_officers query bool false "Есть / нет должностных лиц"
+// @Param risk_flag query string false "Сработавшее правило риска (несколько через запятую), например negative_equity,circular_ownership"
+// @Param risk_level query string false "Уровень риска (несколько через запятую): low, medium, high"
+// @Param min_risk_score query int false "Минимальный score риска (0-100)"
 // @Success 200 {object} models.PaginatedResponse{data=[]models.Registers} "Пагинированный список записей"
+// @Failure 400 {object} HTTPError "Неверное значение фильтра"
 // @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
 // @Router /registers [get] // <-- Пример роута, измените на ваш
 func GetAllRegisters(c *gin.Context) {
@@ -78,8 +93,12 @@ func GetAllRegisters(c *gin.Context) {
 	// Базовый запрос
 	queryBuilder := db.DB.Model(&models.Registers{})
 
-	// Фильтрация (если нужна, добавьте .Where() сюда)
-	// queryBuilder = queryBuilder.Where("some_condition = ?", some_value)
+	// Фильтрация по query-параметрам (см. applyRegisterFilters)
+	queryBuilder, err := applyRegisterFilters(c, queryBuilder)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
 
 	// Считаем общее количество
 	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
@@ -89,7 +108,7 @@ func GetAllRegisters(c *gin.Context) {
 	}
 
 	// Получаем данные для страницы + сортировка (пример)
-	err := queryBuilder.Order("name asc"). // <-- Пример сортировки
+	err = queryBuilder.Order("name asc"). // <-- Пример сортировки
 						Limit(pagination.Limit).
 						Offset(pagination.Offset).
 						Find(&registers).Error
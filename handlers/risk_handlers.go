@@ -0,0 +1,73 @@
+// handlers/risk_handlers.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCompanyRisk godoc
+// @Summary Флаги риска и оценка компании
+// @Description Возвращает сработавшие для компании правила риска с объяснениями и итоговую оценку. score - сумма весов сработавших правил (не больше 100), level - уровень по порогам из файла правил. Правила: negative_equity (отрицательный собственный капитал), zero_assets_with_liabilities (нулевые активы при наличии обязательств), no_recent_statements (нет отчетности за последние годы), frequent_beneficial_owner_changes (частая смена бенефициаров), shared_address (адрес, по которому зарегистрировано много компаний), circular_ownership (циклическое владение). Правила, веса и пороги задаются в файле RISK_RULES_PATH (по умолчанию risk_rules.json), оценка пересчитывается после каждого импорта. Компания без флагов возвращается со score 0 и level low.
+// @Tags risk
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Success 200 {object} models.CompanyRiskReport "Оценка и флаги по убыванию веса"
+// @Failure 400 {object} HTTPError "Неверный Regcode"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/risk [get]
+func GetCompanyRisk(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	var risks []models.CompanyRisk
+	if err := db.DB.Where("regcode = ?", regcode).Limit(1).Find(&risks).Error; err != nil {
+		log.Printf("GetCompanyRisk: Error loading risk of regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	var companies []models.Registers
+	if err := db.DB.Select("regcode", "name").Where("regcode = ?", regcode).Limit(1).Find(&companies).Error; err != nil {
+		log.Printf("GetCompanyRisk: Error loading company %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	if len(companies) == 0 && len(risks) == 0 {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+		return
+	}
+
+	report := models.CompanyRiskReport{Regcode: regcode, Level: models.RiskLevelLow, Flags: []models.CompanyRiskFlag{}}
+	if len(companies) > 0 {
+		report.Name = companies[0].Name
+	}
+	if len(risks) > 0 {
+		report.Score = risks[0].Score
+		report.Level = risks[0].Level
+		report.EvaluatedAt = &risks[0].EvaluatedAt
+		if err := db.DB.Where("regcode = ?", regcode).Order("weight DESC").Order("id").Find(&report.Flags).Error; err != nil {
+			log.Printf("GetCompanyRisk: Error loading risk flags of regcode %s: %v", regcode, err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+	} else {
+		// Флагов нет: время оценки берем из любой строки, если оценка уже выполнялась
+		var latest []models.CompanyRisk
+		if err := db.DB.Select("evaluated_at").Order("evaluated_at DESC").Limit(1).Find(&latest).Error; err == nil && len(latest) > 0 {
+			report.EvaluatedAt = &latest[0].EvaluatedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, report)
+}
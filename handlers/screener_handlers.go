@@ -0,0 +1,278 @@
+// handlers/screener_handlers.go
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// screenerOperators - операторы условий скринера и соответствующие SQL-операторы (between обрабатывается отдельно)
+var screenerOperators = map[string]string{
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+	"eq":  "=",
+	"ne":  "<>",
+}
+
+// screenerExpression возвращает SQL-выражение показателя: строки отчета (суммы в евро), employees или производного показателя
+func screenerExpression(name string) (string, bool) {
+	if expression, ok := db.DerivedMetricSQL[name]; ok {
+		return expression, true
+	}
+	field, ok := financialFields[name]
+	if !ok {
+		return "", false
+	}
+	column := field.Table + "." + field.Name
+	if field.Source == "financial_statement" {
+		return column, true // employees - не сумма
+	}
+	return "(" + column + " * " + db.EURAmountMultiplierSQL + ")", true
+}
+
+// screenerMetricNames - все доступные показатели (для сообщений об ошибках)
+func screenerMetricNames() []string {
+	names := financialFieldNames()
+	for name := range db.DerivedMetricSQL {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// screenerCondition - одно условие вида net_turnover:gt:1000000 или employees:between:10,50
+type screenerCondition struct {
+	Field  string
+	Op     string
+	Values []float64
+}
+
+func parseScreenerCondition(raw string) (screenerCondition, error) {
+	parts := strings.SplitN(strings.TrimSpace(raw), ":", 3)
+	if len(parts) != 3 {
+		return screenerCondition{}, fmt.Errorf("условие '%s' должно иметь вид поле:оператор:значение", raw)
+	}
+	condition := screenerCondition{Field: strings.TrimSpace(parts[0]), Op: strings.ToLower(strings.TrimSpace(parts[1]))}
+	if _, ok := screenerExpression(condition.Field); !ok {
+		return screenerCondition{}, fmt.Errorf("неизвестное поле '%s' в условии '%s'", condition.Field, raw)
+	}
+
+	rawValues := []string{parts[2]}
+	switch _, ok := screenerOperators[condition.Op]; {
+	case condition.Op == "between":
+		rawValues = strings.Split(parts[2], ",")
+		if len(rawValues) != 2 {
+			return screenerCondition{}, fmt.Errorf("between в условии '%s' ожидает два значения через запятую", raw)
+		}
+	case !ok:
+		return screenerCondition{}, fmt.Errorf("неизвестный оператор '%s' в условии '%s' (допустимо: gt, gte, lt, lte, eq, ne, between)", condition.Op, raw)
+	}
+	for _, rawValue := range rawValues {
+		value, err := strconv.ParseFloat(strings.TrimSpace(rawValue), 64)
+		if err != nil {
+			return screenerCondition{}, fmt.Errorf("неверное число '%s' в условии '%s'", rawValue, raw)
+		}
+		condition.Values = append(condition.Values, value)
+	}
+	return condition, nil
+}
+
+// Screener godoc
+// @Summary Скринер компаний по финансовым показателям
+// @Description Отбирает компании по строкам фин. отчета за год и производным показателям (current_ratio, debt_to_equity, return_on_assets, return_on_equity, net_margin, gross_margin, operating_cash_flow_coverage, equity_ratio, revenue_per_employee). Условие задается параметром condition вида поле:оператор:значение, например net_turnover:gt:1000000, equity:lt:0, employees:between:10,50; несколько условий объединяются через AND. Суммы сравниваются и возвращаются в единицах евро (rounded_to_nearest и LVL учитываются); отчеты с нераспознанной валютой или единицами не проходят условия по суммам. Дополнительно принимает фильтры /registers.
+// @Tags screener
+// @Produce json
+// @Param year query int true "Год отчетности"
+// @Param condition query []string false "Условие поле:оператор:значение (gt, gte, lt, lte, eq, ne, between)" collectionFormat(multi)
+// @Param fields query string false "Дополнительные показатели в ответе через запятую"
+// @Param sort query string false "Показатель или name; префикс '-' - по убыванию (например -net_turnover). По умолчанию name"
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Param type query string false "Фильтр по типу компании, например SIA,AS"
+// @Param status query string false "Статус компании" Enums(active, terminated, closed)
+// @Param region query string false "Код региона (несколько через запятую)"
+// @Param city query string false "Код города (несколько через запятую)"
+// @Success 200 {object} models.PaginatedResponse{data=[]models.ScreenerResult} "Компании, прошедшие условия"
+// @Failure 400 {object} HTTPError "Неверный год, условие, поле или фильтр"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /screener [get]
+func Screener(c *gin.Context) {
+	pagination := utils.GetPaginationParams(c)
+
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("параметр year обязателен и должен быть числом")))
+		return
+	}
+
+	var conditions []screenerCondition
+	for _, raw := range c.QueryArray("condition") {
+		condition, err := parseScreenerCondition(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewHTTPError(err))
+			return
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// Показатели в ответе: поля условий, сортировки и fields (без повторов, в порядке упоминания)
+	var valueFields []string
+	addValueField := func(name string) {
+		for _, existing := range valueFields {
+			if existing == name {
+				return
+			}
+		}
+		valueFields = append(valueFields, name)
+	}
+	for _, condition := range conditions {
+		addValueField(condition.Field)
+	}
+	for _, name := range splitFilterValues(c.Query("fields")) {
+		if _, ok := screenerExpression(name); !ok {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неизвестное поле '%s' (доступны: %s)", name, strings.Join(screenerMetricNames(), ", "))))
+			return
+		}
+		addValueField(name)
+	}
+
+	order := "registers.name ASC"
+	if sortParam := strings.TrimSpace(c.Query("sort")); sortParam != "" && sortParam != "name" && sortParam != "-name" {
+		direction := "ASC"
+		if strings.HasPrefix(sortParam, "-") {
+			direction, sortParam = "DESC", sortParam[1:]
+		}
+		expression, ok := screenerExpression(sortParam)
+		if !ok {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неизвестное поле сортировки '%s'", sortParam)))
+			return
+		}
+		addValueField(sortParam)
+		// Компании без значения - в конце в любом направлении
+		order = fmt.Sprintf("(%s IS NULL), %s %s, registers.name ASC", expression, expression, direction)
+	} else if sortParam == "-name" {
+		order = "registers.name DESC"
+	}
+
+	// registers -> financial_statements за год (один на компанию, uq_fs_company_year) -> строки отчетов по statement_id
+	queryBuilder := db.DB.Table("registers").
+		Joins("JOIN financial_statements ON financial_statements.legal_entity_registration_number = registers.regcode AND financial_statements.year = ?", year).
+		Joins("LEFT JOIN income_statements ON income_statements.statement_id = financial_statements.id").
+		Joins("LEFT JOIN balance_sheets ON balance_sheets.statement_id = financial_statements.id").
+		Joins("LEFT JOIN cash_flow_statements ON cash_flow_statements.statement_id = financial_statements.id")
+
+	queryBuilder, err = applyRegisterFilters(c, queryBuilder)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	for _, condition := range conditions {
+		expression, _ := screenerExpression(condition.Field)
+		if condition.Op == "between" {
+			low, high := condition.Values[0], condition.Values[1]
+			if low > high {
+				low, high = high, low
+			}
+			queryBuilder = queryBuilder.Where(expression+" BETWEEN ? AND ?", low, high)
+			continue
+		}
+		queryBuilder = queryBuilder.Where(expression+" "+screenerOperators[condition.Op]+" ?", condition.Values[0])
+	}
+
+	var totalRecords int64
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("Screener: Error counting companies: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	selects := []string{"registers.regcode", "registers.name", "registers.type", "financial_statements.id", "financial_statements.year"}
+	for i, name := range valueFields {
+		expression, _ := screenerExpression(name)
+		selects = append(selects, fmt.Sprintf("%s AS screener_value_%d", expression, i))
+	}
+
+	rows, err := queryBuilder.Select(strings.Join(selects, ", ")).
+		Order(order).
+		Limit(pagination.Limit).
+		Offset(pagination.Offset).
+		Rows()
+	if err != nil {
+		log.Printf("Screener: Error querying companies: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	defer rows.Close()
+
+	results := []models.ScreenerResult{}
+	for rows.Next() {
+		var (
+			regcode, name, companyType sql.NullString
+			statementID                uint
+			statementYear              int
+		)
+		values := make([]sql.NullFloat64, len(valueFields))
+		destinations := []interface{}{&regcode, &name, &companyType, &statementID, &statementYear}
+		for i := range values {
+			destinations = append(destinations, &values[i])
+		}
+		if err := rows.Scan(destinations...); err != nil {
+			log.Printf("Screener: Error scanning row: %v", err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+
+		result := models.ScreenerResult{
+			Regcode:     regcode.String,
+			Name:        nullStringPtr(name),
+			Type:        nullStringPtr(companyType),
+			Year:        statementYear,
+			StatementID: statementID,
+			Values:      make(map[string]*float64, len(valueFields)),
+		}
+		for i, field := range valueFields {
+			if values[i].Valid {
+				value := values[i].Float64
+				result.Values[field] = &value
+			} else {
+				result.Values[field] = nil
+			}
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Screener: Error reading rows: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         results,
+	})
+}
+
+// nullStringPtr превращает sql.NullString в *string (NULL -> nil)
+func nullStringPtr(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
@@ -0,0 +1,94 @@
+// handlers/screening_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/screening"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCompanyScreening godoc
+// @Summary Совпадения компании со списками санкций и PEP
+// @Description Возвращает совпадения названия компании и имен ее участников, бенефициаров и должностных лиц с загруженными списками санкций и PEP. Списки загружает импортер (load-screening-lists), скрининг выполняется после каждого импорта. score - сходство имен от 0 до 1 без учета порядка слов, регистра, диакритики и организационно-правовой формы; matched_field и matched_value - что именно совпало; birth_date_match - совпал ли год рождения. Сохраняются совпадения со score не ниже 0.85.
+// @Tags screening
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param min_score query number false "Минимальный score (0-1)" default(0.85)
+// @Param category query string false "Только списки категории" Enums(sanctions, pep)
+// @Success 200 {object} models.CompanyScreening "Совпадения по убыванию score"
+// @Failure 400 {object} HTTPError "Неверный Regcode, min_score или category"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/screening [get]
+func GetCompanyScreening(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+	minScore := screening.DefaultMinScore
+	if value := c.Query("min_score"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("min_score должен быть числом от 0 до 1")))
+			return
+		}
+		minScore = parsed
+	}
+	category := c.Query("category")
+	if category != "" && category != models.ScreeningCategorySanctions && category != models.ScreeningCategoryPEP {
+		c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неизвестная category %q (sanctions или pep)", category)))
+		return
+	}
+
+	result := models.CompanyScreening{Regcode: regcode, MinScore: minScore, Lists: []models.ScreeningList{}, Matches: []models.ScreeningMatch{}}
+
+	query := db.DB.Preload("Entry").Where("regcode = ? AND score >= ?", regcode, minScore)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if err := query.Order("score DESC").Order("id").Find(&result.Matches).Error; err != nil {
+		log.Printf("GetCompanyScreening: Error loading screening matches of regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	var companies []models.Registers
+	if err := db.DB.Select("regcode", "name").Where("regcode = ?", regcode).Limit(1).Find(&companies).Error; err != nil {
+		log.Printf("GetCompanyScreening: Error loading company %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	if len(companies) == 0 && len(result.Matches) == 0 {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+		return
+	}
+	if len(companies) > 0 {
+		result.Name = companies[0].Name
+	}
+
+	listsQuery := db.DB.Order("name")
+	if category != "" {
+		listsQuery = listsQuery.Where("category = ?", category)
+	}
+	if err := listsQuery.Find(&result.Lists).Error; err != nil {
+		log.Printf("GetCompanyScreening: Error loading screening lists: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	for _, list := range result.Lists {
+		if list.ScreenedAt != nil && (result.ScreenedAt == nil || list.ScreenedAt.After(*result.ScreenedAt)) {
+			result.ScreenedAt = list.ScreenedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	// "sync" // sync.WaitGroup и sync.Mutex больше не нужны для Этапа 1
 
@@ -21,14 +24,28 @@ import (
 
 // DetailedSearch godoc
 // @Summary Упрощенный поиск компаний (с пагинацией)
-// @Description Ищет компании **только** по полям таблицы регистра (Regcode, SEPA, Name). Возвращает пагинированный список с базовой информацией. // <-- Описание изменено
+// @Description Ищет компании по Regcode/SEPA (точное совпадение) и полнотекстово по названию и адресу. Принимает те же фильтры, что и /registers. Результаты упорядочены по релевантности (BM25). Поддерживаются префиксы (`balt*`) и фразы (`"zemes iela"`). Регистр и диакритика не учитываются (lusins находит Lūsiņš). Если FTS5 недоступен, используется поиск LIKE по нормализованному названию.
 // @Tags search
 // @Produce json
-// @Param q query string true "Поисковый запрос (Regcode, SEPA, Name)"
+// @Param q query string true "Поисковый запрос (Regcode, SEPA, название, адрес; префикс: слово*, фраза - в двойных кавычках)"
 // @Param page query int false "Номер страницы" default(1) minimum(1)
 // @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Param regtype query string false "Фильтр по regtype (несколько значений через запятую)"
+// @Param type query string false "Фильтр по типу компании, например SIA,AS"
+// @Param status query string false "Статус компании" Enums(active, terminated, closed)
+// @Param region query string false "Код региона (несколько через запятую)"
+// @Param city query string false "Код города (несколько через запятую)"
+// @Param atvk query string false "Код ATVK (несколько через запятую)"
+// @Param registered_from query string false "Зарегистрирована не раньше (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param registered_to query string false "Зарегистрирована не позже (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param has_financials query bool false "Есть / нет фин. отчетов"
+// @Param has_beneficial_owners query bool false "Есть / нет бенефициаров"
+// @Param has_officers query bool false "Есть / нет должностных лиц"
+// @Param risk_flag query string false "Сработавшее правило риска (несколько через запятую), например negative_equity,circular_ownership"
+// @Param risk_level query string false "Уровень риска (несколько через запятую): low, medium, high"
+// @Param min_risk_score query int false "Минимальный score риска (0-100)"
 // @Success 200 {object} models.PaginatedResponse{data=[]models.SimpleRegisterInfo} "Пагинированный список базовой информации о компаниях"
-// @Failure 400 {object} HTTPError "Неверный запрос (отсутствует 'q')"
+// @Failure 400 {object} HTTPError "Неверный запрос (отсутствует 'q' или неверный фильтр)"
 // @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
 // @Router /search/detailed [get]
 func DetailedSearch(c *gin.Context) {
@@ -40,42 +57,28 @@ func DetailedSearch(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("поисковый параметр 'q' обязателен")))
 		return
 	}
-	searchTermLower := strings.ToLower(searchTerm)
-	searchTermLikeLower := "%" + searchTermLower + "%"
 	pagination := utils.GetPaginationParams(c)
-	log.Printf("DetailedSearch (Simplified): SearchTerm: '%s', Page: %d, Limit: %d", searchTerm, pagination.Page, pagination.Limit)
+	// Фильтры проверяем до поиска, чтобы вернуть 400 на неверный параметр
+	if _, err := applyRegisterFilters(c, db.DB); err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	log.Printf("DetailedSearch (Simplified): SearchTerm: '%s', Page: %d, Limit: %d, FTS: %t", searchTerm, pagination.Page, pagination.Limit, db.FTSEnabled)
 
-	// --- Этап 1 (Упрощен): Поиск ID ТОЛЬКО в таблице 'registers' ---
+	// --- Этап 1: Поиск regcode в таблице 'registers' (в порядке релевантности) ---
 	log.Println("DetailedSearch (Simplified): Phase 1 starting - Finding matching IDs in 'registers' table only...")
-	var registerMatches []struct{ Regcode *string } // Срез для получения только regcode
-
-	// Выполняем ОДИН запрос к таблице registers
-	err := db.DB.Model(&models.Registers{}). // Убедитесь, что используется модель для таблицы 'registers'
-							Select("DISTINCT regcode"). // Выбираем УНИКАЛЬНЫЕ regcode
-							Where("LOWER(regcode) = ?", searchTermLower).
-							Or("LOWER(sepa) = ?", searchTermLower).
-							Or("LOWER(name) LIKE ?", searchTermLikeLower).
-							Or("LOWER(name_in_quotes) LIKE ?", searchTermLikeLower).
-							Or("LOWER(without_quotes) LIKE ?", searchTermLikeLower).
-							Find(&registerMatches).Error // Ищем все совпадающие regcode
-
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	var uniqueRegCodes []string
+	var err error
+	if db.FTSEnabled {
+		uniqueRegCodes, err = findRegcodesFTS(c, searchTerm)
+	} else {
+		uniqueRegCodes, err = findRegcodesLike(c, searchTerm)
+	}
+	if err != nil {
 		log.Printf("DetailedSearch (Simplified): Error during ID search: %v", err)
 		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка при поиске ID: %w", err)))
 		return
 	}
-
-	// Собираем уникальные ID (хотя SELECT DISTINCT уже должен был это сделать)
-	var uniqueRegCodes []string
-	if registerMatches != nil { // Проверка на случай, если Find вернул nil при gorm.ErrRecordNotFound
-		for _, match := range registerMatches {
-			if match.Regcode != nil && *match.Regcode != "" {
-				// Дополнительная проверка на уникальность не нужна из-за SELECT DISTINCT
-				uniqueRegCodes = append(uniqueRegCodes, *match.Regcode)
-			}
-		}
-	}
-	sort.Strings(uniqueRegCodes) // Сортируем для стабильности пагинации
 	totalRecords := int64(len(uniqueRegCodes))
 	log.Printf("DetailedSearch (Simplified): Phase 1 finished - Found %d unique matching IDs.", totalRecords)
 
@@ -110,17 +113,30 @@ func DetailedSearch(c *gin.Context) {
 	log.Printf("DetailedSearch (Simplified): Phase 2 starting - Fetching simplified data for %d IDs...", len(paginatedRegCodes))
 
 	// Выбираем нужные поля в структуру SimpleRegisterInfo
+	var fetched []models.SimpleRegisterInfo
 	err = db.DB.Model(&models.Registers{}).
 		Select("regcode", "name", "regtype_text", "address", "type_text"). // <--- ТОЛЬКО эти поля
 		Where("regcode IN ?", paginatedRegCodes).
-		// Order(...) // Можно добавить сортировку по regcode, чтобы соответствовать paginatedRegCodes
-		Find(&simplePaginatedData).Error // Записываем результат в срез []SimpleRegisterInfo
+		Find(&fetched).Error // Записываем результат в срез []SimpleRegisterInfo
 
 	if err != nil {
 		log.Printf("DetailedSearch (Simplified): Error fetching simplified data: %v", err)
 		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки списка компаний: %w", err)))
 		return
 	}
+
+	// IN (...) не сохраняет порядок - восстанавливаем порядок релевантности из Этапа 1
+	byRegcode := make(map[string]models.SimpleRegisterInfo, len(fetched))
+	for _, info := range fetched {
+		if info.Regcode != nil {
+			byRegcode[*info.Regcode] = info
+		}
+	}
+	for _, regcode := range paginatedRegCodes {
+		if info, ok := byRegcode[regcode]; ok {
+			simplePaginatedData = append(simplePaginatedData, info)
+		}
+	}
 	log.Printf("DetailedSearch (Simplified): Phase 2 finished - Fetched simplified data for %d companies.", len(simplePaginatedData))
 
 	// --- Этап 3: Формирование финального ответа ---
@@ -129,3 +145,374 @@ func DetailedSearch(c *gin.Context) {
 	log.Printf("DetailedSearch (Simplified): Request successful. Returning %d records.", len(simplePaginatedData))
 	c.JSON(http.StatusOK, response)
 }
+
+// findRegcodesFTS ищет regcode через FTS5: сначала точные совпадения по regcode/SEPA,
+// затем полнотекстовые совпадения в порядке BM25 (совпадение в названии весит больше, чем в адресе).
+// Фильтры из запроса (applyRegisterFilters) применяются к обоим этапам.
+func findRegcodesFTS(c *gin.Context, searchTerm string) ([]string, error) {
+	searchTermLower := strings.ToLower(strings.TrimSpace(searchTerm))
+
+	exactQuery, err := applyRegisterFilters(c, db.DB.Model(&models.Registers{}).
+		Where("LOWER(registers.regcode) = ? OR LOWER(registers.sepa) = ?", searchTermLower, searchTermLower))
+	if err != nil {
+		return nil, err
+	}
+	var exactMatches []string
+	if err := exactQuery.Pluck("registers.regcode", &exactMatches).Error; err != nil {
+		return nil, err
+	}
+
+	var ftsMatches []string
+	// Запрос нормализуется так же, как токенизатор FTS нормализует данные (регистр и диакритика)
+	if matchQuery := buildFTSQuery(utils.Fold(searchTerm)); matchQuery != "" {
+		ftsQuery, err := applyRegisterFilters(c, db.DB.Model(&models.Registers{}).
+			Joins("JOIN registers_fts ON registers_fts.rowid = registers.id").
+			Where("registers_fts MATCH ?", matchQuery))
+		if err != nil {
+			return nil, err
+		}
+		err = ftsQuery.Order("bm25(registers_fts, 10.0, 5.0, 5.0, 1.0)").
+			Pluck("registers.regcode", &ftsMatches).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return appendUniqueRegcodes(nil, append(exactMatches, ftsMatches...)...), nil
+}
+
+// findRegcodesLike - запасной поиск через LIKE (когда FTS5 недоступен), отсортированный по regcode.
+// Название сравнивается по колонке name_folded, поэтому "lusins" находит "Lūsiņš".
+func findRegcodesLike(c *gin.Context, searchTerm string) ([]string, error) {
+	searchTermLower := strings.ToLower(strings.TrimSpace(searchTerm))
+
+	// Условия поиска группируем в скобки, чтобы фильтры применялись ко всем OR
+	matchGroup := db.DB.Where("LOWER(registers.regcode) = ?", searchTermLower).
+		Or("LOWER(registers.sepa) = ?", searchTermLower)
+	if condition, args := foldedNameCondition("registers.name_folded", searchTerm); condition != "" {
+		matchGroup = matchGroup.Or(condition, args...)
+	}
+	queryBuilder, err := applyRegisterFilters(c, db.DB.Model(&models.Registers{}).Distinct("registers.regcode").Where(matchGroup))
+	if err != nil {
+		return nil, err
+	}
+
+	var registerMatches []string
+	err = queryBuilder.Pluck("registers.regcode", &registerMatches).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	uniqueRegCodes := appendUniqueRegcodes(nil, registerMatches...)
+	sort.Strings(uniqueRegCodes) // Сортируем для стабильности пагинации
+	return uniqueRegCodes, nil
+}
+
+// foldedNameCondition строит условие поиска по *_folded колонке: запрос нормализуется
+// так же, как данные при импорте (utils.FoldName), и каждое слово должно встретиться
+// в имени в любом порядке ("didzis kadaks" находит "Kadaks Didzis").
+// Возвращает пустое условие, если в запросе нет ни одного слова.
+func foldedNameCondition(column string, searchTerm string) (string, []interface{}) {
+	words := strings.Fields(utils.FoldName(searchTerm))
+	if len(words) == 0 {
+		return "", nil
+	}
+	conditions := make([]string, 0, len(words))
+	args := make([]interface{}, 0, len(words))
+	for _, word := range words {
+		conditions = append(conditions, column+" LIKE ?")
+		args = append(args, "%"+word+"%")
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", args
+}
+
+// appendUniqueRegcodes добавляет непустые regcode, сохраняя порядок и пропуская повторы.
+func appendUniqueRegcodes(dst []string, regcodes ...string) []string {
+	seen := make(map[string]bool, len(dst)+len(regcodes))
+	for _, regcode := range dst {
+		seen[regcode] = true
+	}
+	for _, regcode := range regcodes {
+		if regcode != "" && !seen[regcode] {
+			seen[regcode] = true
+			dst = append(dst, regcode)
+		}
+	}
+	return dst
+}
+
+// buildFTSQuery превращает пользовательский ввод в безопасное выражение FTS5 MATCH.
+// Каждое слово берется в кавычки (спецсимволы FTS5 не интерпретируются), слова объединяются через AND.
+// "фраза в кавычках" остается фразой, слово* - префиксный поиск.
+func buildFTSQuery(searchTerm string) string {
+	var parts []string
+	rest := searchTerm
+	for {
+		open := strings.IndexByte(rest, '"')
+		if open < 0 {
+			parts = append(parts, ftsWordTerms(rest)...)
+			break
+		}
+		parts = append(parts, ftsWordTerms(rest[:open])...)
+		rest = rest[open+1:]
+
+		closing := strings.IndexByte(rest, '"')
+		phrase := rest
+		if closing >= 0 {
+			phrase = rest[:closing]
+			rest = rest[closing+1:]
+		} else {
+			rest = ""
+		}
+		if words := strings.Fields(phrase); len(words) > 0 {
+			parts = append(parts, `"`+strings.Join(words, " ")+`"`)
+		}
+		if closing < 0 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ftsWordTerms разбивает текст на отдельные слова FTS5, сохраняя признак префикса (*).
+func ftsWordTerms(text string) []string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.Trim(word, "*")
+		if word == "" {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// identityNumberPattern - начало латвийского персонального кода: "090859", "090859-", "090859-*****"
+var identityNumberPattern = regexp.MustCompile(`^\d{6}(-[\d*]*)?$`)
+
+// SearchPersons godoc
+// @Summary Поиск физических лиц по всем компаниям
+// @Description Ищет физических лиц среди участников (members), бенефициаров (beneficial owners) и должностных лиц (officers) по имени (без учета регистра, диакритики и порядка слов) или по маскированному персональному коду ("090859" или "090859-*****"). Записи одного лица (person_id, см. сопоставление лиц в импортере) возвращаются одним результатом со списком всех найденных компаний и ролей. Группировка, подсчет и пагинация выполняются в БД, поэтому total_records - точное число лиц.
+// @Tags search
+// @Produce json
+// @Param q query string true "Имя лица или начало персонального кода"
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.PersonSearchResult} "Пагинированный список лиц с их компаниями"
+// @Failure 400 {object} HTTPError "Неверный запрос (отсутствует 'q')"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /search/persons [get]
+func SearchPersons(c *gin.Context) {
+	searchTerm := strings.TrimSpace(c.Query("q"))
+	if searchTerm == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("поисковый параметр 'q' обязателен")))
+		return
+	}
+	pagination := utils.GetPaginationParams(c)
+	log.Printf("SearchPersons: SearchTerm: '%s', Page: %d, Limit: %d", searchTerm, pagination.Page, pagination.Limit)
+
+	// --- Условие поиска: по персональному коду или по нормализованному имени ---
+	var condition string
+	var args []interface{}
+	if identityNumberPattern.MatchString(searchTerm) {
+		condition = "latvian_identity_number_masked LIKE ?"
+		args = []interface{}{searchTerm[:6] + "%"}
+	} else {
+		condition, args = foldedNameCondition("name_folded", searchTerm)
+	}
+	if condition == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("поисковый параметр 'q' не содержит букв или цифр")))
+		return
+	}
+
+	// --- Этап 1: Лица страницы (группировка и пагинация в БД) ---
+	matches := personMatchesQuery(condition, args)
+	var totalRecords int64
+	if err := db.DB.Table("(?) AS matches", matches).Select("COUNT(DISTINCT person_key)").Scan(&totalRecords).Error; err != nil {
+		log.Printf("SearchPersons: Error counting persons: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка подсчета лиц: %w", err)))
+		return
+	}
+	var pageKeys []string
+	err := db.DB.Table("(?) AS matches", matches).Group("person_key").Order("MIN(name_folded), person_key").
+		Limit(pagination.Limit).Offset(pagination.Offset).Pluck("person_key", &pageKeys).Error
+	if err != nil {
+		log.Printf("SearchPersons: Error fetching persons page: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка поиска лиц: %w", err)))
+		return
+	}
+
+	// --- Этап 2: Найденные строки лиц страницы, сгруппированные по ключу ---
+	var personIDs []uint
+	rowIDs := map[string][]uint{}
+	for _, key := range pageKeys {
+		prefix, value, _ := strings.Cut(key, ":")
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		if prefix == "p" {
+			personIDs = append(personIDs, uint(id))
+		} else {
+			rowIDs[prefix] = append(rowIDs[prefix], uint(id))
+		}
+	}
+	pageRows := func(prefix string) *gorm.DB {
+		return db.DB.Where(condition, args...).Where("person_id IN ? OR id IN ?", personIDs, rowIDs[prefix]).Order("id")
+	}
+	var members []models.Member
+	if err := pageRows("m").Where("entity_type = ?", "NATURAL_PERSON").Find(&members).Error; err != nil {
+		log.Printf("SearchPersons: Error loading members: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка поиска участников: %w", err)))
+		return
+	}
+	var owners []models.BeneficialOwner
+	if err := pageRows("b").Find(&owners).Error; err != nil {
+		log.Printf("SearchPersons: Error loading beneficial owners: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка поиска бенефициаров: %w", err)))
+		return
+	}
+	var officers []models.Officer
+	if err := pageRows("o").Where("entity_type = ?", "NATURAL_PERSON").Find(&officers).Error; err != nil {
+		log.Printf("SearchPersons: Error loading officers: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка поиска должностных лиц: %w", err)))
+		return
+	}
+
+	persons := make(map[string]*models.PersonSearchResult, len(pageKeys))
+	addRole := func(key string, personID *uint, name *string, masked *string, birthDate *time.Time, role models.PersonCompanyRole) {
+		if name == nil || role.Regcode == "" {
+			return
+		}
+		person, ok := persons[key]
+		if !ok {
+			person = &models.PersonSearchResult{PersonID: personID, Name: *name, LatvianIdentityNumberMasked: masked, BirthDate: birthDate}
+			persons[key] = person
+		}
+		if person.BirthDate == nil {
+			person.BirthDate = birthDate
+		}
+		person.Companies = append(person.Companies, role)
+	}
+	for _, m := range members {
+		addRole(searchPersonKey("m", m.PersonID, m.ID), m.PersonID, m.Name, m.LatvianIdentityNumberMasked, m.BirthDate, memberRole(m))
+	}
+	for _, o := range owners {
+		name := beneficialOwnerName(o)
+		addRole(searchPersonKey("b", o.PersonID, o.ID), o.PersonID, &name, o.LatvianIdentityNumberMasked, o.BirthDate, beneficialOwnerRole(o))
+	}
+	for _, o := range officers {
+		addRole(searchPersonKey("o", o.PersonID, o.ID), o.PersonID, o.Name, o.LatvianIdentityNumberMasked, o.BirthDate, officerRole(o))
+	}
+
+	// --- Этап 3: Названия компаний для страницы ---
+	var regcodes []string
+	for _, person := range persons {
+		for _, role := range person.Companies {
+			regcodes = appendUniqueRegcodes(regcodes, role.Regcode)
+		}
+	}
+	companyNames, err := loadCompanyNames(regcodes)
+	if err != nil {
+		log.Printf("SearchPersons: Error fetching company names: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки названий компаний: %w", err)))
+		return
+	}
+
+	data := make([]models.PersonSearchResult, 0, len(pageKeys))
+	for _, key := range pageKeys {
+		found, ok := persons[key]
+		if !ok {
+			continue
+		}
+		person := *found
+		for i := range person.Companies {
+			person.Companies[i].CompanyName = companyNames[person.Companies[i].Regcode]
+		}
+		data = append(data, person)
+	}
+
+	log.Printf("SearchPersons: Request successful. Returning %d of %d persons.", len(data), totalRecords)
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         data,
+	})
+}
+
+// personMatchesQuery - подзапрос (person_key, name_folded) по совпадениям в members, beneficial_owners и
+// officers. Ключ лица - "p:<person_id>" после сопоставления импортером; строка без person_id считается
+// отдельным лицом ("m:<id>", "b:<id>", "o:<id>"). Строки без компании не учитываются, как и в ответе.
+func personMatchesQuery(condition string, args []interface{}) *gorm.DB {
+	members := db.DB.Model(&models.Member{}).Select(personKeySQL("m")+" AS person_key, name_folded").
+		Where("entity_type = ? AND name IS NOT NULL AND at_legal_entity_registration_number <> ''", "NATURAL_PERSON").Where(condition, args...)
+	owners := db.DB.Model(&models.BeneficialOwner{}).Select(personKeySQL("b")+" AS person_key, name_folded").
+		Where("legal_entity_registration_number <> ''").Where(condition, args...)
+	officers := db.DB.Model(&models.Officer{}).Select(personKeySQL("o")+" AS person_key, name_folded").
+		Where("entity_type = ? AND name IS NOT NULL AND at_legal_entity_registration_number <> ''", "NATURAL_PERSON").Where(condition, args...)
+	return db.DB.Raw("? UNION ALL ? UNION ALL ?", members, owners, officers)
+}
+
+// personKeySQL - выражение ключа лица для строки таблицы (см. personMatchesQuery, searchPersonKey)
+func personKeySQL(prefix string) string {
+	return "COALESCE('p:' || CAST(person_id AS TEXT), '" + prefix + ":' || CAST(id AS TEXT))"
+}
+
+// searchPersonKey - тот же ключ лица, что personKeySQL, для загруженной строки
+func searchPersonKey(prefix string, personID *uint, id uint) string {
+	if personID != nil {
+		return "p:" + strconv.FormatUint(uint64(*personID), 10)
+	}
+	return prefix + ":" + strconv.FormatUint(uint64(id), 10)
+}
+
+// memberRole - роль участника; компания, в которой лицо является участником - at_legal_entity_registration_number
+func memberRole(m models.Member) models.PersonCompanyRole {
+	return models.PersonCompanyRole{
+		Regcode: utils.StringValue(m.AtLegalEntityRegistrationNumber), Role: "member",
+		NumberOfShares: m.NumberOfShares, Since: m.DateFrom, Confidence: m.PersonConfidence,
+	}
+}
+
+func beneficialOwnerRole(o models.BeneficialOwner) models.PersonCompanyRole {
+	return models.PersonCompanyRole{
+		Regcode: utils.StringValue(o.LegalEntityRegistrationNumber), Role: "beneficial_owner",
+		Since: o.RegisteredOn, Confidence: o.PersonConfidence,
+	}
+}
+
+func officerRole(o models.Officer) models.PersonCompanyRole {
+	return models.PersonCompanyRole{
+		Regcode: utils.StringValue(o.AtLegalEntityRegistrationNumber), Role: "officer",
+		Position: o.Position, GoverningBody: o.GoverningBody, Since: o.RegisteredOn, Confidence: o.PersonConfidence,
+	}
+}
+
+// beneficialOwnerName - в beneficial_owners имя хранится раздельно, собираем "Имя Фамилия"
+func beneficialOwnerName(o models.BeneficialOwner) string {
+	return strings.TrimSpace(utils.StringValue(o.Forename) + " " + utils.StringValue(o.Surname))
+}
+
+// loadCompanyNames возвращает названия компаний из registers по regcode
+func loadCompanyNames(regcodes []string) (map[string]*string, error) {
+	names := make(map[string]*string, len(regcodes))
+	if len(regcodes) == 0 {
+		return names, nil
+	}
+	var companies []models.SimpleRegisterInfo
+	if err := db.DB.Model(&models.Registers{}).Select("regcode", "name").Where("regcode IN ?", regcodes).Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	for _, company := range companies {
+		if company.Regcode != nil {
+			names[*company.Regcode] = company.Name
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,165 @@
+// handlers/shareholding_handlers.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// В members.csv at_legal_entity_registration_number - компания, в которой участник держит доли,
+// а legal_entity_registration_number - regcode самого участника, если он юр. лицо.
+// Отсюда два направления: shareholders (кто держит доли компании) и holdings (чьи доли держит компания).
+
+// GetCompanyShareholders godoc
+// @Summary Участники (владельцы долей) компании
+// @Description Возвращает пагинированный список тех, кто держит доли компании: записи members с at_legal_entity_registration_number = regcode. ownership_percent - доля участника в уставном капитале (число акций * номинал, LVL пересчитывается в EUR, если валюты участников различаются). С as_of - участники на эту дату по версиям записей (entity_versions).
+// @Tags company
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param as_of query string false "Состояние на дату (dd/mm/yyyy или yyyy-mm-dd)"
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.Member} "Пагинированный список участников"
+// @Failure 400 {object} HTTPError "Неверный Regcode или as_of"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/shareholders [get]
+func GetCompanyShareholders(c *gin.Context) {
+	respondShareholders(c)
+}
+
+// respondShareholders - общий обработчик /company/:regcode/shareholders и /members/by-regcode/:regcode
+func respondShareholders(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	pagination := utils.GetPaginationParams(c)
+
+	// На дату: участники из версий записей, доли - по составу на ту же дату
+	if asOf != nil {
+		members, err := membersAsOf(regcode, *asOf)
+		if err != nil {
+			log.Printf("Error loading members of regcode %s as of %s: %v", regcode, asOf.Format("2006-01-02"), err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		start, end := pageOf(len(members), pagination)
+		c.JSON(http.StatusOK, models.PaginatedResponse{
+			TotalRecords: int64(len(members)),
+			Page:         pagination.Page,
+			Limit:        pagination.Limit,
+			Data:         members[start:end],
+		})
+		return
+	}
+
+	var members []models.Member
+	var totalRecords int64
+
+	// Участники компании - записи, где она указана как компания участия
+	queryBuilder := db.DB.Model(&models.Member{}).Where("at_legal_entity_registration_number = ?", regcode)
+
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting members for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	err = queryBuilder.Order("id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&members).Error
+	if err != nil {
+		log.Printf("Error finding members for regcode %s with pagination: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	// Доля в капитале считается по всем участникам компании, а не только по странице
+	if err := applyOwnershipPercents(members); err != nil {
+		log.Printf("Error computing ownership percentages for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         members,
+	})
+}
+
+// GetCompanyHoldings godoc
+// @Summary Доли компании в других компаниях
+// @Description Возвращает пагинированный список компаний, доли которых держит компания: записи members с legal_entity_registration_number = regcode. Название и статус - из реестра (если компания в нем есть). ownership_percent - доля в уставном капитале компании, посчитанная по всем ее участникам.
+// @Tags company
+// @Produce json
+// @Param regcode path string true "Regcode компании-участника"
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.Holding} "Пагинированный список долей"
+// @Failure 400 {object} HTTPError "Неверный Regcode"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/holdings [get]
+func GetCompanyHoldings(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	pagination := utils.GetPaginationParams(c)
+
+	var members []models.Member
+	var totalRecords int64
+
+	queryBuilder := db.DB.Model(&models.Member{}).Where("legal_entity_registration_number = ?", regcode)
+
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting holdings for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	err := queryBuilder.Preload("Company").
+		Order("at_legal_entity_registration_number").Order("id").
+		Limit(pagination.Limit).Offset(pagination.Offset).
+		Find(&members).Error
+	if err != nil {
+		log.Printf("Error finding holdings for regcode %s with pagination: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	// Доля считается по всем участникам каждой компании, в которой держится доля
+	if err := applyOwnershipPercents(members); err != nil {
+		log.Printf("Error computing holding percentages for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	holdings := make([]models.Holding, 0, len(members))
+	for _, member := range members {
+		holdings = append(holdings, models.NewHolding(member))
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         holdings,
+	})
+}
@@ -0,0 +1,85 @@
+// handlers/shareholding_handlers_test.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"capital-view-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Участники - строки members.csv из csv_examples после импорта. В примерах нет участников - юр. лиц,
+// поэтому участник 93 - компания IK DANDIS (40002158072), которая держит доли 40103196773
+func TestShareholdersAndHoldings(t *testing.T) {
+	database := useTestSQLite(t, &models.Registers{}, &models.Member{})
+	members := []models.Member{
+		{ID: 3, AtLegalEntityRegistrationNumber: ptr("40103165237"), EntityType: ptr("NATURAL_PERSON"), Name: ptr("Kadaks Didzis"), NumberOfShares: ptr(100.0)},
+		{ID: 38, AtLegalEntityRegistrationNumber: ptr("40103182646"), EntityType: ptr("NATURAL_PERSON"), Name: ptr("Lipšāns Rolands"), NumberOfShares: ptr(2000.0)},
+		{ID: 93, AtLegalEntityRegistrationNumber: ptr("40103196773"), EntityType: ptr("LEGAL_ENTITY"), Name: ptr("IK DANDIS"), LegalEntityRegistrationNumber: ptr("40002158072"), NumberOfShares: ptr(10.0)},
+	}
+	if err := database.Create(&members).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/company/:regcode/shareholders", GetCompanyShareholders)
+	router.GET("/company/:regcode/holdings", GetCompanyHoldings)
+
+	tests := []struct {
+		path  string
+		idKey string
+		want  []float64 // id участников (shareholders) или member_id долей (holdings)
+	}{
+		// shareholders - по at_legal_entity_registration_number (компания, в которой держатся доли)
+		{"/company/40103196773/shareholders", "id", []float64{93}},
+		{"/company/40002158072/shareholders", "id", []float64{}},
+		{"/company/40103182646/shareholders", "id", []float64{38}},
+		// holdings - по legal_entity_registration_number (regcode участника - юр. лица)
+		{"/company/40002158072/holdings", "member_id", []float64{93}},
+		{"/company/40103196773/holdings", "member_id", []float64{}},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("%s: status %d, body %s", tt.path, recorder.Code, recorder.Body.String())
+			continue
+		}
+		var response struct {
+			TotalRecords int64                    `json:"total_records"`
+			Data         []map[string]interface{} `json:"data"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		ids := []float64{}
+		for _, row := range response.Data {
+			id, _ := row[tt.idKey].(float64)
+			ids = append(ids, id)
+		}
+		if !reflect.DeepEqual(ids, tt.want) || response.TotalRecords != int64(len(tt.want)) {
+			t.Errorf("%s: ids %v (total %d), want %v", tt.path, ids, response.TotalRecords, tt.want)
+		}
+	}
+
+	// Доля указывает на компанию, в которой она держится
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/company/40002158072/holdings", nil))
+	var holdings struct {
+		Data []struct {
+			Regcode string `json:"regcode"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &holdings); err != nil {
+		t.Fatal(err)
+	}
+	if len(holdings.Data) != 1 || holdings.Data[0].Regcode != "40103196773" {
+		t.Errorf("holdings of 40002158072 = %+v, want one holding in 40103196773", holdings.Data)
+	}
+}
@@ -0,0 +1,172 @@
+// handlers/timeseries_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultTimeSeriesFields - поля по умолчанию, если ?fields= не указан
+var defaultTimeSeriesFields = []string{"net_turnover", "net_income", "total_assets", "equity", "employees"}
+
+// GetCompanyTimeSeries godoc
+// @Summary Временные ряды строк фин. отчетов компании
+// @Description Возвращает по одному ряду на каждое поле из ?fields= (json-имена строк IncomeStatement, BalanceSheet, CashFlowStatement и employees) по всем годам отчетности компании. Все ряды выровнены по общей оси лет; для каждой точки - абсолютное и процентное изменение к предыдущему году (только если у ряда есть значение за год year-1), для ряда - CAGR между первым и последним значением. change_pct и CAGR всегда считаются по суммам в евро, поэтому переход с LVL на EUR или с THOUSANDS на ONES их не искажает; абсолютное change без ?normalize=eur - в единицах отчета и null, если единицы двух лет различаются. С ?normalize=eur сами суммы приводятся к единицам евро.
+// @Tags company
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param fields query string false "Поля через запятую" default(net_turnover,net_income,total_assets,equity,employees)
+// @Param normalize query string false "eur - привести суммы к единицам евро" Enums(eur)
+// @Success 200 {object} models.CompanyTimeSeries "Ряды по годам"
+// @Failure 400 {object} HTTPError "Неверный Regcode, неизвестное поле или неверный normalize"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/timeseries [get]
+func GetCompanyTimeSeries(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+	normalize, err := parseNormalizeParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	// --- Поля ---
+	fieldNames := splitFilterValues(strings.ToLower(c.Query("fields")))
+	if len(fieldNames) == 0 {
+		fieldNames = defaultTimeSeriesFields
+	}
+	fields := make([]financialField, 0, len(fieldNames))
+	for _, name := range fieldNames {
+		field, ok := financialFields[name]
+		if !ok {
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неизвестное поле '%s'; доступные поля: %s", name, strings.Join(financialFieldNames(), ", "))))
+			return
+		}
+		fields = append(fields, field)
+	}
+
+	// --- Отчеты ---
+	statements, err := loadStatementsWithDetails(regcode)
+	if err != nil {
+		log.Printf("GetCompanyTimeSeries: Error loading statements for regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки фин. отчетов: %w", err)))
+		return
+	}
+	if len(statements) == 0 {
+		exists, err := companyExists(regcode)
+		if err != nil {
+			log.Printf("GetCompanyTimeSeries: Error checking company %s: %v", regcode, err)
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+			return
+		}
+	}
+
+	// Отчеты уже отсортированы по году; отчеты без года на ось не попадают
+	response := models.CompanyTimeSeries{Regcode: regcode, Years: []int{}, Normalized: normalize, Series: []models.TimeSeries{}}
+	var yearStatements []*models.FinancialStatement
+	for i := range statements {
+		if statements[i].Year == nil {
+			continue
+		}
+		if normalize && !normalizeStatementToEUR(&statements[i]) {
+			log.Printf("GetCompanyTimeSeries: Statement %d of %s left as is: unknown currency/rounding", statements[i].ID, regcode)
+		}
+		response.Years = append(response.Years, *statements[i].Year)
+		yearStatements = append(yearStatements, &statements[i])
+	}
+
+	for _, field := range fields {
+		series := models.TimeSeries{Field: field.Name, Source: field.Source, Points: make([]models.TimeSeriesPoint, 0, len(yearStatements))}
+		var previous, first, last *timeSeriesValue
+		for _, statement := range yearStatements {
+			point := models.TimeSeriesPoint{Year: *statement.Year, Value: field.get(statement)}
+			var current *timeSeriesValue
+			if point.Value != nil {
+				current = newTimeSeriesValue(field, statement, point.Year, *point.Value)
+			}
+			// Изменение к предыдущему году - только если предыдущая точка ряда за год year-1
+			if current != nil && previous != nil && previous.year == point.Year-1 {
+				if current.units == previous.units {
+					change := current.value - previous.value
+					point.Change = &change
+				}
+				if from, to, ok := comparableValues(previous, current); ok && from != 0 {
+					changePct := (to - from) / math.Abs(from) * 100
+					point.ChangePct = &changePct
+				}
+			}
+			if current != nil {
+				if first == nil {
+					first = current
+				}
+				last = current
+			}
+			previous = current
+			series.Points = append(series.Points, point)
+		}
+		// CAGR определен только для положительных значений на концах и хотя бы одного года между ними
+		if first != nil && last != nil && last.year > first.year {
+			if from, to, ok := comparableValues(first, last); ok && from > 0 && to > 0 {
+				cagr := (math.Pow(to/from, 1/float64(last.year-first.year)) - 1) * 100
+				series.CAGRPct = &cagr
+				series.CAGRFrom, series.CAGRTo = utils.IntPtr(first.year), utils.IntPtr(last.year)
+			}
+		}
+		response.Series = append(response.Series, series)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// timeSeriesValue - значение ряда за год с единицами отчета для расчета изменений
+type timeSeriesValue struct {
+	year  int
+	value float64  // Как в ответе (в единицах отчета или в евро при ?normalize=eur)
+	units string   // Валюта и единицы отчета ("LVL|THOUSANDS"); у employees пусто
+	eur   *float64 // Значение в единицах евро (nil - валюта или единицы не распознаны)
+}
+
+func newTimeSeriesValue(field financialField, statement *models.FinancialStatement, year int, value float64) *timeSeriesValue {
+	if field.Source == "financial_statement" { // employees - не сумма
+		return &timeSeriesValue{year: year, value: value, eur: &value}
+	}
+	result := &timeSeriesValue{
+		year:  year,
+		value: value,
+		units: strings.ToUpper(strings.TrimSpace(utils.StringValue(statement.Currency)) + "|" + strings.TrimSpace(utils.StringValue(statement.RoundedToNearest))),
+	}
+	if multiplier, ok := utils.EURMultiplier(statement.Currency, statement.RoundedToNearest); ok {
+		eur := value * multiplier
+		result.eur = &eur
+	}
+	return result
+}
+
+// comparableValues - значения двух точек в общих единицах: как есть, если единицы отчетов совпадают,
+// иначе в евро (переход с LVL на EUR, с THOUSANDS на ONES). ok = false, если привести нельзя.
+func comparableValues(from, to *timeSeriesValue) (float64, float64, bool) {
+	if from.units == to.units {
+		return from.value, to.value, true
+	}
+	if from.eur == nil || to.eur == nil {
+		return 0, 0, false
+	}
+	return *from.eur, *to.eur, true
+}
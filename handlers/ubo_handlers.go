@@ -0,0 +1,249 @@
+// handlers/ubo_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUBOAnalysis godoc
+// @Summary Анализ конечных бенефициаров (UBO)
+// @Description Обходит цепочку участников вверх до физ. лиц (как effective-owners) и отбирает тех, чья косвенная доля не меньше порога (по умолчанию 25%). Сравнивает их с заявленными бенефициарами (beneficial_owners) и сообщает о расхождениях: undeclared_owner - вероятный бенефициар не заявлен; declared_without_ownership - заявленный бенефициар не найден среди владельцев; no_declaration - у компании (или у юр. лица в ее цепочке) нет заявленных бенефициаров; unresolved_owner - юр. лицо с долей не меньше порога, чьи участники неизвестны. Лица сопоставляются по person_id, иначе по маскированному коду и имени без учета порядка слов. Бенефициар может контролировать компанию и без доли, поэтому declared_without_ownership - повод для проверки, а не нарушение.
+// @Tags ownership
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param depth query int false "Глубина обхода" default(10) minimum(1) maximum(10)
+// @Param threshold query number false "Порог косвенной доли, %" default(25)
+// @Success 200 {object} models.UBOAnalysis "Вероятные и заявленные бенефициары, расхождения"
+// @Failure 400 {object} HTTPError "Неверный Regcode, depth или threshold"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /company/{regcode}/ubo-analysis [get]
+func GetUBOAnalysis(c *gin.Context) {
+	regcode := c.Param("regcode")
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+	// Для UBO нужна вся цепочка: по умолчанию максимальная глубина
+	depth := maxOwnershipDepth
+	if c.Query("depth") != "" {
+		var err error
+		if depth, err = parseOwnershipDepth(c); err != nil {
+			c.JSON(http.StatusBadRequest, NewHTTPError(err))
+			return
+		}
+	}
+	threshold := models.UBOThresholdPercent
+	if value := c.Query("threshold"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("threshold должен быть числом больше 0 и не больше 100")))
+			return
+		}
+		threshold = parsed
+	}
+
+	graph, err := loadOwnershipGraph(regcode, models.OwnershipDirectionOwners, depth)
+	if err != nil {
+		log.Printf("GetUBOAnalysis: Error loading owners of regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(fmt.Errorf("ошибка загрузки связей: %w", err)))
+		return
+	}
+
+	var declared []models.BeneficialOwner
+	if err := db.DB.Where("legal_entity_registration_number = ?", regcode).Order("id").Find(&declared).Error; err != nil {
+		log.Printf("GetUBOAnalysis: Error loading beneficial owners of regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	if _, ok := graph.names[regcode]; !ok && len(graph.edges[regcode]) == 0 && len(declared) == 0 {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+		return
+	}
+
+	analysis := analyzeUBO(graph.effectiveOwners(regcode, depth), declared, threshold)
+
+	// Юр. лица в цепочке (из реестра), у которых тоже нет заявленных бенефициаров
+	undeclared, err := companiesWithoutDeclaration(graph, regcode)
+	if err != nil {
+		log.Printf("GetUBOAnalysis: Error checking declarations in the chain of regcode %s: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	for _, company := range undeclared {
+		company := company
+		analysis.Discrepancies = append(analysis.Discrepancies, models.UBODiscrepancy{
+			Type:    models.UBODiscrepancyNoDeclaration,
+			Message: "у компании в цепочке владения нет заявленных бенефициаров",
+			Regcode: &company,
+			Name:    graph.names[company],
+		})
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// analyzeUBO сопоставляет конечных владельцев с заявленными бенефициарами
+func analyzeUBO(ownership models.EffectiveOwnership, declared []models.BeneficialOwner, threshold float64) models.UBOAnalysis {
+	analysis := models.UBOAnalysis{
+		Regcode:          ownership.Regcode,
+		Name:             ownership.Name,
+		Depth:            ownership.Depth,
+		ThresholdPercent: threshold,
+		Truncated:        ownership.Truncated,
+		Derived:          []models.DerivedUBO{},
+		Declared:         make([]models.DeclaredUBO, 0, len(declared)),
+		Discrepancies:    []models.UBODiscrepancy{},
+	}
+	if len(declared) == 0 {
+		regcode := ownership.Regcode
+		analysis.Discrepancies = append(analysis.Discrepancies, models.UBODiscrepancy{
+			Type:    models.UBODiscrepancyNoDeclaration,
+			Message: "у компании нет заявленных бенефициаров",
+			Regcode: &regcode,
+			Name:    ownership.Name,
+		})
+	}
+
+	// Заявленные: ищем каждого среди физ. лиц-владельцев с любой долей
+	matched := make(map[int]uint) // индекс владельца -> beneficial_owners.id
+	for _, owner := range declared {
+		item := models.DeclaredUBO{
+			BeneficialOwnerID:           owner.ID,
+			Forename:                    owner.Forename,
+			Surname:                     owner.Surname,
+			LatvianIdentityNumberMasked: owner.LatvianIdentityNumberMasked,
+			PersonID:                    owner.PersonID,
+		}
+		for i, effective := range ownership.Owners {
+			if !isNaturalPerson(effective) || !sameUBO(effective, owner) {
+				continue
+			}
+			item.OwnershipFound = true
+			item.EffectivePercent = effective.EffectivePercent
+			if _, ok := matched[i]; !ok {
+				matched[i] = owner.ID
+			}
+			break
+		}
+		analysis.Declared = append(analysis.Declared, item)
+		if !item.OwnershipFound {
+			id := owner.ID
+			name := strings.TrimSpace(utils.StringValue(owner.Forename) + " " + utils.StringValue(owner.Surname))
+			analysis.Discrepancies = append(analysis.Discrepancies, models.UBODiscrepancy{
+				Type:                        models.UBODiscrepancyNoOwnership,
+				Message:                     "заявленный бенефициар не найден среди владельцев по цепочке участников",
+				Name:                        &name,
+				LatvianIdentityNumberMasked: owner.LatvianIdentityNumberMasked,
+				BeneficialOwnerID:           &id,
+			})
+		}
+	}
+
+	// Вероятные бенефициары: физ. лица с косвенной долей не ниже порога
+	for i, owner := range ownership.Owners {
+		if owner.EffectivePercent == nil || *owner.EffectivePercent < threshold {
+			continue
+		}
+		if !isNaturalPerson(owner) {
+			analysis.Discrepancies = append(analysis.Discrepancies, models.UBODiscrepancy{
+				Type:             models.UBODiscrepancyUnresolved,
+				Message:          "юр. лицо с долей не ниже порога: его участники неизвестны, бенефициара по цепочке определить нельзя",
+				Regcode:          owner.Regcode,
+				Name:             owner.Name,
+				EffectivePercent: owner.EffectivePercent,
+			})
+			continue
+		}
+		derived := models.DerivedUBO{
+			Name:                        owner.Name,
+			LatvianIdentityNumberMasked: owner.LatvianIdentityNumberMasked,
+			PersonID:                    owner.PersonID,
+			EffectivePercent:            owner.EffectivePercent,
+			Paths:                       owner.Paths,
+			Incomplete:                  owner.Incomplete,
+		}
+		if id, ok := matched[i]; ok {
+			id := id
+			derived.Declared = true
+			derived.BeneficialOwnerID = &id
+		} else {
+			analysis.Discrepancies = append(analysis.Discrepancies, models.UBODiscrepancy{
+				Type:                        models.UBODiscrepancyUndeclared,
+				Message:                     fmt.Sprintf("физ. лицо владеет %.2f%% (порог %.0f%%), но не заявлено бенефициаром", *owner.EffectivePercent, threshold),
+				Name:                        owner.Name,
+				LatvianIdentityNumberMasked: owner.LatvianIdentityNumberMasked,
+				EffectivePercent:            owner.EffectivePercent,
+			})
+		}
+		analysis.Derived = append(analysis.Derived, derived)
+	}
+	return analysis
+}
+
+// isNaturalPerson - конечный владелец является физ. лицом (юр. лица без regcode, например
+// иностранные, физ. лицами не считаются)
+func isNaturalPerson(owner models.EffectiveOwner) bool {
+	return owner.Regcode == nil && utils.StringValue(owner.EntityType) == "NATURAL_PERSON"
+}
+
+// sameUBO - владелец из members и заявленный бенефициар - одно лицо: по person_id, если он есть
+// у обоих, иначе по маскированному коду и имени (без кода - только по имени)
+func sameUBO(owner models.EffectiveOwner, declared models.BeneficialOwner) bool {
+	if owner.PersonID != nil && declared.PersonID != nil {
+		return *owner.PersonID == *declared.PersonID
+	}
+	name := utils.StringValue(declared.Forename) + " " + utils.StringValue(declared.Surname)
+	if owner.LatvianIdentityNumberMasked == nil || declared.LatvianIdentityNumberMasked == nil {
+		return utils.PersonKey(utils.StringValue(owner.Name), nil) == utils.PersonKey(name, nil)
+	}
+	return utils.PersonKey(utils.StringValue(owner.Name), owner.LatvianIdentityNumberMasked) ==
+		utils.PersonKey(name, declared.LatvianIdentityNumberMasked)
+}
+
+// companiesWithoutDeclaration возвращает юр. лица из цепочки владения root (кроме самой root),
+// которые есть в реестре, но не имеют ни одной записи beneficial_owners
+func companiesWithoutDeclaration(graph *ownershipGraph, root string) ([]string, error) {
+	var companies []string
+	for regcode := range graph.edges {
+		if _, ok := graph.names[regcode]; ok && regcode != root {
+			companies = append(companies, regcode)
+		}
+	}
+	if len(companies) == 0 {
+		return nil, nil
+	}
+	sort.Strings(companies)
+
+	var declaredIn []string
+	err := db.DB.Model(&models.BeneficialOwner{}).
+		Where("legal_entity_registration_number IN ?", companies).
+		Distinct().Pluck("legal_entity_registration_number", &declaredIn).Error
+	if err != nil {
+		return nil, err
+	}
+	hasDeclaration := make(map[string]bool, len(declaredIn))
+	for _, regcode := range declaredIn {
+		hasDeclaration[regcode] = true
+	}
+
+	var result []string
+	for _, regcode := range companies {
+		if !hasDeclaration[regcode] {
+			result = append(result, regcode)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,225 @@
+// handlers/ubo_handlers_test.go
+package handlers
+
+import (
+	"testing"
+
+	"capital-view-api/models"
+)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// naturalOwner - физ. лицо-владелец с косвенной долей
+func naturalOwner(name string, masked *string, personID *uint, percent float64) models.EffectiveOwner {
+	return models.EffectiveOwner{
+		Name: ptr(name), EntityType: ptr("NATURAL_PERSON"), LatvianIdentityNumberMasked: masked,
+		PersonID: personID, EffectivePercent: ptr(percent), Paths: 1,
+	}
+}
+
+// discrepancyTypes - типы расхождений по порядку
+func discrepancyTypes(analysis models.UBOAnalysis) []string {
+	types := make([]string, 0, len(analysis.Discrepancies))
+	for _, discrepancy := range analysis.Discrepancies {
+		types = append(types, discrepancy.Type)
+	}
+	return types
+}
+
+func TestAnalyzeUBODeclaredAndUndeclared(t *testing.T) {
+	ownership := models.EffectiveOwnership{
+		Regcode: "40000000001",
+		Owners: []models.EffectiveOwner{
+			naturalOwner("Jānis Bērziņš", ptr("010170-*****"), ptr(uint(1)), 60),
+			naturalOwner("Anna Kalniņa", ptr("020280-*****"), ptr(uint(2)), 25), // Ровно на пороге
+			naturalOwner("Pēteris Ozols", ptr("030390-*****"), ptr(uint(3)), 15),
+		},
+	}
+	declared := []models.BeneficialOwner{
+		{ID: 10, Forename: ptr("Jānis"), Surname: ptr("Bērziņš"), LatvianIdentityNumberMasked: ptr("010170-*****"), PersonID: ptr(uint(1))},
+		{ID: 11, Forename: ptr("Pēteris"), Surname: ptr("Ozols"), LatvianIdentityNumberMasked: ptr("030390-*****"), PersonID: ptr(uint(3))},
+	}
+
+	analysis := analyzeUBO(ownership, declared, models.UBOThresholdPercent)
+
+	if len(analysis.Derived) != 2 {
+		t.Fatalf("derived = %+v, want Jānis and Anna", analysis.Derived)
+	}
+	if janis := analysis.Derived[0]; !janis.Declared || janis.BeneficialOwnerID == nil || *janis.BeneficialOwnerID != 10 {
+		t.Errorf("Jānis = %+v, want declared as beneficial owner 10", janis)
+	}
+	if anna := analysis.Derived[1]; anna.Declared || anna.BeneficialOwnerID != nil {
+		t.Errorf("Anna = %+v, want undeclared", anna)
+	}
+	if got := discrepancyTypes(analysis); len(got) != 1 || got[0] != models.UBODiscrepancyUndeclared {
+		t.Fatalf("discrepancies = %v, want one undeclared_owner", got)
+	}
+	if name := analysis.Discrepancies[0].Name; name == nil || *name != "Anna Kalniņa" {
+		t.Errorf("undeclared owner = %v, want Anna Kalniņa", name)
+	}
+
+	// Заявленный бенефициар с долей ниже порога найден, но вероятным бенефициаром не считается
+	if len(analysis.Declared) != 2 {
+		t.Fatalf("declared = %+v, want 2", analysis.Declared)
+	}
+	peteris := analysis.Declared[1]
+	if !peteris.OwnershipFound || peteris.EffectivePercent == nil || *peteris.EffectivePercent != 15 {
+		t.Errorf("Pēteris = %+v, want ownership found with 15%%", peteris)
+	}
+}
+
+func TestAnalyzeUBODeclaredWithoutOwnership(t *testing.T) {
+	ownership := models.EffectiveOwnership{
+		Regcode: "40000000001",
+		Owners:  []models.EffectiveOwner{naturalOwner("Jānis Bērziņš", ptr("010170-*****"), nil, 100)},
+	}
+	declared := []models.BeneficialOwner{
+		{ID: 10, Forename: ptr("Jānis"), Surname: ptr("Bērziņš"), LatvianIdentityNumberMasked: ptr("010170-*****")},
+		{ID: 11, Forename: ptr("Ilze"), Surname: ptr("Liepa"), LatvianIdentityNumberMasked: ptr("040475-*****")},
+	}
+
+	analysis := analyzeUBO(ownership, declared, models.UBOThresholdPercent)
+
+	if got := discrepancyTypes(analysis); len(got) != 1 || got[0] != models.UBODiscrepancyNoOwnership {
+		t.Fatalf("discrepancies = %v, want one declared_without_ownership", got)
+	}
+	discrepancy := analysis.Discrepancies[0]
+	if discrepancy.BeneficialOwnerID == nil || *discrepancy.BeneficialOwnerID != 11 || discrepancy.Name == nil || *discrepancy.Name != "Ilze Liepa" {
+		t.Errorf("discrepancy = %+v, want beneficial owner 11 Ilze Liepa", discrepancy)
+	}
+	if analysis.Declared[1].OwnershipFound || analysis.Declared[1].EffectivePercent != nil {
+		t.Errorf("Ilze = %+v, want no ownership", analysis.Declared[1])
+	}
+	if len(analysis.Derived) != 1 || !analysis.Derived[0].Declared {
+		t.Errorf("derived = %+v, want Jānis declared (matched by code and name)", analysis.Derived)
+	}
+}
+
+func TestAnalyzeUBOUnresolvedLegalEntity(t *testing.T) {
+	ownership := models.EffectiveOwnership{
+		Regcode: "40000000001",
+		Owners: []models.EffectiveOwner{
+			// Юр. лицо из реестра без участников в данных
+			{Regcode: ptr("40000000002"), Name: ptr("SIA Holding"), EntityType: ptr("LEGAL_ENTITY"), EffectivePercent: ptr(50.0), Paths: 1},
+			// Иностранное юр. лицо без regcode - тоже не физ. лицо
+			{Name: ptr("Foreign Ltd"), EntityType: ptr("FOREIGN_ENTITY"), EffectivePercent: ptr(30.0), Paths: 1},
+			// Юр. лицо ниже порога - не расхождение
+			{Regcode: ptr("40000000003"), Name: ptr("SIA Small"), EntityType: ptr("LEGAL_ENTITY"), EffectivePercent: ptr(20.0), Paths: 1},
+		},
+	}
+	declared := []models.BeneficialOwner{{ID: 10, Forename: ptr("Jānis"), Surname: ptr("Bērziņš")}}
+
+	analysis := analyzeUBO(ownership, declared, models.UBOThresholdPercent)
+
+	if len(analysis.Derived) != 0 {
+		t.Errorf("derived = %+v, want none (legal entities are not beneficial owners)", analysis.Derived)
+	}
+	got := discrepancyTypes(analysis)
+	want := []string{models.UBODiscrepancyNoOwnership, models.UBODiscrepancyUnresolved, models.UBODiscrepancyUnresolved}
+	if len(got) != len(want) {
+		t.Fatalf("discrepancies = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("discrepancies = %v, want %v", got, want)
+		}
+	}
+	if regcode := analysis.Discrepancies[1].Regcode; regcode == nil || *regcode != "40000000002" {
+		t.Errorf("unresolved owner regcode = %v, want 40000000002", regcode)
+	}
+}
+
+func TestAnalyzeUBONoDeclaration(t *testing.T) {
+	ownership := models.EffectiveOwnership{
+		Regcode: "40000000001",
+		Name:    ptr("SIA Test"),
+		Owners: []models.EffectiveOwner{
+			naturalOwner("Jānis Bērziņš", nil, nil, 50),
+			naturalOwner("Anna Kalniņa", nil, nil, 50),
+		},
+	}
+
+	analysis := analyzeUBO(ownership, nil, models.UBOThresholdPercent)
+
+	got := discrepancyTypes(analysis)
+	want := []string{models.UBODiscrepancyNoDeclaration, models.UBODiscrepancyUndeclared, models.UBODiscrepancyUndeclared}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("discrepancies = %v, want %v", got, want)
+	}
+	if regcode := analysis.Discrepancies[0].Regcode; regcode == nil || *regcode != "40000000001" {
+		t.Errorf("no_declaration regcode = %v, want 40000000001", regcode)
+	}
+	if analysis.Declared == nil || len(analysis.Declared) != 0 {
+		t.Errorf("declared = %#v, want an empty list", analysis.Declared)
+	}
+}
+
+func TestAnalyzeUBOThreshold(t *testing.T) {
+	ownership := models.EffectiveOwnership{
+		Regcode: "40000000001",
+		Owners:  []models.EffectiveOwner{naturalOwner("Jānis Bērziņš", nil, nil, 24.99)},
+	}
+	declared := []models.BeneficialOwner{{ID: 10, Forename: ptr("Jānis"), Surname: ptr("Bērziņš")}}
+
+	if analysis := analyzeUBO(ownership, declared, models.UBOThresholdPercent); len(analysis.Derived) != 0 {
+		t.Errorf("derived at 24.99%% = %+v, want none", analysis.Derived)
+	}
+	if analysis := analyzeUBO(ownership, declared, 10); len(analysis.Derived) != 1 || !analysis.Derived[0].Declared {
+		t.Errorf("derived with threshold 10 = %+v, want Jānis declared", analysis.Derived)
+	}
+}
+
+func TestSameUBO(t *testing.T) {
+	tests := []struct {
+		name     string
+		owner    models.EffectiveOwner
+		declared models.BeneficialOwner
+		want     bool
+	}{
+		{
+			name:     "same person_id, different spelling",
+			owner:    naturalOwner("Bērziņš J.", nil, ptr(uint(1)), 50),
+			declared: models.BeneficialOwner{Forename: ptr("Jānis"), Surname: ptr("Bērziņš"), PersonID: ptr(uint(1))},
+			want:     true,
+		},
+		{
+			name:     "different person_id, same name and code",
+			owner:    naturalOwner("Jānis Bērziņš", ptr("010170-*****"), ptr(uint(1)), 50),
+			declared: models.BeneficialOwner{Forename: ptr("Jānis"), Surname: ptr("Bērziņš"), LatvianIdentityNumberMasked: ptr("010170-*****"), PersonID: ptr(uint(2))},
+			want:     false,
+		},
+		{
+			name:     "code and name in another word order",
+			owner:    naturalOwner("Bērziņš Jānis", ptr("010170-*****"), ptr(uint(1)), 50),
+			declared: models.BeneficialOwner{Forename: ptr("Jānis"), Surname: ptr("Bērziņš"), LatvianIdentityNumberMasked: ptr("010170-*****")},
+			want:     true,
+		},
+		{
+			name:     "same name, different code",
+			owner:    naturalOwner("Jānis Bērziņš", ptr("010170-*****"), nil, 50),
+			declared: models.BeneficialOwner{Forename: ptr("Jānis"), Surname: ptr("Bērziņš"), LatvianIdentityNumberMasked: ptr("020280-*****")},
+			want:     false,
+		},
+		{
+			name:     "no code on one side, name without diacritics",
+			owner:    naturalOwner("Janis Berzins", nil, nil, 50),
+			declared: models.BeneficialOwner{Forename: ptr("Jānis"), Surname: ptr("Bērziņš"), LatvianIdentityNumberMasked: ptr("010170-*****")},
+			want:     true,
+		},
+		{
+			name:     "different name",
+			owner:    naturalOwner("Anna Kalniņa", nil, nil, 50),
+			declared: models.BeneficialOwner{Forename: ptr("Jānis"), Surname: ptr("Bērziņš")},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameUBO(tt.owner, tt.declared); got != tt.want {
+				t.Errorf("sameUBO = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
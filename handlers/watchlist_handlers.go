@@ -0,0 +1,165 @@
+// handlers/watchlist_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetWatchlist godoc
+// @Summary Список отслеживаемых компаний
+// @Description Компании, об изменениях которых после импорта отправляются вебхуки (см. /webhooks).
+// @Tags watchlist
+// @Produce json
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.WatchlistEntry} "Пагинированный список компаний"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /watchlist [get]
+func GetWatchlist(c *gin.Context) {
+	pagination := utils.GetPaginationParams(c)
+
+	var totalRecords int64
+	queryBuilder := db.DB.Model(&models.WatchlistEntry{})
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting watchlist: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	entries := []models.WatchlistEntry{}
+	if err := queryBuilder.Order("id").Limit(pagination.Limit).Offset(pagination.Offset).Find(&entries).Error; err != nil {
+		log.Printf("Error finding watchlist with pagination: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         entries,
+	})
+}
+
+// AddToWatchlist godoc
+// @Summary Добавить компанию в watchlist
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param entry body models.WatchlistInput true "Regcode компании и комментарий"
+// @Success 201 {object} models.WatchlistEntry
+// @Failure 400 {object} HTTPError "Неверное тело запроса"
+// @Failure 404 {object} HTTPError "Компания не найдена"
+// @Failure 409 {object} HTTPError "Компания уже в watchlist"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /watchlist [post]
+func AddToWatchlist(c *gin.Context) {
+	var input models.WatchlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	regcode := strings.TrimSpace(input.Regcode)
+	if regcode == "" {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("regcode не может быть пустым")))
+		return
+	}
+
+	var companies int64
+	if err := db.DB.Model(&models.Registers{}).Where("regcode = ?", regcode).Count(&companies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	if companies == 0 {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компания с таким regcode не найдена")))
+		return
+	}
+
+	var existing int64
+	if err := db.DB.Model(&models.WatchlistEntry{}).Where("regcode = ?", regcode).Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, NewHTTPError(fmt.Errorf("компания %s уже в watchlist", regcode)))
+		return
+	}
+
+	entry := models.WatchlistEntry{Regcode: regcode, Note: input.Note}
+	if err := db.DB.Create(&entry).Error; err != nil {
+		log.Printf("Error adding %s to watchlist: %v", regcode, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// UpdateWatchlistEntry godoc
+// @Summary Изменить комментарий к компании в watchlist
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Param entry body models.WatchlistInput true "Новый комментарий (regcode игнорируется)"
+// @Success 200 {object} models.WatchlistEntry
+// @Failure 400 {object} HTTPError "Неверное тело запроса"
+// @Failure 404 {object} HTTPError "Компании нет в watchlist"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /watchlist/{regcode} [put]
+func UpdateWatchlistEntry(c *gin.Context) {
+	var entry models.WatchlistEntry
+	if err := db.DB.Where("regcode = ?", c.Param("regcode")).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компании нет в watchlist")))
+		} else {
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		}
+		return
+	}
+
+	var input models.WatchlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	entry.Note = input.Note
+	if err := db.DB.Save(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// RemoveFromWatchlist godoc
+// @Summary Убрать компанию из watchlist
+// @Tags watchlist
+// @Produce json
+// @Param regcode path string true "Regcode компании"
+// @Success 200 {object} map[string]string "Сообщение об успехе"
+// @Failure 404 {object} HTTPError "Компании нет в watchlist"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /watchlist/{regcode} [delete]
+func RemoveFromWatchlist(c *gin.Context) {
+	result := db.DB.Where("regcode = ?", c.Param("regcode")).Delete(&models.WatchlistEntry{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, NewHTTPError(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, NewHTTPError(errors.New("компании нет в watchlist")))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Компания удалена из watchlist"})
+}
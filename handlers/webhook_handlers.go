@@ -0,0 +1,270 @@
+// handlers/webhook_handlers.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"capital-view-api/db"
+	"capital-view-api/models"
+	"capital-view-api/utils"
+	"capital-view-api/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateWebhook godoc
+// @Summary Создать подписку на вебхуки
+// @Description После каждого импорта на url отправляется POST по каждой компании из watchlist, у которой есть изменения нужных типов (beneficial_owner.added, beneficial_owner.removed, member.added, member.removed, member.shares_changed, financial_statement.added, company.terminated). Заголовок X-Webhook-Signature содержит "sha256=" + HMAC-SHA256 от "<X-Webhook-Timestamp>.<тело>" с секретом подписки. Неудачные доставки повторяются с удвоением паузы (30s, 1m, 2m, ...), всего до 6 попыток. Секрет возвращается только в этом ответе.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param subscription body models.WebhookSubscriptionInput true "url обязателен; без secret он будет сгенерирован"
+// @Success 201 {object} models.WebhookSubscriptionWithSecret
+// @Failure 400 {object} HTTPError "Неверный url или тип события"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /webhooks [post]
+func CreateWebhook(c *gin.Context) {
+	var input models.WebhookSubscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	if input.URL == nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("url обязателен")))
+		return
+	}
+
+	subscription := models.WebhookSubscription{Active: true}
+	if err := applyWebhookInput(&subscription, input); err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	if subscription.Secret == "" {
+		secret, err := webhooks.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+			return
+		}
+		subscription.Secret = secret
+	}
+
+	if err := db.DB.Create(&subscription).Error; err != nil {
+		log.Printf("Error creating webhook subscription: %v", err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	c.JSON(http.StatusCreated, models.WebhookSubscriptionWithSecret{WebhookSubscription: subscription, Secret: subscription.Secret})
+}
+
+// GetWebhooks godoc
+// @Summary Список подписок на вебхуки
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.WebhookSubscription
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	subscriptions := []models.WebhookSubscription{}
+	if err := db.DB.Order("id").Find(&subscriptions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// GetWebhook godoc
+// @Summary Подписка на вебхуки по ID
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} HTTPError "Неверный ID"
+// @Failure 404 {object} HTTPError "Подписка не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /webhooks/{id} [get]
+func GetWebhook(c *gin.Context) {
+	subscription, ok := findWebhook(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// UpdateWebhook godoc
+// @Summary Изменить подписку на вебхуки
+// @Description Меняются только переданные поля. event_types: [] - все типы событий.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param subscription body models.WebhookSubscriptionInput true "Новые значения полей"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} HTTPError "Неверный ID, url или тип события"
+// @Failure 404 {object} HTTPError "Подписка не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /webhooks/{id} [put]
+func UpdateWebhook(c *gin.Context) {
+	subscription, ok := findWebhook(c)
+	if !ok {
+		return
+	}
+
+	var input models.WebhookSubscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+	if err := applyWebhookInput(&subscription, input); err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(err))
+		return
+	}
+
+	if err := db.DB.Save(&subscription).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteWebhook godoc
+// @Summary Удалить подписку на вебхуки
+// @Description Удаляет подписку вместе с журналом ее доставок.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {object} map[string]string "Сообщение об успехе"
+// @Failure 400 {object} HTTPError "Неверный ID"
+// @Failure 404 {object} HTTPError "Подписка не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /webhooks/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	subscription, ok := findWebhook(c)
+	if !ok {
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", subscription.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&subscription).Error
+	})
+	if err != nil {
+		log.Printf("Error deleting webhook subscription %d: %v", subscription.ID, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Подписка удалена"})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Журнал доставок подписки
+// @Description Уведомления подписки с результатом последней попытки, от новых к старым. Доставки в статусе pending повторяются сервером в next_attempt_at.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param status query string false "Статус доставки" Enums(pending, delivered, failed)
+// @Param page query int false "Номер страницы" default(1) minimum(1)
+// @Param limit query int false "Записей на странице" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.PaginatedResponse{data=[]models.WebhookDelivery} "Пагинированный журнал доставок"
+// @Failure 400 {object} HTTPError "Неверный ID или статус"
+// @Failure 404 {object} HTTPError "Подписка не найдена"
+// @Failure 500 {object} HTTPError "Внутренняя ошибка сервера"
+// @Router /webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	subscription, ok := findWebhook(c)
+	if !ok {
+		return
+	}
+
+	queryBuilder := db.DB.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscription.ID)
+	if status := strings.TrimSpace(c.Query("status")); status != "" {
+		switch status {
+		case models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+			queryBuilder = queryBuilder.Where("status = ?", status)
+		default:
+			c.JSON(http.StatusBadRequest, NewHTTPError(fmt.Errorf("неверный status '%s': ожидается pending, delivered или failed", status)))
+			return
+		}
+	}
+
+	pagination := utils.GetPaginationParams(c)
+	var totalRecords int64
+	if err := queryBuilder.Count(&totalRecords).Error; err != nil {
+		log.Printf("Error counting deliveries of webhook %d: %v", subscription.ID, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	if err := queryBuilder.Order("id DESC").Limit(pagination.Limit).Offset(pagination.Offset).Find(&deliveries).Error; err != nil {
+		log.Printf("Error finding deliveries of webhook %d with pagination: %v", subscription.ID, err)
+		c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		TotalRecords: totalRecords,
+		Page:         pagination.Page,
+		Limit:        pagination.Limit,
+		Data:         deliveries,
+	})
+}
+
+// findWebhook загружает подписку по :id и сам отвечает ошибкой, если не удалось
+func findWebhook(c *gin.Context) (models.WebhookSubscription, bool) {
+	var subscription models.WebhookSubscription
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewHTTPError(errors.New("id должен быть положительным числом")))
+		return subscription, false
+	}
+	if err := db.DB.First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, NewHTTPError(errors.New("подписка с таким id не найдена")))
+		} else {
+			c.JSON(http.StatusInternalServerError, NewHTTPError(err))
+		}
+		return subscription, false
+	}
+	return subscription, true
+}
+
+// applyWebhookInput переносит переданные поля в подписку с проверкой url и типов событий
+func applyWebhookInput(subscription *models.WebhookSubscription, input models.WebhookSubscriptionInput) error {
+	if input.URL != nil {
+		parsed, err := url.Parse(strings.TrimSpace(*input.URL))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("url должен быть абсолютным http(s) адресом: '%s'", *input.URL)
+		}
+		subscription.URL = parsed.String()
+	}
+	if input.Secret != nil {
+		if strings.TrimSpace(*input.Secret) == "" {
+			return errors.New("secret не может быть пустым (не передавайте его, чтобы сгенерировать)")
+		}
+		subscription.Secret = strings.TrimSpace(*input.Secret)
+	}
+	if input.EventTypes != nil {
+		eventTypes, err := webhooks.ParseEventTypes(strings.Join(input.EventTypes, ","))
+		if err != nil {
+			return err
+		}
+		subscription.EventTypes = nil
+		if len(eventTypes) > 0 {
+			joined := strings.Join(eventTypes, ",")
+			subscription.EventTypes = &joined
+		}
+	}
+	if input.Active != nil {
+		subscription.Active = *input.Active
+	}
+	return nil
+}
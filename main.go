@@ -4,6 +4,9 @@ import (
 	"capital-view-api/db"       // Adjust import path if needed
 	_ "capital-view-api/docs"   // Adjust import path (important for swag init)
 	"capital-view-api/handlers" // Adjust import path if needed
+	"capital-view-api/risk"
+	"capital-view-api/webhooks"
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -48,11 +51,16 @@ func main() {
 		"CREATE INDEX IF NOT EXISTS idx_registers_name ON registers (name)",                                     // <-- Для LIKE
 		"CREATE INDEX IF NOT EXISTS idx_registers_name_in_quotes ON registers (name_in_quotes)",                 // <-- Для LIKE
 		"CREATE INDEX IF NOT EXISTS idx_registers_without_quotes ON registers (without_quotes)",                 // <-- Для LIKE
-		"CREATE INDEX IF NOT EXISTS idx_members_regcode ON members (legal_entity_registration_number)",          // <-- Для Preload
+		"CREATE INDEX IF NOT EXISTS idx_registers_addressid ON registers (addressid)",                           // <-- Для /addresses
+		"CREATE INDEX IF NOT EXISTS idx_members_company ON members (at_legal_entity_registration_number)",       // <-- Для Preload и /shareholders
+		"CREATE INDEX IF NOT EXISTS idx_members_regcode ON members (legal_entity_registration_number)",          // <-- Для /holdings
 		"CREATE INDEX IF NOT EXISTS idx_members_name ON members (name)",                                         // <-- Для LIKE
 		"CREATE INDEX IF NOT EXISTS idx_owners_regcode ON beneficial_owners (legal_entity_registration_number)", // <-- Для Preload
 		"CREATE INDEX IF NOT EXISTS idx_owners_forename ON beneficial_owners (forename)",                        // <-- Для LIKE
 		"CREATE INDEX IF NOT EXISTS idx_owners_surname ON beneficial_owners (surname)",                          // <-- Для LIKE
+		"CREATE INDEX IF NOT EXISTS idx_officers_regcode ON officers (at_legal_entity_registration_number)",     // <-- Для Preload
+		"CREATE INDEX IF NOT EXISTS idx_officers_name ON officers (name)",                                       // <-- Для LIKE
+		"CREATE INDEX IF NOT EXISTS idx_fs_year ON financial_statements (year)",                                 // <-- Для скринера
 		// Индекс для financial_statements.legal_entity_registration_number уже покрыт уникальным составным
 	}
 	for _, cmd := range indexCommands {
@@ -61,6 +69,48 @@ func main() {
 		}
 	}
 	log.Println("Indexes checked/created.")
+
+	// Полнотекстовый поиск (FTS5) в SQLite; в PostgreSQL - LIKE с индексами pg_trgm.
+	if db.IsPostgres(db.DB) {
+		if err := db.EnsureTrigramIndexes(db.DB); err != nil {
+			log.Printf("WARN: Failed to create pg_trgm indexes, LIKE search will be slow: %v", err)
+		} else {
+			log.Println("Trigram search indexes are ready.")
+		}
+	} else if err := db.EnsureRegistersFTS(db.DB); err != nil {
+		log.Printf("WARN: FTS5 full-text search is not available, falling back to LIKE search (build with -tags sqlite_fts5): %v", err)
+	} else {
+		log.Println("Full-text search index is ready.")
+	}
+
+	// Предрасчитанные показатели для сравнения с аналогами (/company/:regcode/benchmark)
+	if err := db.EnsureCompanyMetrics(db.DB); err != nil {
+		log.Printf("WARN: Failed to prepare company_metrics, benchmark will not work: %v", err)
+	}
+
+	// Журнал изменений и версии записей, которые пишет импортер (/changes, /company/:regcode/history, ?as_of=)
+	if err := db.EnsureChangeLog(db.DB); err != nil {
+		log.Printf("WARN: Failed to prepare change_events/entity_versions, change history and as_of will not work: %v", err)
+	}
+
+	// Watchlist и вебхуки: повторные попытки доставки выполняет фоновый обработчик
+	if err := db.EnsureWebhooks(db.DB); err != nil {
+		log.Printf("WARN: Failed to prepare webhook tables, webhooks will not work: %v", err)
+	} else {
+		go webhooks.RunWorker(context.Background(), db.DB, webhooks.WorkerInterval)
+	}
+
+	// Оценка риска компаний: правила из RISK_RULES_PATH (risk_rules.json), пересчитывает импортер
+	if rules, err := risk.LoadRules(risk.RulesPath()); err != nil {
+		log.Printf("WARN: Failed to load risk rules, risk flags will not be computed on startup: %v", err)
+	} else if err := db.EnsureCompanyRisks(db.DB, rules); err != nil {
+		log.Printf("WARN: Failed to prepare company_risks, /company/:regcode/risk will not work: %v", err)
+	}
+
+	// Списки санкций/PEP и совпадения с ними (заполняет импортер)
+	if err := db.EnsureScreening(db.DB); err != nil {
+		log.Printf("WARN: Failed to prepare screening tables, /company/:regcode/screening will not work: %v", err)
+	}
 	// ---------------------------------------------------------
 
 	// Initialize Gin router
@@ -71,6 +121,19 @@ func main() {
 	{
 		// --- !!! ДОБАВИТЬ РОУТ ДЛЯ ПОЛНОЙ ИНФОРМАЦИИ О КОМПАНИИ !!! ---
 		v1.GET("/company/:regcode", handlers.GetCompanyDetailsByRegcode)
+		v1.GET("/company/:regcode/ratios", handlers.GetCompanyRatios)
+		v1.GET("/company/:regcode/timeseries", handlers.GetCompanyTimeSeries)
+		v1.GET("/company/:regcode/benchmark", handlers.GetCompanyBenchmark)
+		v1.GET("/company/:regcode/ownership-tree", handlers.GetOwnershipTree)
+		v1.GET("/company/:regcode/subsidiaries", handlers.GetSubsidiaries)
+		v1.GET("/company/:regcode/effective-owners", handlers.GetEffectiveOwners)
+		v1.GET("/company/:regcode/ubo-analysis", handlers.GetUBOAnalysis)
+		v1.GET("/company/:regcode/screening", handlers.GetCompanyScreening)
+		v1.GET("/company/:regcode/risk", handlers.GetCompanyRisk)
+		v1.GET("/company/:regcode/graph", handlers.ExportCompanyGraph)
+		v1.GET("/company/:regcode/history", handlers.GetCompanyHistory)
+		v1.GET("/company/:regcode/shareholders", handlers.GetCompanyShareholders)
+		v1.GET("/company/:regcode/holdings", handlers.GetCompanyHoldings)
 		// ---------------------------------------------------------------
 
 		// Register routes
@@ -78,6 +141,10 @@ func main() {
 		v1.GET("/register/:regcode", handlers.GetRegisterByID) // Этот остается для базовой инфы
 		// ... (закомментированные CRUD роуты) ...
 
+		// Address routes
+		v1.GET("/addresses/hotspots", handlers.GetAddressHotspots)
+		v1.GET("/addresses/:addressid/companies", handlers.GetAddressCompanies)
+
 		// Member routes
 		v1.GET("/members/by-regcode/:regcode", handlers.GetMembersByRegcode)
 		// ... (закомментированные CRUD роуты) ...
@@ -86,15 +153,41 @@ func main() {
 		v1.GET("/beneficial-owners/by-regcode/:regcode", handlers.GetBeneficialOwnersByRegcode)
 		// ... (закомментированные CRUD роуты) ...
 
+		// Officer routes
+		v1.GET("/officers/by-regcode/:regcode", handlers.GetOfficersByRegcode)
+
+		// Person routes: лица после сопоставления импортером
+		v1.GET("/persons/:id", handlers.GetPersonByID)
+
 		// Financial Statement routes
 		v1.GET("/financial-statements/by-regcode/:regcode", handlers.GetFinancialStatementsByRegcode)
 		// ... (закомментированные CRUD роуты) ...
 
+		// Screener: отбор компаний по фин. показателям за год
+		v1.GET("/screener", handlers.Screener)
+
+		// Лента изменений между запусками импортера
+		v1.GET("/changes", handlers.GetChanges)
+
+		// Watchlist и подписки на вебхуки об изменениях отслеживаемых компаний
+		v1.GET("/watchlist", handlers.GetWatchlist)
+		v1.POST("/watchlist", handlers.AddToWatchlist)
+		v1.PUT("/watchlist/:regcode", handlers.UpdateWatchlistEntry)
+		v1.DELETE("/watchlist/:regcode", handlers.RemoveFromWatchlist)
+		v1.GET("/webhooks", handlers.GetWebhooks)
+		v1.POST("/webhooks", handlers.CreateWebhook)
+		v1.GET("/webhooks/:id", handlers.GetWebhook)
+		v1.PUT("/webhooks/:id", handlers.UpdateWebhook)
+		v1.DELETE("/webhooks/:id", handlers.DeleteWebhook)
+		v1.GET("/webhooks/:id/deliveries", handlers.GetWebhookDeliveries)
+
 		// Search routes
 		searchGroup := v1.Group("/search")
 		{
 			// Этот роут теперь возвращает УПРОЩЕННЫЕ данные
 			searchGroup.GET("/detailed", handlers.DetailedSearch)
+			// Поиск физических лиц по members, beneficial_owners и officers
+			searchGroup.GET("/persons", handlers.SearchPersons)
 		}
 	} // конец v1
 
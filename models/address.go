@@ -0,0 +1,44 @@
+// models/address.go
+package models
+
+import "time"
+
+// AddressTypeCount - число действующих компаний одного типа по адресу
+type AddressTypeCount struct {
+	Type      *string `json:"type"`
+	TypeText  *string `json:"type_text"`
+	Companies int64   `json:"companies"`
+}
+
+// AddressSummary - сводка по адресу регистрации (Registers.Addressid).
+// Разбивка по типам и даты регистрации считаются по действующим компаниям.
+type AddressSummary struct {
+	Addressid          string             `json:"addressid"`
+	Address            *string            `json:"address"`
+	ActiveCompanies    int64              `json:"active_companies"` // Без terminated и closed
+	TotalCompanies     int64              `json:"total_companies"`  // Включая ликвидированные
+	ByType             []AddressTypeCount `json:"by_type"`          // По убыванию числа компаний
+	FirstRegistered    *time.Time         `json:"first_registered"`
+	LastRegistered     *time.Time         `json:"last_registered"`
+	SpreadDays         *int               `json:"spread_days"`          // Дней между первой и последней регистрацией
+	RegisteredLastYear int64              `json:"registered_last_year"` // Зарегистрировано за последние 365 дней
+}
+
+// AddressCompany - компания, зарегистрированная по адресу
+type AddressCompany struct {
+	Regcode    *string    `json:"regcode"`
+	Name       *string    `json:"name"`
+	Regtype    *string    `json:"regtype"`
+	Type       *string    `json:"type"`
+	TypeText   *string    `json:"type_text"`
+	Registered *time.Time `json:"registered"`
+	Terminated *time.Time `json:"terminated"`
+	Closed     *string    `json:"closed"`
+	Active     bool       `json:"active"`
+}
+
+// AddressCompanies - ответ /addresses/:addressid/companies: сводка по адресу и страница компаний
+type AddressCompanies struct {
+	Summary   AddressSummary    `json:"summary"`
+	Companies PaginatedResponse `json:"companies"`
+}
@@ -2,22 +2,22 @@ package models
 
 type BalanceSheet struct {
 	ID                           uint    `gorm:"primaryKey;autoIncrement" json:"id"`
-	StatementID                  *string `gorm:"uniqueIndex"` // <--- Добавить/обновить тег
+	StatementID                  *uint   `gorm:"uniqueIndex"` // <--- Добавить/обновить тег
 	FileID                       *string `json:"file_id,omitempty"`
-	Cash                         *string `json:"cash,omitempty"`
-	MarketableSecurities         *string `json:"marketable_securities,omitempty"`
-	AccountsReceivable           *string `json:"accounts_receivable,omitempty"`
-	Inventories                  *string `json:"inventories,omitempty"`
-	TotalCurrentAssets           *string `json:"total_current_assets,omitempty"`
-	Investments                  *string `json:"investments,omitempty"`
-	FixedAssets                  *string `json:"fixed_assets,omitempty"`
-	IntangibleAssets             *string `json:"intangible_assets,omitempty"`
-	TotalNonCurrentAssets        *string `json:"total_non_current_assets,omitempty"`
-	TotalAssets                  *string `json:"total_assets,omitempty"`
-	FutureHousingRepairsPayments *string `json:"future_housing_repairs_payments,omitempty"`
-	CurrentLiabilities           *string `json:"current_liabilities,omitempty"`
-	NonCurrentLiabilities        *string `json:"non_current_liabilities,omitempty"`
-	Provisions                   *string `json:"provisions,omitempty"`
-	Equity                       *string `json:"equity,omitempty"`
-	TotalEquities                *string `json:"total_equities,omitempty"`
+	Cash                         *int64  `json:"cash,omitempty"`
+	MarketableSecurities         *int64  `json:"marketable_securities,omitempty"`
+	AccountsReceivable           *int64  `json:"accounts_receivable,omitempty"`
+	Inventories                  *int64  `json:"inventories,omitempty"`
+	TotalCurrentAssets           *int64  `json:"total_current_assets,omitempty"`
+	Investments                  *int64  `json:"investments,omitempty"`
+	FixedAssets                  *int64  `json:"fixed_assets,omitempty"`
+	IntangibleAssets             *int64  `json:"intangible_assets,omitempty"`
+	TotalNonCurrentAssets        *int64  `json:"total_non_current_assets,omitempty"`
+	TotalAssets                  *int64  `json:"total_assets,omitempty"`
+	FutureHousingRepairsPayments *int64  `json:"future_housing_repairs_payments,omitempty"`
+	CurrentLiabilities           *int64  `json:"current_liabilities,omitempty"`
+	NonCurrentLiabilities        *int64  `json:"non_current_liabilities,omitempty"`
+	Provisions                   *int64  `json:"provisions,omitempty"`
+	Equity                       *int64  `json:"equity,omitempty"`
+	TotalEquities                *int64  `json:"total_equities,omitempty"`
 }
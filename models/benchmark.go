@@ -0,0 +1,33 @@
+// models/benchmark.go
+package models
+
+import "time"
+
+// BenchmarkPeerGroup - группа аналогов: компании за тот же год с теми же значениями признаков By
+type BenchmarkPeerGroup struct {
+	By        []string `json:"by"` // type | region | size
+	Type      *string  `json:"type,omitempty"`
+	Region    *string  `json:"region,omitempty"`
+	SizeBand  *string  `json:"size_band,omitempty" enums:"micro,small,medium,large"`
+	Companies int64    `json:"companies"` // Компаний в группе (включая саму компанию)
+}
+
+// BenchmarkMetric - положение показателя компании в группе аналогов
+type BenchmarkMetric struct {
+	Metric         string   `json:"metric"`
+	Value          *float64 `json:"value"`
+	PercentileRank *float64 `json:"percentile_rank"` // 0-100: доля аналогов ниже значения (равные считаются наполовину)
+	PeerCount      int64    `json:"peer_count"`      // Аналогов, у которых показатель посчитан
+	P25            *float64 `json:"p25"`
+	Median         *float64 `json:"median"`
+	P75            *float64 `json:"p75"`
+}
+
+// CompanyBenchmark - сравнение компании с группой аналогов за год
+type CompanyBenchmark struct {
+	Regcode     string             `json:"regcode"`
+	Year        int                `json:"year"`
+	PeerGroup   BenchmarkPeerGroup `json:"peer_group"`
+	Metrics     []BenchmarkMetric  `json:"metrics"`
+	RefreshedAt time.Time          `json:"refreshed_at"` // Когда показатели были пересчитаны (последний импорт)
+}
@@ -1,19 +1,35 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type BeneficialOwner struct {
 	ID uint `gorm:"primaryKey;autoIncrement" json:"id"`
 	// Индекс нужен для поиска по компании и для связи в Registers
 	LegalEntityRegistrationNumber *string `gorm:"index" json:"legal_entity_registration_number,omitempty"` // <-- Индекс для связи
 	Forename                      *string `gorm:"index" json:"forename,omitempty"`                         // <-- Индекс для поиска
 	Surname                       *string `gorm:"index" json:"surname,omitempty"`                          // <-- Индекс для поиска
+	NameFolded                    *string `gorm:"column:name_folded" json:"-"`                             // "Forename Surname" без диакритики (utils.FoldName) - для поиска
 	// ... остальные поля ...
-	LatvianIdentityNumberMasked *string `json:"latvian_identity_number_masked,omitempty"`
-	BirthDate                   *string `json:"birth_date,omitempty"`
-	Nationality                 *string `json:"nationality,omitempty"`
-	Residence                   *string `json:"residence,omitempty"`
-	RegisteredOn                *string `json:"registered_on,omitempty"`
-	LastModifiedAt              *string `json:"last_modified_at,omitempty"`
+	LatvianIdentityNumberMasked *string    `json:"latvian_identity_number_masked,omitempty"`
+	BirthDate                   *time.Time `json:"birth_date,omitempty"`
+	Nationality                 *string    `json:"nationality,omitempty"`
+	Residence                   *string    `json:"residence,omitempty"`
+	RegisteredOn                *time.Time `json:"registered_on,omitempty"`
+	LastModifiedAt              *time.Time `json:"last_modified_at,omitempty"`
+	// Лицо после сопоставления записей members/beneficial_owners/officers (заполняет импортер, см. persons)
+	PersonID         *uint    `gorm:"index" json:"person_id,omitempty"`
+	PersonConfidence *float64 `json:"person_confidence,omitempty"` // Уверенность сопоставления, 0-1
 	// Добавьте остальные поля из вашего CSV/модели, если они есть
 }
 
 // TableName() не нужен, GORM по умолчанию сделает "beneficial_owners"
+
+// BeforeSave заполняет name_folded при каждой записи (в т.ч. при upsert из импортера)
+func (o *BeneficialOwner) BeforeSave(tx *gorm.DB) error {
+	o.NameFolded = foldedName(o.Forename, o.Surname)
+	return nil
+}
@@ -0,0 +1,34 @@
+// models/change_event.go
+package models
+
+import "time"
+
+// Операции в журнале изменений
+const (
+	ChangeOperationInsert = "insert" // Новая запись
+	ChangeOperationUpdate = "update" // Изменилось поле (одно событие на поле)
+	ChangeOperationDelete = "delete" // Запись удалена
+)
+
+// ImportRun - один запуск cmd/importer
+type ImportRun struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CSVDir     string     `gorm:"column:csv_dir" json:"csv_dir"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Changes    int64      `json:"changes"` // Событий в change_events
+}
+
+// ChangeEvent - изменение записи, обнаруженное импортером по сравнению с предыдущим запуском
+type ChangeEvent struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ImportRunID uint      `gorm:"index" json:"import_run_id"`
+	Entity      string    `gorm:"index:idx_change_events_entity,priority:1" json:"entity"`    // Таблица: registers, members, beneficial_owners, ...
+	EntityID    string    `gorm:"index:idx_change_events_entity,priority:2" json:"entity_id"` // Ключ записи (значения колонок ON CONFLICT через "|")
+	Regcode     *string   `gorm:"index" json:"regcode,omitempty"`                             // Компания, к которой относится запись
+	Operation   string    `json:"operation" enums:"insert,update,delete"`
+	Field       *string   `json:"field,omitempty"` // Только для update
+	OldValue    *string   `json:"old_value,omitempty"`
+	NewValue    *string   `json:"new_value,omitempty"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,30 @@
+// models/company_metric.go
+package models
+
+import "time"
+
+// Размерные группы по числу сотрудников (FinancialStatement.Employees)
+const (
+	SizeBandMicro  = "micro"  // 0-9
+	SizeBandSmall  = "small"  // 10-49
+	SizeBandMedium = "medium" // 50-249
+	SizeBandLarge  = "large"  // 250+
+)
+
+// CompanyMetric - предрасчитанные показатели компании за год для сравнения с группой аналогов.
+// Таблица полностью пересчитывается импортером (db.RefreshCompanyMetrics), суммы в единицах евро.
+type CompanyMetric struct {
+	ID                 uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	Regcode            string    `gorm:"index:idx_company_metrics_regcode_year,priority:1" json:"regcode"`
+	Year               int       `gorm:"index:idx_company_metrics_regcode_year,priority:2;index:idx_company_metrics_year_type,priority:1;index:idx_company_metrics_year_region,priority:1;index:idx_company_metrics_year_size,priority:1" json:"year"`
+	StatementID        uint      `json:"statement_id"`
+	Type               *string   `gorm:"index:idx_company_metrics_year_type,priority:2" json:"type"`
+	Region             *string   `gorm:"index:idx_company_metrics_year_region,priority:2" json:"region"`
+	SizeBand           *string   `gorm:"index:idx_company_metrics_year_size,priority:2" json:"size_band"`
+	NetTurnover        *float64  `json:"net_turnover"`
+	NetMargin          *float64  `json:"net_margin"`
+	EquityRatio        *float64  `json:"equity_ratio"`
+	Employees          *float64  `json:"employees"` // float64, чтобы все показатели обрабатывались одинаково
+	RevenuePerEmployee *float64  `json:"revenue_per_employee"`
+	RefreshedAt        time.Time `json:"refreshed_at"`
+}
@@ -0,0 +1,18 @@
+// models/entity_version.go
+package models
+
+import "time"
+
+// EntityVersion - версия записи registers, members, beneficial_owners или officers с интервалом действия
+// [valid_from, valid_to). Импортер закрывает текущую версию и открывает новую, когда запись
+// меняется или исчезает из реестра; по версиям восстанавливается состояние на дату (?as_of=).
+type EntityVersion struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Entity      string     `gorm:"index:idx_entity_versions_entity,priority:1" json:"entity"`    // registers, members, beneficial_owners, officers
+	EntityID    string     `gorm:"index:idx_entity_versions_entity,priority:2" json:"entity_id"` // regcode для registers, id для остальных (как в change_events)
+	Regcode     *string    `gorm:"index" json:"regcode,omitempty"`                               // Компания, к которой относится запись
+	ValidFrom   *time.Time `gorm:"index" json:"valid_from,omitempty"`                            // nil - дата начала неизвестна
+	ValidTo     *time.Time `gorm:"index" json:"valid_to,omitempty"`                              // nil - действующая версия
+	ImportRunID uint       `json:"import_run_id"`
+	Data        string     `json:"-"` // Запись в JSON (модели Registers, Member, BeneficialOwner или Officer)
+}
@@ -1,20 +1,22 @@
 package models
 
+import "time"
+
 type FinancialStatement struct {
 	ID     uint    `gorm:"primaryKey;autoIncrement" json:"id"`
 	FileID *string `json:"file_id,omitempty"`
 	// Индекс нужен для поиска по компании и для связи в Registers
 	LegalEntityRegistrationNumber *string `gorm:"uniqueIndex:uq_fs_company_year"` // <-- Составной уникальный индекс
-	Year                          *string `gorm:"uniqueIndex:uq_fs_company_year"` // <-- Составной уникальный индекс
+	Year                          *int    `gorm:"uniqueIndex:uq_fs_company_year"` // <-- Составной уникальный индекс
 	// ... остальные поля ...
-	SourceSchema     *string `json:"source_schema,omitempty"`
-	SourceType       *string `json:"source_type,omitempty"`
-	YearStartedOn    *string `json:"year_started_on,omitempty"`
-	YearEndedOn      *string `json:"year_ended_on,omitempty"`
-	Employees        *string `json:"employees,omitempty"`
-	RoundedToNearest *string `json:"rounded_to_nearest,omitempty"`
-	Currency         *string `json:"currency,omitempty"`
-	CreatedAt        *string `json:"created_at,omitempty"`
+	SourceSchema     *string    `json:"source_schema,omitempty"`
+	SourceType       *string    `json:"source_type,omitempty"`
+	YearStartedOn    *time.Time `json:"year_started_on,omitempty"`
+	YearEndedOn      *time.Time `json:"year_ended_on,omitempty"`
+	Employees        *int       `json:"employees,omitempty"`
+	RoundedToNearest *string    `json:"rounded_to_nearest,omitempty"`
+	Currency         *string    `json:"currency,omitempty"`
+	CreatedAt        *time.Time `gorm:"autoCreateTime:false" json:"created_at,omitempty"` // Дата из CSV, GORM не должен её подставлять
 	// --- Связи (Один к одному/нулю с деталями) ---
 	// Указываем, что у Statement есть детали, связанные по ID этого Statement
 	// и колонке 'statement_id' в таблицах деталей.
@@ -0,0 +1,24 @@
+// models/folding.go
+package models
+
+import (
+	"strings"
+
+	"capital-view-api/utils"
+)
+
+// foldedName собирает значение для *_folded колонки из одной или нескольких частей имени.
+// Возвращает nil, если все части пустые, чтобы колонка оставалась NULL.
+func foldedName(parts ...*string) *string {
+	var values []string
+	for _, part := range parts {
+		if part != nil && *part != "" {
+			values = append(values, *part)
+		}
+	}
+	folded := utils.FoldName(strings.Join(values, " "))
+	if folded == "" {
+		return nil
+	}
+	return &folded
+}
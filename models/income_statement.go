@@ -2,32 +2,32 @@ package models
 
 type IncomeStatement struct {
 	ID                                 uint    `gorm:"primaryKey;autoIncrement" json:"id"`
-	StatementID                        *string `gorm:"uniqueIndex"` // <--- Добавить/обновить тег
+	StatementID                        *uint   `gorm:"uniqueIndex"` // <--- Добавить/обновить тег
 	FileID                             *string `json:"file_id,omitempty"`
-	NetTurnover                        *string `json:"net_turnover,omitempty"`
-	ByNatureInventoryChange            *string `json:"by_nature_inventory_change,omitempty"`
-	ByNatureLongTermInvestmentExpenses *string `json:"by_nature_long_term_investment_expenses,omitempty"`
-	ByNatureOtherOperatingRevenues     *string `json:"by_nature_other_operating_revenues,omitempty"`
-	ByNatureMaterialExpenses           *string `json:"by_nature_material_expenses,omitempty"`
-	ByNatureLabourExpenses             *string `json:"by_nature_labour_expenses,omitempty"`
-	ByNatureDepreciationExpenses       *string `json:"by_nature_depreciation_expenses,omitempty"`
-	ByFunctionCostOfGoodsSold          *string `json:"by_function_cost_of_goods_sold,omitempty"`
-	ByFunctionGrossProfit              *string `json:"by_function_gross_profit,omitempty"`
-	ByFunctionSellingExpenses          *string `json:"by_function_selling_expenses,omitempty"`
-	ByFunctionAdministrativeExpenses   *string `json:"by_function_administrative_expenses,omitempty"`
-	ByFunctionOtherOperatingRevenues   *string `json:"by_function_other_operating_revenues,omitempty"`
-	OtherOperatingExpenses             *string `json:"other_operating_expenses,omitempty"`
-	EquityInvestmentEarnings           *string `json:"equity_investment_earnings,omitempty"`
-	OtherLongTermInvestmentEarnings    *string `json:"other_long_term_investment_earnings,omitempty"`
-	OtherInterestRevenues              *string `json:"other_interest_revenues,omitempty"`
-	InvestmentFairValueAdjustments     *string `json:"investment_fair_value_adjustments,omitempty"`
-	InterestExpenses                   *string `json:"interest_expenses,omitempty"`
-	ExtraRevenues                      *string `json:"extra_revenues,omitempty"`
-	ExtraExpenses                      *string `json:"extra_expenses,omitempty"`
-	IncomeBeforeIncomeTaxes            *string `json:"income_before_income_taxes,omitempty"`
-	ProvisionForIncomeTaxes            *string `json:"provision_for_income_taxes,omitempty"`
-	IncomeAfterIncomeTaxes             *string `json:"income_after_income_taxes,omitempty"`
-	OtherTaxes                         *string `json:"other_taxes,omitempty"`
-	ExtraDividends                     *string `json:"extra_dividends,omitempty"`
-	NetIncome                          *string `json:"net_income,omitempty"`
+	NetTurnover                        *int64  `json:"net_turnover,omitempty"`
+	ByNatureInventoryChange            *int64  `json:"by_nature_inventory_change,omitempty"`
+	ByNatureLongTermInvestmentExpenses *int64  `json:"by_nature_long_term_investment_expenses,omitempty"`
+	ByNatureOtherOperatingRevenues     *int64  `json:"by_nature_other_operating_revenues,omitempty"`
+	ByNatureMaterialExpenses           *int64  `json:"by_nature_material_expenses,omitempty"`
+	ByNatureLabourExpenses             *int64  `json:"by_nature_labour_expenses,omitempty"`
+	ByNatureDepreciationExpenses       *int64  `json:"by_nature_depreciation_expenses,omitempty"`
+	ByFunctionCostOfGoodsSold          *int64  `json:"by_function_cost_of_goods_sold,omitempty"`
+	ByFunctionGrossProfit              *int64  `json:"by_function_gross_profit,omitempty"`
+	ByFunctionSellingExpenses          *int64  `json:"by_function_selling_expenses,omitempty"`
+	ByFunctionAdministrativeExpenses   *int64  `json:"by_function_administrative_expenses,omitempty"`
+	ByFunctionOtherOperatingRevenues   *int64  `json:"by_function_other_operating_revenues,omitempty"`
+	OtherOperatingExpenses             *int64  `json:"other_operating_expenses,omitempty"`
+	EquityInvestmentEarnings           *int64  `json:"equity_investment_earnings,omitempty"`
+	OtherLongTermInvestmentEarnings    *int64  `json:"other_long_term_investment_earnings,omitempty"`
+	OtherInterestRevenues              *int64  `json:"other_interest_revenues,omitempty"`
+	InvestmentFairValueAdjustments     *int64  `json:"investment_fair_value_adjustments,omitempty"`
+	InterestExpenses                   *int64  `json:"interest_expenses,omitempty"`
+	ExtraRevenues                      *int64  `json:"extra_revenues,omitempty"`
+	ExtraExpenses                      *int64  `json:"extra_expenses,omitempty"`
+	IncomeBeforeIncomeTaxes            *int64  `json:"income_before_income_taxes,omitempty"`
+	ProvisionForIncomeTaxes            *int64  `json:"provision_for_income_taxes,omitempty"`
+	IncomeAfterIncomeTaxes             *int64  `json:"income_after_income_taxes,omitempty"`
+	OtherTaxes                         *int64  `json:"other_taxes,omitempty"`
+	ExtraDividends                     *int64  `json:"extra_dividends,omitempty"`
+	NetIncome                          *int64  `json:"net_income,omitempty"`
 }
@@ -1,24 +1,45 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Member struct {
 	ID  uint    `gorm:"primaryKey;autoIncrement" json:"id"`
 	Uri *string `json:"uri,omitempty"`
-	// Индекс нужен для поиска по компании и для связи в Registers
+	// Компания, в которой участник держит доли (связь Registers.Members и Member.Company)
 	AtLegalEntityRegistrationNumber *string `gorm:"column:at_legal_entity_registration_number;index" json:"at_legal_entity_registration_number,omitempty"`
 	EntityType                      *string `json:"entity_type,omitempty"`
 	Name                            *string `gorm:"index" json:"name,omitempty"` // <-- Индекс для поиска по имени
-	// Если это поле = regcode компании, где лицо является участником, то нужен индекс
-	LegalEntityRegistrationNumber *string `gorm:"index" json:"legal_entity_registration_number,omitempty"` // <-- Индекс для связи
+	NameFolded                      *string `gorm:"column:name_folded" json:"-"` // Name без диакритики (utils.FoldName) - для поиска
+	// Regcode самого участника, если он - юр. лицо (связь Registers.Holdings); у физ. лиц пусто
+	LegalEntityRegistrationNumber *string `gorm:"index" json:"legal_entity_registration_number,omitempty"`
 	// ... остальные поля ...
-	LatvianIdentityNumberMasked *string `json:"latvian_identity_number_masked,omitempty"`
-	BirthDate                   *string `json:"birth_date,omitempty"`
-	NumberOfShares              *string `json:"number_of_shares,omitempty"`
-	ShareNominalValue           *string `json:"share_nominal_value,omitempty"`
-	ShareCurrency               *string `json:"share_currency,omitempty"`
-	DateFrom                    *string `json:"date_from,omitempty"`
-	RegisteredOn                *string `json:"registered_on,omitempty"`
-	LastModifiedAt              *string `json:"last_modified_at,omitempty"`
+	LatvianIdentityNumberMasked *string    `json:"latvian_identity_number_masked,omitempty"`
+	BirthDate                   *time.Time `json:"birth_date,omitempty"`
+	NumberOfShares              *float64   `json:"number_of_shares,omitempty"`
+	ShareNominalValue           *float64   `json:"share_nominal_value,omitempty"`
+	ShareCurrency               *string    `json:"share_currency,omitempty"`
+	DateFrom                    *time.Time `json:"date_from,omitempty"`
+	RegisteredOn                *time.Time `json:"registered_on,omitempty"`
+	LastModifiedAt              *time.Time `json:"last_modified_at,omitempty"`
+	// Лицо после сопоставления записей members/beneficial_owners/officers (заполняет импортер, см. persons)
+	PersonID         *uint    `gorm:"index" json:"person_id,omitempty"`
+	PersonConfidence *float64 `json:"person_confidence,omitempty"` // Уверенность сопоставления, 0-1
+	// Доля в уставном капитале компании, % (не хранится: считается по всем участникам компании)
+	OwnershipPercent *float64 `gorm:"-" json:"ownership_percent,omitempty"`
+	// Компания, в которой участник держит доли (загружается только через Preload("Company")).
+	// constraint:- - в members есть компании, которых нет в registers, внешний ключ не создаем
+	Company *Registers `gorm:"foreignKey:AtLegalEntityRegistrationNumber;references:Regcode;constraint:-" json:"company,omitempty"`
 	// Добавьте остальные поля из вашего CSV/модели, если они есть
 }
 
 // TableName() не нужен, GORM по умолчанию сделает "members"
+
+// BeforeSave заполняет name_folded при каждой записи (в т.ч. при upsert из импортера)
+func (m *Member) BeforeSave(tx *gorm.DB) error {
+	m.NameFolded = foldedName(m.Name)
+	return nil
+}
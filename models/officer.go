@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Officer struct {
+	ID  uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Uri *string `json:"uri,omitempty"`
+	// Regcode компании, в которой лицо занимает должность - по нему связь с Registers
+	AtLegalEntityRegistrationNumber *string    `gorm:"column:at_legal_entity_registration_number;index" json:"at_legal_entity_registration_number,omitempty"` // <-- Индекс для связи
+	EntityType                      *string    `json:"entity_type,omitempty"`
+	Position                        *string    `json:"position,omitempty"`          // BOARD_MEMBER, CHAIR_OF_BOARD, LIQUIDATOR, ...
+	GoverningBody                   *string    `json:"governing_body,omitempty"`    // EXECUTIVE_BOARD, COUNCIL, ...
+	Name                            *string    `gorm:"index" json:"name,omitempty"` // <-- Индекс для поиска по имени
+	NameFolded                      *string    `gorm:"column:name_folded" json:"-"` // Name без диакритики (utils.FoldName) - для поиска
+	LatvianIdentityNumberMasked     *string    `json:"latvian_identity_number_masked,omitempty"`
+	BirthDate                       *time.Time `json:"birth_date,omitempty"`
+	// Заполнено, только если должностное лицо само является юр. лицом
+	LegalEntityRegistrationNumber *string    `json:"legal_entity_registration_number,omitempty"`
+	RightsOfRepresentationType    *string    `json:"rights_of_representation_type,omitempty"` // INDIVIDUALLY, JOINTLY, ...
+	RepresentationWithAtLeast     *int       `json:"representation_with_at_least,omitempty"`  // Сколько ещё лиц нужно для совместной подписи
+	RegisteredOn                  *time.Time `json:"registered_on,omitempty"`
+	LastModifiedAt                *time.Time `json:"last_modified_at,omitempty"`
+	// Лицо после сопоставления записей members/beneficial_owners/officers (заполняет импортер, см. persons)
+	PersonID         *uint    `gorm:"index" json:"person_id,omitempty"`
+	PersonConfidence *float64 `json:"person_confidence,omitempty"` // Уверенность сопоставления, 0-1
+}
+
+// TableName() не нужен, GORM по умолчанию сделает "officers"
+
+// BeforeSave заполняет name_folded при каждой записи (в т.ч. при upsert из импортера)
+func (o *Officer) BeforeSave(tx *gorm.DB) error {
+	o.NameFolded = foldedName(o.Name)
+	return nil
+}
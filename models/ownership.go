@@ -0,0 +1,119 @@
+// models/ownership.go
+package models
+
+import (
+	"strings"
+	"time"
+
+	"capital-view-api/utils"
+)
+
+// Направления обхода графа владения
+const (
+	OwnershipDirectionOwners       = "owners"       // Вверх: участники компании, их участники и т.д.
+	OwnershipDirectionSubsidiaries = "subsidiaries" // Вниз: компании, где компания - участник, и т.д.
+)
+
+// OwnershipNode - участник (для owners) или дочерняя компания (для subsidiaries) в дереве владения.
+// Поля доли берутся из записи members, связывающей узел с родителем.
+type OwnershipNode struct {
+	Regcode           *string         `json:"regcode,omitempty"` // Regcode юр. лица; у физ. лиц пусто
+	Name              *string         `json:"name,omitempty"`
+	EntityType        *string         `json:"entity_type,omitempty"`
+	MemberID          uint            `json:"member_id"` // Запись members, которая задает связь
+	NumberOfShares    *float64        `json:"number_of_shares,omitempty"`
+	ShareNominalValue *float64        `json:"share_nominal_value,omitempty"`
+	ShareCurrency     *string         `json:"share_currency,omitempty"`
+	DateFrom          *time.Time      `json:"date_from,omitempty"`
+	OwnershipPercent  *float64        `json:"ownership_percent,omitempty"`   // Прямая доля в капитале дочерней компании связи, %
+	EffectivePercent  *float64        `json:"effective_percent,omitempty"`   // Произведение долей по пути от корня, %
+	Cycle             bool            `json:"cycle,omitempty"`               // Узел уже есть выше по пути - дальше не раскрывается
+	DepthLimitReached bool            `json:"depth_limit_reached,omitempty"` // У узла есть связи глубже, чем depth
+	Children          []OwnershipNode `json:"children,omitempty"`
+}
+
+// OwnershipTree - дерево владения компании на заданную глубину
+type OwnershipTree struct {
+	Regcode   string          `json:"regcode"`
+	Name      *string         `json:"name,omitempty"`
+	Direction string          `json:"direction" enums:"owners,subsidiaries"`
+	Depth     int             `json:"depth"`
+	Nodes     int             `json:"nodes"`     // Узлов в дереве (без корня)
+	Truncated bool            `json:"truncated"` // Дерево обрезано по лимиту узлов
+	Children  []OwnershipNode `json:"children"`
+	Cycles    [][]string      `json:"cycles"` // Найденные циклы: regcode по пути, первый = последний
+}
+
+// EffectiveOwner - конечный владелец компании и его косвенная доля по всем цепочкам владения
+type EffectiveOwner struct {
+	Regcode                     *string  `json:"regcode,omitempty"` // Для юр. лиц
+	Name                        *string  `json:"name,omitempty"`
+	EntityType                  *string  `json:"entity_type,omitempty"`
+	LatvianIdentityNumberMasked *string  `json:"latvian_identity_number_masked,omitempty"`
+	PersonID                    *uint    `json:"person_id,omitempty"` // Для физ. лиц, сопоставленных импортером
+	EffectivePercent            *float64 `json:"effective_percent"`   // Сумма по цепочкам произведений долей, %
+	Paths                       int      `json:"paths"`               // Число цепочек до компании
+	Incomplete                  bool     `json:"incomplete"`          // В какой-то цепочке доля неизвестна или обход остановлен по depth
+}
+
+// EffectiveOwnership - конечные владельцы компании
+type EffectiveOwnership struct {
+	Regcode   string           `json:"regcode"`
+	Name      *string          `json:"name,omitempty"`
+	Depth     int              `json:"depth"`
+	Truncated bool             `json:"truncated"` // Обход обрезан по лимиту узлов
+	Owners    []EffectiveOwner `json:"owners"`
+}
+
+// MemberOwnershipPercents считает долю каждого участника в уставном капитале его компании:
+// NumberOfShares * ShareNominalValue / сумма по всем участникам компании * 100.
+// members должны содержать ВСЕХ участников каждой компании. Если валюты участников одной компании
+// различаются, капитал пересчитывается в EUR (LVL по фиксированному курсу). Доля не считается (nil)
+// для всей компании, если у кого-то нет числа акций или номинала, или валюту нельзя пересчитать.
+func MemberOwnershipPercents(members []Member) map[uint]*float64 {
+	byCompany := make(map[string][]Member)
+	for _, member := range members {
+		company := strings.TrimSpace(utils.StringValue(member.AtLegalEntityRegistrationNumber))
+		if company != "" {
+			byCompany[company] = append(byCompany[company], member)
+		}
+	}
+
+	percents := make(map[uint]*float64, len(members))
+	for _, companyMembers := range byCompany {
+		currencies := make(map[string]bool)
+		for _, member := range companyMembers {
+			currencies[strings.ToUpper(strings.TrimSpace(utils.StringValue(member.ShareCurrency)))] = true
+		}
+		convert := len(currencies) > 1
+
+		capitals := make([]float64, len(companyMembers))
+		var total float64
+		known := true
+		for i, member := range companyMembers {
+			if member.NumberOfShares == nil || member.ShareNominalValue == nil {
+				known = false
+				break
+			}
+			capital := *member.NumberOfShares * *member.ShareNominalValue
+			if convert {
+				rate, ok := utils.EURRate(member.ShareCurrency)
+				if !ok {
+					known = false
+					break
+				}
+				capital *= rate
+			}
+			capitals[i] = capital
+			total += capital
+		}
+		if !known || total <= 0 {
+			continue
+		}
+		for i, member := range companyMembers {
+			percent := capitals[i] / total * 100
+			percents[member.ID] = &percent
+		}
+	}
+	return percents
+}
@@ -0,0 +1,29 @@
+// models/person.go
+package models
+
+import "time"
+
+// Person - физическое лицо, объединяющее записи members, beneficial_owners и officers.
+// Заполняется импортером (сопоставление по началу персонального кода, имени и дате рождения);
+// ID стабилен между запусками, пока не меняется MatchKey.
+type Person struct {
+	ID                          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	MatchKey                    string     `gorm:"uniqueIndex" json:"-"` // "090859|didzis kadaks" или "|didzis kadaks" (только имя)
+	Name                        string     `json:"name"`
+	LatvianIdentityNumberMasked *string    `json:"latvian_identity_number_masked,omitempty"`
+	BirthDate                   *time.Time `json:"birth_date,omitempty"`
+	Confidence                  float64    `json:"confidence"` // Минимальная уверенность среди записей лица, 0-1
+	Records                     int        `json:"records"`    // Сколько записей сопоставлено с лицом
+	UpdatedAt                   time.Time  `json:"updated_at"`
+}
+
+// TableName - GORM по умолчанию назвал бы таблицу "people"
+func (Person) TableName() string {
+	return "persons"
+}
+
+// PersonProfile - лицо и все его роли в компаниях
+type PersonProfile struct {
+	Person    Person              `json:"person"`
+	Companies []PersonCompanyRole `json:"companies"`
+}
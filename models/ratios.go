@@ -0,0 +1,37 @@
+// models/ratios.go
+package models
+
+// Статусы коэффициента: почему значение не посчитано
+const (
+	RatioStatusOK                  = "ok"
+	RatioStatusMissingData         = "missing_data"         // Нет числителя или знаменателя в отчете
+	RatioStatusZeroDenominator     = "zero_denominator"     // Знаменатель равен 0
+	RatioStatusNegativeDenominator = "negative_denominator" // Отрицательный капитал/выручка - коэффициент не имеет смысла
+)
+
+// RatioValue - значение коэффициента. Value = nil, если Status != "ok".
+type RatioValue struct {
+	Value  *float64 `json:"value"`
+	Status string   `json:"status" enums:"ok,missing_data,zero_denominator,negative_denominator"`
+}
+
+// FinancialRatios - коэффициенты за один год
+type FinancialRatios struct {
+	CurrentRatio              RatioValue `json:"current_ratio"`                // TotalCurrentAssets / CurrentLiabilities
+	DebtToEquity              RatioValue `json:"debt_to_equity"`               // (CurrentLiabilities + NonCurrentLiabilities) / Equity
+	ReturnOnAssets            RatioValue `json:"return_on_assets"`             // NetIncome / TotalAssets
+	ReturnOnEquity            RatioValue `json:"return_on_equity"`             // NetIncome / Equity
+	NetMargin                 RatioValue `json:"net_margin"`                   // NetIncome / NetTurnover
+	GrossMargin               RatioValue `json:"gross_margin"`                 // ByFunctionGrossProfit / NetTurnover
+	OperatingCashFlowCoverage RatioValue `json:"operating_cash_flow_coverage"` // Чистый операционный денежный поток / CurrentLiabilities
+}
+
+// CompanyRatiosYear - коэффициенты компании за год вместе с единицами исходного отчета
+type CompanyRatiosYear struct {
+	Year             *int            `json:"year"`
+	StatementID      uint            `json:"statement_id"`
+	Currency         *string         `json:"currency,omitempty"`
+	RoundedToNearest *string         `json:"rounded_to_nearest,omitempty"`
+	UnitsKnown       bool            `json:"units_known"` // false, если RoundedToNearest не распознан (коэффициенты все равно корректны)
+	Ratios           FinancialRatios `json:"ratios"`
+}
@@ -1,11 +1,18 @@
 // models/registers.go
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Registers struct {
 	ID                 uint    `gorm:"primaryKey;autoIncrement"`
 	Regcode            *string `gorm:"uniqueIndex"` // Уникальный индекс уже есть
 	Sepa               *string
-	Name               *string `gorm:"index"` // <-- Добавим индекс для поиска по имени
+	Name               *string `gorm:"index"`                       // <-- Добавим индекс для поиска по имени
+	NameFolded         *string `gorm:"column:name_folded" json:"-"` // Name без диакритики в нижнем регистре (utils.FoldName) - для поиска
 	NameBeforeQuotes   *string `gorm:"column:name_before_quotes"`
 	NameInQuotes       *string `gorm:"column:name_in_quotes;index"` // <-- Добавим индекс
 	NameAfterQuotes    *string `gorm:"column:name_after_quotes"`
@@ -14,8 +21,8 @@ type Registers struct {
 	RegtypeText        *string `gorm:"column:regtype_text"`
 	Type               *string
 	TypeText           *string `gorm:"column:type_text"`
-	Registered         *string
-	Terminated         *string
+	Registered         *time.Time
+	Terminated         *time.Time
 	Closed             *string
 	Address            *string
 	IndexCompany       *string `gorm:"column:index_company"`
@@ -24,19 +31,34 @@ type Registers struct {
 	City               *string
 	Atvk               *string
 	ReregistrationTerm *string `gorm:"column:reregistration_term"`
-	Latitude           *string
-	Longitude          *string
+	Latitude           *float64
+	Longitude          *float64
 
 	// --- Связи ---
-	// Указываем, что у одной записи Registers может быть много записей Member,
-	// связанных по колонке 'legal_entity_registration_number' в таблице members,
-	// которая соответствует колонке 'regcode' в таблице registers.
-	Members             []Member             `gorm:"foreignKey:LegalEntityRegistrationNumber;references:Regcode"`
+	// Участники компании: записи members, где 'at_legal_entity_registration_number' = regcode
+	// (кто держит доли компании). constraint:- - внешний ключ в БД не создаем: в members есть
+	// компании, которых нет в registers.
+	Members []Member `gorm:"foreignKey:AtLegalEntityRegistrationNumber;references:Regcode;constraint:-"`
+	// Доли компании в других компаниях: записи members, где компания сама участник
+	// ('legal_entity_registration_number' = regcode). В детали компании не загружается.
+	Holdings            []Member             `gorm:"foreignKey:LegalEntityRegistrationNumber;references:Regcode;constraint:-" json:"Holdings,omitempty"`
 	BeneficialOwners    []BeneficialOwner    `gorm:"foreignKey:LegalEntityRegistrationNumber;references:Regcode"`
 	FinancialStatements []FinancialStatement `gorm:"foreignKey:LegalEntityRegistrationNumber;references:Regcode"`
+	// Должностные лица связаны по 'at_legal_entity_registration_number' (компания, где лицо занимает должность)
+	Officers []Officer `gorm:"foreignKey:AtLegalEntityRegistrationNumber;references:Regcode"`
 }
 
+// ActiveRegisterCondition - SQL-условие "действующая компания": не ликвидирована и не закрыта.
+// Общее для фильтра status=active, рейтинга адресов и правил риска, чтобы они считали одинаково.
+const ActiveRegisterCondition = "registers.terminated IS NULL AND (registers.closed IS NULL OR registers.closed = '')"
+
 // Метод TableName оставляем, чтобы гарантировать имя "registers"
 func (Registers) TableName() string {
 	return "registers"
 }
+
+// BeforeSave заполняет name_folded при каждой записи (в т.ч. при upsert из импортера)
+func (r *Registers) BeforeSave(tx *gorm.DB) error {
+	r.NameFolded = foldedName(r.Name)
+	return nil
+}
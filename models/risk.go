@@ -0,0 +1,55 @@
+// models/risk.go
+package models
+
+import "time"
+
+// Правила оценки риска (CompanyRiskFlag.Rule)
+const (
+	RiskRuleNegativeEquity            = "negative_equity"
+	RiskRuleZeroAssetsWithLiabilities = "zero_assets_with_liabilities"
+	RiskRuleNoRecentStatements        = "no_recent_statements"
+	RiskRuleFrequentOwnerChanges      = "frequent_beneficial_owner_changes"
+	RiskRuleSharedAddress             = "shared_address"
+	RiskRuleCircularOwnership         = "circular_ownership"
+)
+
+// Уровни риска по сумме весов сработавших правил
+const (
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+)
+
+// CompanyRisk - итог оценки компании. Строки есть только у компаний хотя бы с одним флагом;
+// таблица полностью пересчитывается импортером (risk.Refresh).
+type CompanyRisk struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	Regcode     string    `gorm:"uniqueIndex" json:"regcode"`
+	Score       int       `gorm:"index" json:"score"` // Сумма весов флагов, не больше 100
+	Level       string    `gorm:"index" json:"level" enums:"low,medium,high"`
+	Flags       int       `json:"flags"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+}
+
+// CompanyRiskFlag - сработавшее правило с объяснением
+type CompanyRiskFlag struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"-"`
+	Regcode     string    `gorm:"index" json:"-"`
+	Rule        string    `gorm:"index" json:"rule" enums:"negative_equity,zero_assets_with_liabilities,no_recent_statements,frequent_beneficial_owner_changes,shared_address,circular_ownership"`
+	Severity    string    `json:"severity" enums:"low,medium,high"`
+	Weight      int       `json:"weight"`
+	Message     string    `json:"message"`         // Объяснение на русском
+	Value       *float64  `json:"value,omitempty"` // Значение, по которому сработало правило (капитал, число компаний и т.п.)
+	Year        *int      `json:"year,omitempty"`  // Год отчета, если правило по отчетности
+	EvaluatedAt time.Time `json:"evaluated_at"`
+}
+
+// CompanyRiskReport - ответ /company/:regcode/risk
+type CompanyRiskReport struct {
+	Regcode     string            `json:"regcode"`
+	Name        *string           `json:"name,omitempty"`
+	Score       int               `json:"score"`
+	Level       string            `json:"level" enums:"low,medium,high"`
+	EvaluatedAt *time.Time        `json:"evaluated_at,omitempty"` // nil - оценка еще не выполнялась
+	Flags       []CompanyRiskFlag `json:"flags"`
+}
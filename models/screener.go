@@ -0,0 +1,12 @@
+// models/screener.go
+package models
+
+// ScreenerResult - компания, прошедшая условия скринера, и значения показателей за выбранный год
+type ScreenerResult struct {
+	Regcode     string              `json:"regcode"`
+	Name        *string             `json:"name"`
+	Type        *string             `json:"type,omitempty"`
+	Year        int                 `json:"year"`
+	StatementID uint                `json:"statement_id"`
+	Values      map[string]*float64 `json:"values"` // Поля из условий, сортировки и fields; суммы в единицах евро
+}
@@ -0,0 +1,73 @@
+// models/screening.go
+package models
+
+import "time"
+
+// Категории списков для скрининга
+const (
+	ScreeningCategorySanctions = "sanctions"
+	ScreeningCategoryPEP       = "pep" // Политически значимые лица
+)
+
+// Типы записей списка
+const (
+	ScreeningEntryPerson = "person"
+	ScreeningEntryEntity = "entity"
+)
+
+// ScreeningList - список санкций или PEP, загруженный из файла (load-screening-lists)
+type ScreeningList struct {
+	ID       uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name     string    `gorm:"uniqueIndex" json:"name"` // Имя файла: повторная загрузка файла заменяет список
+	Category string    `json:"category" enums:"sanctions,pep"`
+	Format   string    `json:"format" enums:"csv,un-xml,eu-xml,ofac-xml"`
+	Entries  int       `json:"entries"`
+	LoadedAt time.Time `json:"loaded_at"`
+	// Последний пакетный скрининг реестра по этому списку; nil - еще не выполнялся
+	ScreenedAt *time.Time `json:"screened_at,omitempty"`
+}
+
+// ScreeningEntry - лицо или организация из списка. Несколько значений в полях - через "; "
+type ScreeningEntry struct {
+	ID         uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListID     uint    `gorm:"index" json:"list_id"`
+	ExternalID *string `json:"external_id,omitempty"` // Идентификатор записи в исходном списке
+	EntryType  *string `json:"entry_type,omitempty" enums:"person,entity"`
+	Name       string  `json:"name"`
+	Aliases    *string `json:"aliases,omitempty"`
+	BirthDates *string `json:"birth_dates,omitempty"` // Как в списке: дата или только год
+	Countries  *string `json:"countries,omitempty"`
+	Programs   *string `json:"programs,omitempty"` // Санкционные режимы или должности PEP
+	Remarks    *string `json:"remarks,omitempty"`
+}
+
+// ScreeningMatch - совпадение записи реестра с записью списка (результат пакетного скрининга)
+type ScreeningMatch struct {
+	ID           uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ImportRunID  *uint   `json:"import_run_id,omitempty"` // Запуск импорта, после которого выполнен скрининг
+	EntryID      uint    `gorm:"index" json:"entry_id"`
+	ListID       uint    `gorm:"index" json:"list_id"`
+	ListName     string  `json:"list_name"`
+	Category     string  `json:"category" enums:"sanctions,pep"`
+	Regcode      string  `gorm:"index" json:"regcode"` // Компания, к которой относится запись реестра
+	Entity       string  `json:"entity" enums:"registers,members,beneficial_owners,officers"`
+	RecordID     uint    `json:"record_id"`
+	MatchedField string  `json:"matched_field"` // Колонка записи реестра, по которой найдено совпадение
+	MatchedValue string  `json:"matched_value"`
+	MatchedName  string  `json:"matched_name"` // Имя или псевдоним из списка, с которым совпало значение
+	Score        float64 `json:"score"`        // Сходство имен, 0-1
+	// Год рождения из списка совпал с датой рождения (или кодом) лица; nil - сравнить нечем
+	BirthDateMatch *bool           `json:"birth_date_match,omitempty"`
+	ScreenedAt     time.Time       `json:"screened_at"`
+	Entry          *ScreeningEntry `gorm:"foreignKey:EntryID;constraint:-" json:"entry,omitempty"`
+}
+
+// CompanyScreening - совпадения компании, ее участников, бенефициаров и должностных лиц со списками
+type CompanyScreening struct {
+	Regcode    string           `json:"regcode"`
+	Name       *string          `json:"name,omitempty"`
+	MinScore   float64          `json:"min_score"`
+	ScreenedAt *time.Time       `json:"screened_at,omitempty"` // Последний пакетный скрининг; nil - еще не выполнялся
+	Lists      []ScreeningList  `json:"lists"`                 // Загруженные списки, по которым проверялись записи
+	Matches    []ScreeningMatch `json:"matches"`
+}
@@ -1,6 +1,8 @@
 // models/search_result.go (ИЛИ models/models.go)
 package models
 
+import "time"
+
 // SimpleRegisterInfo содержит минимальный набор полей для списка результатов поиска
 type SimpleRegisterInfo struct {
 	Regcode     *string `json:"Regcode,omitempty"`
@@ -9,3 +11,25 @@ type SimpleRegisterInfo struct {
 	Address     *string `json:"Address,omitempty"`
 	TypeText    *string `json:"TypeText,omitempty"`
 }
+
+// PersonSearchResult - физическое лицо, найденное в members, beneficial_owners и officers,
+// со всеми компаниями, с которыми оно связано
+type PersonSearchResult struct {
+	PersonID                    *uint               `json:"person_id,omitempty"` // См. /persons/{id}
+	Name                        string              `json:"name"`
+	LatvianIdentityNumberMasked *string             `json:"latvian_identity_number_masked,omitempty"`
+	BirthDate                   *time.Time          `json:"birth_date,omitempty"`
+	Companies                   []PersonCompanyRole `json:"companies"`
+}
+
+// PersonCompanyRole - роль лица в конкретной компании
+type PersonCompanyRole struct {
+	Regcode        string     `json:"regcode"`
+	CompanyName    *string    `json:"company_name,omitempty"`
+	Role           string     `json:"role" enums:"member,beneficial_owner,officer"` // member - участник (дольщик), beneficial_owner - бенефициар, officer - должностное лицо
+	Position       *string    `json:"position,omitempty"`                           // Только для officer
+	GoverningBody  *string    `json:"governing_body,omitempty"`                     // Только для officer
+	NumberOfShares *float64   `json:"number_of_shares,omitempty"`                   // Только для member
+	Since          *time.Time `json:"since,omitempty"`                              // date_from / registered_on
+	Confidence     *float64   `json:"match_confidence,omitempty"`                   // Уверенность сопоставления записи с лицом
+}
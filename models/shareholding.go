@@ -0,0 +1,40 @@
+// models/shareholding.go
+package models
+
+import "time"
+
+// Holding - доля компании в другой компании: запись members, где компания - участник
+// (legal_entity_registration_number), вместе с реквизитами компании, чьи доли она держит
+type Holding struct {
+	MemberID          uint       `json:"member_id"`         // Запись members, которая задает долю
+	Regcode           *string    `json:"regcode,omitempty"` // Компания, в которой держится доля (at_legal_entity_registration_number)
+	Name              *string    `json:"name,omitempty"`    // Из registers; пусто, если компании нет в реестре
+	RegtypeText       *string    `json:"regtype_text,omitempty"`
+	Terminated        *time.Time `json:"terminated,omitempty"`
+	NumberOfShares    *float64   `json:"number_of_shares,omitempty"`
+	ShareNominalValue *float64   `json:"share_nominal_value,omitempty"`
+	ShareCurrency     *string    `json:"share_currency,omitempty"`
+	DateFrom          *time.Time `json:"date_from,omitempty"`
+	RegisteredOn      *time.Time `json:"registered_on,omitempty"`
+	OwnershipPercent  *float64   `json:"ownership_percent,omitempty"` // Доля в уставном капитале компании, %
+}
+
+// NewHolding собирает Holding из записи members с загруженной связью Company
+func NewHolding(member Member) Holding {
+	holding := Holding{
+		MemberID:          member.ID,
+		Regcode:           member.AtLegalEntityRegistrationNumber,
+		NumberOfShares:    member.NumberOfShares,
+		ShareNominalValue: member.ShareNominalValue,
+		ShareCurrency:     member.ShareCurrency,
+		DateFrom:          member.DateFrom,
+		RegisteredOn:      member.RegisteredOn,
+		OwnershipPercent:  member.OwnershipPercent,
+	}
+	if member.Company != nil {
+		holding.Name = member.Company.Name
+		holding.RegtypeText = member.Company.RegtypeText
+		holding.Terminated = member.Company.Terminated
+	}
+	return holding
+}
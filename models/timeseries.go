@@ -0,0 +1,28 @@
+// models/timeseries.go
+package models
+
+// TimeSeriesPoint - значение строки отчета за год и изменение к предыдущему году
+type TimeSeriesPoint struct {
+	Year      int      `json:"year"`
+	Value     *float64 `json:"value"`
+	Change    *float64 `json:"change"`     // Абсолютное изменение к году year-1 (null, если одного из значений нет или у отчетов разные валюта/единицы)
+	ChangePct *float64 `json:"change_pct"` // Изменение в % к |значению за year-1| по суммам в евро (null, если предыдущее 0 или нет)
+}
+
+// TimeSeries - ряд одного поля по всем годам отчетности компании
+type TimeSeries struct {
+	Field    string            `json:"field"`
+	Source   string            `json:"source" enums:"financial_statement,income_statement,balance_sheet,cash_flow_statement"`
+	Points   []TimeSeriesPoint `json:"points"`
+	CAGRPct  *float64          `json:"cagr_pct"`            // Среднегодовой темп роста в % между первым и последним значением ряда (по суммам в евро)
+	CAGRFrom *int              `json:"cagr_from,omitempty"` // Год первого значения, по которому считался CAGR
+	CAGRTo   *int              `json:"cagr_to,omitempty"`   // Год последнего значения
+}
+
+// CompanyTimeSeries - выровненные по годам ряды для компании
+type CompanyTimeSeries struct {
+	Regcode    string       `json:"regcode"`
+	Years      []int        `json:"years"`      // Общая ось лет для всех рядов
+	Normalized bool         `json:"normalized"` // true - суммы приведены к единицам евро (?normalize=eur)
+	Series     []TimeSeries `json:"series"`
+}
@@ -0,0 +1,60 @@
+// models/ubo.go
+package models
+
+// UBOThresholdPercent - косвенная доля, начиная с которой физ. лицо считается вероятным
+// бенефициаром (25% и больше)
+const UBOThresholdPercent = 25.0
+
+// Виды расхождений между цепочкой участников и заявленными бенефициарами
+const (
+	UBODiscrepancyUndeclared    = "undeclared_owner"           // Лицо с долей >= порога не заявлено бенефициаром
+	UBODiscrepancyNoOwnership   = "declared_without_ownership" // Заявленный бенефициар не найден в цепочке участников
+	UBODiscrepancyNoDeclaration = "no_declaration"             // У компании нет ни одного заявленного бенефициара
+	UBODiscrepancyUnresolved    = "unresolved_owner"           // Юр. лицо с долей >= порога, чьих участников нет в данных
+)
+
+// DerivedUBO - физ. лицо, которое по цепочке участников владеет долей >= порога
+type DerivedUBO struct {
+	Name                        *string  `json:"name,omitempty"`
+	LatvianIdentityNumberMasked *string  `json:"latvian_identity_number_masked,omitempty"`
+	PersonID                    *uint    `json:"person_id,omitempty"`
+	EffectivePercent            *float64 `json:"effective_percent"`
+	Paths                       int      `json:"paths"`
+	Incomplete                  bool     `json:"incomplete"`                    // В какой-то цепочке доля неизвестна или обход остановлен по depth
+	Declared                    bool     `json:"declared"`                      // Есть среди заявленных бенефициаров
+	BeneficialOwnerID           *uint    `json:"beneficial_owner_id,omitempty"` // Запись beneficial_owners, с которой сопоставлено лицо
+}
+
+// DeclaredUBO - заявленный бенефициар и его доля, видимая по цепочке участников
+type DeclaredUBO struct {
+	BeneficialOwnerID           uint     `json:"beneficial_owner_id"`
+	Forename                    *string  `json:"forename,omitempty"`
+	Surname                     *string  `json:"surname,omitempty"`
+	LatvianIdentityNumberMasked *string  `json:"latvian_identity_number_masked,omitempty"`
+	PersonID                    *uint    `json:"person_id,omitempty"`
+	OwnershipFound              bool     `json:"ownership_found"`             // Лицо есть среди конечных владельцев
+	EffectivePercent            *float64 `json:"effective_percent,omitempty"` // Косвенная доля, если найдена (может быть ниже порога)
+}
+
+// UBODiscrepancy - одно расхождение отчета
+type UBODiscrepancy struct {
+	Type                        string   `json:"type" enums:"undeclared_owner,declared_without_ownership,no_declaration,unresolved_owner"`
+	Message                     string   `json:"message"`
+	Regcode                     *string  `json:"regcode,omitempty"` // Компания (no_declaration) или юр. лицо-владелец (unresolved_owner)
+	Name                        *string  `json:"name,omitempty"`
+	LatvianIdentityNumberMasked *string  `json:"latvian_identity_number_masked,omitempty"`
+	EffectivePercent            *float64 `json:"effective_percent,omitempty"`
+	BeneficialOwnerID           *uint    `json:"beneficial_owner_id,omitempty"`
+}
+
+// UBOAnalysis - вероятные бенефициары по цепочке участников в сравнении с заявленными
+type UBOAnalysis struct {
+	Regcode          string           `json:"regcode"`
+	Name             *string          `json:"name,omitempty"`
+	Depth            int              `json:"depth"`
+	ThresholdPercent float64          `json:"threshold_percent"`
+	Truncated        bool             `json:"truncated"` // Обход обрезан по лимиту узлов - результат может быть неполным
+	Derived          []DerivedUBO     `json:"derived"`
+	Declared         []DeclaredUBO    `json:"declared"`
+	Discrepancies    []UBODiscrepancy `json:"discrepancies"`
+}
@@ -0,0 +1,104 @@
+// models/webhook.go
+package models
+
+import "time"
+
+// Типы событий, о которых сообщают вебхуки (по change_events импортера)
+const (
+	WebhookEventBeneficialOwnerAdded   = "beneficial_owner.added"
+	WebhookEventBeneficialOwnerRemoved = "beneficial_owner.removed"
+	WebhookEventMemberAdded            = "member.added"
+	WebhookEventMemberRemoved          = "member.removed"
+	WebhookEventMemberSharesChanged    = "member.shares_changed"
+	WebhookEventFinancialStatementNew  = "financial_statement.added"
+	WebhookEventCompanyTerminated      = "company.terminated"
+)
+
+// Статусы доставки вебхука
+const (
+	WebhookDeliveryPending   = "pending"   // Ждет первой или повторной попытки (next_attempt_at)
+	WebhookDeliveryDelivered = "delivered" // Получатель ответил 2xx
+	WebhookDeliveryFailed    = "failed"    // Попытки исчерпаны
+)
+
+// WatchlistEntry - компания, за изменениями которой следят подписчики вебхуков
+type WatchlistEntry struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Regcode   string    `gorm:"uniqueIndex" json:"regcode"`
+	Note      *string   `json:"note,omitempty"` // Комментарий (зачем компания добавлена)
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName - "watchlist" вместо "watchlist_entries"
+func (WatchlistEntry) TableName() string {
+	return "watchlist"
+}
+
+// WebhookSubscription - адрес, на который отправляются изменения компаний из watchlist
+type WebhookSubscription struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"`                     // Ключ HMAC-SHA256 подписи (X-Webhook-Signature), показывается только при создании
+	EventTypes *string   `json:"event_types,omitempty"` // Типы событий через запятую; пусто - все
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery - одно уведомление (компания + запуск импорта) для подписки и история попыток его отправки
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID uint       `gorm:"index" json:"subscription_id"`
+	ImportRunID    uint       `gorm:"index" json:"import_run_id"`
+	Regcode        string     `json:"regcode"`
+	EventTypes     string     `json:"event_types"` // Типы событий в payload через запятую
+	Payload        string     `json:"payload"`     // Тело POST-запроса (JSON)
+	Status         string     `gorm:"index" json:"status" enums:"pending,delivered,failed"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookPayload - тело уведомления
+type WebhookPayload struct {
+	ImportRunID uint                 `json:"import_run_id"`
+	Regcode     string               `json:"regcode"`
+	CompanyName *string              `json:"company_name,omitempty"`
+	Events      []WebhookPayloadItem `json:"events"`
+}
+
+// WebhookPayloadItem - одно изменение в уведомлении
+type WebhookPayloadItem struct {
+	Type     string  `json:"type"`
+	ChangeID uint    `json:"change_id"` // ID в change_events (см. /changes)
+	Entity   string  `json:"entity"`
+	EntityID string  `json:"entity_id"`
+	Field    *string `json:"field,omitempty"`
+	OldValue *string `json:"old_value,omitempty"`
+	NewValue *string `json:"new_value,omitempty"`
+}
+
+// WatchlistInput - тело запросов POST /watchlist и PUT /watchlist/{regcode}
+type WatchlistInput struct {
+	Regcode string  `json:"regcode"` // Только для POST
+	Note    *string `json:"note"`
+}
+
+// WebhookSubscriptionInput - тело запросов POST и PUT /webhooks. В PUT меняются только переданные поля.
+type WebhookSubscriptionInput struct {
+	URL        *string  `json:"url"`
+	Secret     *string  `json:"secret"`      // Если не задан при создании, генерируется
+	EventTypes []string `json:"event_types"` // Пусто - все типы
+	Active     *bool    `json:"active"`      // По умолчанию true
+}
+
+// WebhookSubscriptionWithSecret - ответ на создание подписки: единственный раз, когда возвращается секрет
+type WebhookSubscriptionWithSecret struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,434 @@
+// risk/engine.go
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+// batchSize - размер пачки при записи результатов
+const batchSize = 1000
+
+// maxCycleRegcodes - сколько regcode цикла перечислять в сообщении
+const maxCycleRegcodes = 10
+
+// evaluation - флаги, собранные правилами, по regcode
+type evaluation struct {
+	rules       Rules
+	evaluatedAt time.Time
+	flags       map[string][]models.CompanyRiskFlag
+}
+
+func (e *evaluation) add(regcode string, rule Rule, name, message string, value *float64, year *int) {
+	if regcode == "" {
+		return
+	}
+	e.flags[regcode] = append(e.flags[regcode], models.CompanyRiskFlag{
+		Regcode:     regcode,
+		Rule:        name,
+		Severity:    rule.Severity,
+		Weight:      rule.Weight,
+		Message:     message,
+		Value:       value,
+		Year:        year,
+		EvaluatedAt: e.evaluatedAt,
+	})
+}
+
+// Refresh оценивает все компании по правилам rules и заменяет таблицы company_risks и
+// company_risk_flags. Возвращает число компаний хотя бы с одним флагом.
+func Refresh(db *gorm.DB, rules Rules) (int, error) {
+	e := &evaluation{rules: rules, evaluatedAt: time.Now(), flags: make(map[string][]models.CompanyRiskFlag)}
+	steps := []struct {
+		rule Rule
+		run  func(*gorm.DB, *evaluation) error
+	}{
+		{rules.NegativeEquity, negativeEquity},
+		{rules.ZeroAssetsWithLiabilities, zeroAssetsWithLiabilities},
+		{rules.NoRecentStatements, noRecentStatements},
+		{rules.FrequentOwnerChanges, frequentOwnerChanges},
+		{rules.SharedAddress, sharedAddress},
+		{rules.CircularOwnership, circularOwnership},
+	}
+	for _, step := range steps {
+		if !step.rule.Enabled {
+			continue
+		}
+		if err := step.run(db, e); err != nil {
+			return 0, err
+		}
+	}
+
+	var risks []models.CompanyRisk
+	var flags []models.CompanyRiskFlag
+	regcodes := make([]string, 0, len(e.flags))
+	for regcode := range e.flags {
+		regcodes = append(regcodes, regcode)
+	}
+	sort.Strings(regcodes)
+	for _, regcode := range regcodes {
+		companyFlags := e.flags[regcode]
+		score := 0
+		for _, flag := range companyFlags {
+			score += flag.Weight
+		}
+		if score > 100 {
+			score = 100
+		}
+		risks = append(risks, models.CompanyRisk{
+			Regcode:     regcode,
+			Score:       score,
+			Level:       rules.level(score),
+			Flags:       len(companyFlags),
+			EvaluatedAt: e.evaluatedAt,
+		})
+		flags = append(flags, companyFlags...)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.CompanyRiskFlag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1 = 1").Delete(&models.CompanyRisk{}).Error; err != nil {
+			return err
+		}
+		if len(risks) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(risks, batchSize).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(flags, batchSize).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(risks), nil
+}
+
+// latestBalanceSheet - баланс последнего года, за который он есть
+type latestBalanceSheet struct {
+	Regcode               string
+	Year                  int
+	Currency              *string
+	TotalAssets           *int64
+	CurrentLiabilities    *int64
+	NonCurrentLiabilities *int64
+	Provisions            *int64
+	Equity                *int64
+}
+
+func loadLatestBalanceSheets(db *gorm.DB) ([]latestBalanceSheet, error) {
+	var rows []latestBalanceSheet
+	err := db.Raw(`
+		SELECT fs.legal_entity_registration_number AS regcode, fs.year, fs.currency,
+			bs.total_assets, bs.current_liabilities, bs.non_current_liabilities, bs.provisions, bs.equity
+		FROM financial_statements fs
+		JOIN balance_sheets bs ON bs.statement_id = fs.id
+		WHERE fs.year = (
+			SELECT MAX(f2.year) FROM financial_statements f2
+			JOIN balance_sheets b2 ON b2.statement_id = f2.id
+			WHERE f2.legal_entity_registration_number = fs.legal_entity_registration_number
+		)`).Scan(&rows).Error
+	return rows, err
+}
+
+// negativeEquity - отрицательный собственный капитал в последнем балансе
+func negativeEquity(db *gorm.DB, e *evaluation) error {
+	sheets, err := loadLatestBalanceSheets(db)
+	if err != nil {
+		return err
+	}
+	for _, sheet := range sheets {
+		if sheet.Equity == nil || *sheet.Equity >= 0 {
+			continue
+		}
+		value, year := float64(*sheet.Equity), sheet.Year
+		e.add(sheet.Regcode, e.rules.NegativeEquity, models.RiskRuleNegativeEquity,
+			fmt.Sprintf("Собственный капитал (Equity) отрицательный: %d%s в балансе за %d год", *sheet.Equity, currency(sheet.Currency), sheet.Year),
+			&value, &year)
+	}
+	return nil
+}
+
+// zeroAssetsWithLiabilities - активы 0 при ненулевых обязательствах в последнем балансе
+func zeroAssetsWithLiabilities(db *gorm.DB, e *evaluation) error {
+	sheets, err := loadLatestBalanceSheets(db)
+	if err != nil {
+		return err
+	}
+	for _, sheet := range sheets {
+		if sheet.TotalAssets == nil || *sheet.TotalAssets != 0 {
+			continue
+		}
+		liabilities := int64Value(sheet.CurrentLiabilities) + int64Value(sheet.NonCurrentLiabilities) + int64Value(sheet.Provisions)
+		if liabilities == 0 {
+			continue
+		}
+		value, year := float64(liabilities), sheet.Year
+		e.add(sheet.Regcode, e.rules.ZeroAssetsWithLiabilities, models.RiskRuleZeroAssetsWithLiabilities,
+			fmt.Sprintf("Активы (TotalAssets) равны 0, а обязательства и резервы - %d%s в балансе за %d год", liabilities, currency(sheet.Currency), sheet.Year),
+			&value, &year)
+	}
+	return nil
+}
+
+// noRecentStatements - действующая компания (models.ActiveRegisterCondition) без отчетов за последние rule.Years лет
+// (компании, зарегистрированные позже начала этого периода, не проверяются)
+func noRecentStatements(db *gorm.DB, e *evaluation) error {
+	rule := e.rules.NoRecentStatements
+	years := rule.Years
+	if years < 1 {
+		years = 1
+	}
+	fromYear := e.evaluatedAt.Year() - years
+	periodStart := time.Date(fromYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var rows []struct {
+		Regcode    string
+		LatestYear *int
+	}
+	query := db.Table("registers").
+		Select("registers.regcode, (SELECT MAX(fs.year) FROM financial_statements fs WHERE fs.legal_entity_registration_number = registers.regcode) AS latest_year").
+		Where(models.ActiveRegisterCondition).
+		Where("registers.registered IS NULL OR registers.registered < ?", periodStart).
+		Where("NOT EXISTS (SELECT 1 FROM financial_statements fs WHERE fs.legal_entity_registration_number = registers.regcode AND fs.year >= ?)", fromYear)
+	if len(rule.RegTypes) > 0 {
+		query = query.Where("registers.regtype IN ?", rule.RegTypes)
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		message := fmt.Sprintf("Компания действует, но отчетов за %d-%d годы нет; отчетов нет совсем", fromYear, e.evaluatedAt.Year())
+		var value *float64
+		if row.LatestYear != nil {
+			message = fmt.Sprintf("Компания действует, но отчетов за %d-%d годы нет; последний отчет - за %d год", fromYear, e.evaluatedAt.Year(), *row.LatestYear)
+			latest := float64(*row.LatestYear)
+			value = &latest
+		}
+		e.add(row.Regcode, rule, models.RiskRuleNoRecentStatements, message, value, row.LatestYear)
+	}
+	return nil
+}
+
+// frequentOwnerChanges - не меньше rule.MaxChanges добавлений и удалений бенефициаров за rule.WindowDays дней:
+// по журналу изменений импортера и по датам регистрации бенефициаров (берется большее)
+func frequentOwnerChanges(db *gorm.DB, e *evaluation) error {
+	rule := e.rules.FrequentOwnerChanges
+	since := e.evaluatedAt.AddDate(0, 0, -rule.WindowDays)
+
+	var events, registrations []struct {
+		Regcode string
+		Changes int
+	}
+	err := db.Model(&models.ChangeEvent{}).
+		Select("regcode, COUNT(*) AS changes").
+		Where("entity = ? AND operation IN ? AND created_at >= ? AND regcode IS NOT NULL", "beneficial_owners",
+			[]string{models.ChangeOperationInsert, models.ChangeOperationDelete}, since).
+		Group("regcode").Scan(&events).Error
+	if err != nil {
+		return err
+	}
+	err = db.Model(&models.BeneficialOwner{}).
+		Select("legal_entity_registration_number AS regcode, COUNT(*) AS changes").
+		Where("registered_on >= ? AND legal_entity_registration_number IS NOT NULL", since).
+		Group("legal_entity_registration_number").Scan(&registrations).Error
+	if err != nil {
+		return err
+	}
+
+	changes := make(map[string]int)
+	for _, rows := range [][]struct {
+		Regcode string
+		Changes int
+	}{events, registrations} {
+		for _, row := range rows {
+			if row.Changes > changes[row.Regcode] {
+				changes[row.Regcode] = row.Changes
+			}
+		}
+	}
+	for regcode, count := range changes {
+		if count < rule.MaxChanges {
+			continue
+		}
+		value := float64(count)
+		e.add(regcode, rule, models.RiskRuleFrequentOwnerChanges,
+			fmt.Sprintf("Бенефициары менялись %d раз за последние %d дней (порог %d)", count, rule.WindowDays, rule.MaxChanges),
+			&value, nil)
+	}
+	return nil
+}
+
+// sharedAddress - по адресу компании (addressid) зарегистрировано больше rule.MaxCompanies других
+// действующих компаний. Адреса и действующие компании считаются так же, как в /addresses/hotspots.
+func sharedAddress(db *gorm.DB, e *evaluation) error {
+	rule := e.rules.SharedAddress
+	var rows []struct {
+		Regcode string
+		Address string
+		Others  int
+	}
+	err := db.Raw(`
+		SELECT registers.regcode, COALESCE(registers.address, '') AS address, shared.companies - 1 AS others
+		FROM registers
+		JOIN (
+			SELECT registers.addressid, COUNT(*) AS companies FROM registers
+			WHERE `+models.ActiveRegisterCondition+` AND registers.addressid IS NOT NULL AND registers.addressid <> ''
+			GROUP BY registers.addressid HAVING COUNT(*) > ?
+		) shared ON shared.addressid = registers.addressid
+		WHERE `+models.ActiveRegisterCondition, rule.MaxCompanies+1).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		value := float64(row.Others)
+		e.add(row.Regcode, rule, models.RiskRuleSharedAddress,
+			fmt.Sprintf("По адресу \"%s\" зарегистрировано еще %d действующих компаний (порог %d)", row.Address, row.Others, rule.MaxCompanies),
+			&value, nil)
+	}
+	return nil
+}
+
+// circularOwnership - компания входит в цикл владения (через участников-юр. лиц владеет сама собой).
+// Циклы ищутся как сильно связные компоненты графа "участник -> компания" (алгоритм Тарьяна).
+func circularOwnership(db *gorm.DB, e *evaluation) error {
+	var edges []struct {
+		Owner   string
+		Company string
+	}
+	err := db.Model(&models.Member{}).
+		Select("legal_entity_registration_number AS owner, at_legal_entity_registration_number AS company").
+		Where("legal_entity_registration_number IS NOT NULL AND legal_entity_registration_number <> ''").
+		Where("at_legal_entity_registration_number IS NOT NULL AND at_legal_entity_registration_number <> ''").
+		Scan(&edges).Error
+	if err != nil {
+		return err
+	}
+	graph := make(map[string][]string)
+	selfOwned := make(map[string]bool)
+	for _, edge := range edges {
+		owner, company := strings.TrimSpace(edge.Owner), strings.TrimSpace(edge.Company)
+		graph[owner] = append(graph[owner], company)
+		if owner == company {
+			selfOwned[owner] = true
+		}
+	}
+
+	for _, component := range stronglyConnected(graph) {
+		if len(component) == 1 && !selfOwned[component[0]] {
+			continue
+		}
+		sort.Strings(component)
+		listed := component
+		if len(listed) > maxCycleRegcodes {
+			listed = listed[:maxCycleRegcodes]
+		}
+		message := fmt.Sprintf("Компания входит в цикл владения из %d компаний: %s", len(component), strings.Join(listed, ", "))
+		if len(listed) < len(component) {
+			message += ", ..."
+		}
+		if len(component) == 1 {
+			message = "Компания указана участником самой себя"
+		}
+		value := float64(len(component))
+		for _, regcode := range component {
+			e.add(regcode, e.rules.CircularOwnership, models.RiskRuleCircularOwnership, message, &value, nil)
+		}
+	}
+	return nil
+}
+
+// stronglyConnected - сильно связные компоненты графа (итеративный алгоритм Тарьяна)
+func stronglyConnected(graph map[string][]string) [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	next := 0
+
+	nodes := make([]string, 0, len(graph))
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	type frame struct {
+		node string
+		edge int
+	}
+	for _, root := range nodes {
+		if _, seen := index[root]; seen {
+			continue
+		}
+		callStack := []frame{{node: root}}
+		index[root], lowLink[root] = next, next
+		next++
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(callStack) > 0 {
+			top := &callStack[len(callStack)-1]
+			if top.edge < len(graph[top.node]) {
+				neighbor := graph[top.node][top.edge]
+				top.edge++
+				if _, seen := index[neighbor]; !seen {
+					index[neighbor], lowLink[neighbor] = next, next
+					next++
+					stack = append(stack, neighbor)
+					onStack[neighbor] = true
+					callStack = append(callStack, frame{node: neighbor})
+				} else if onStack[neighbor] && index[neighbor] < lowLink[top.node] {
+					lowLink[top.node] = index[neighbor]
+				}
+				continue
+			}
+
+			node := top.node
+			callStack = callStack[:len(callStack)-1]
+			if len(callStack) > 0 {
+				parent := callStack[len(callStack)-1].node
+				if lowLink[node] < lowLink[parent] {
+					lowLink[parent] = lowLink[node]
+				}
+			}
+			if lowLink[node] == index[node] {
+				var component []string
+				for {
+					last := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[last] = false
+					component = append(component, last)
+					if last == node {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+		}
+	}
+	return components
+}
+
+// currency - " EUR" для подстановки после суммы ("" - валюта не указана)
+func currency(value *string) string {
+	if value == nil || *value == "" {
+		return ""
+	}
+	return " " + *value
+}
+
+func int64Value(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,343 @@
+// risk/engine_test.go
+package risk
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"capital-view-api/db/postgrestest"
+	"capital-view-api/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testNow - время оценки во всех тестах правил
+var testNow = time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// connectTestSQLite - временная база SQLite с таблицами, которые читают правила
+// (те же настройки gorm, что в db.ConnectDatabase; сам пакет db импортирует risk)
+func connectTestSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		Logger:                                   logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	err = database.AutoMigrate(&models.Registers{}, &models.Member{}, &models.BeneficialOwner{}, &models.Officer{},
+		&models.FinancialStatement{}, &models.BalanceSheet{}, &models.ChangeEvent{})
+	if err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	return database
+}
+
+func create(t *testing.T, database *gorm.DB, records ...interface{}) {
+	t.Helper()
+	for _, record := range records {
+		if err := database.Create(record).Error; err != nil {
+			t.Fatalf("create %T: %v", record, err)
+		}
+	}
+}
+
+// statement - отчет компании за год с балансом (balance = nil - отчет без баланса)
+func statement(t *testing.T, database *gorm.DB, regcode string, year int, balance *models.BalanceSheet) {
+	t.Helper()
+	fs := models.FinancialStatement{LegalEntityRegistrationNumber: ptr(regcode), Year: ptr(year), Currency: ptr("EUR")}
+	create(t, database, &fs)
+	if balance != nil {
+		balance.StatementID = ptr(fs.ID)
+		create(t, database, balance)
+	}
+}
+
+// runRule выполняет одно правило и возвращает его флаги по regcode
+func runRule(t *testing.T, database *gorm.DB, rules Rules, run func(*gorm.DB, *evaluation) error) map[string][]models.CompanyRiskFlag {
+	t.Helper()
+	e := &evaluation{rules: rules, evaluatedAt: testNow, flags: make(map[string][]models.CompanyRiskFlag)}
+	if err := run(database, e); err != nil {
+		t.Fatalf("rule: %v", err)
+	}
+	return e.flags
+}
+
+// flaggedRegcodes - отсортированные regcode с флагами
+func flaggedRegcodes(flags map[string][]models.CompanyRiskFlag) []string {
+	regcodes := make([]string, 0, len(flags))
+	for regcode := range flags {
+		regcodes = append(regcodes, regcode)
+	}
+	sort.Strings(regcodes)
+	return regcodes
+}
+
+// flagValue - значение единственного флага компании
+func flagValue(t *testing.T, flags map[string][]models.CompanyRiskFlag, regcode string) (*float64, *int) {
+	t.Helper()
+	if len(flags[regcode]) != 1 {
+		t.Fatalf("flags of %s = %+v, want exactly one", regcode, flags[regcode])
+	}
+	return flags[regcode][0].Value, flags[regcode][0].Year
+}
+
+func TestStronglyConnected(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		want  [][]string
+	}{
+		{"self-loop", map[string][]string{"A": {"A"}}, [][]string{{"A"}}},
+		{"two-cycle", map[string][]string{"A": {"B"}, "B": {"A"}}, [][]string{{"A", "B"}}},
+		{"dag", map[string][]string{"A": {"B", "C"}, "B": {"C"}}, [][]string{{"A"}, {"B"}, {"C"}}},
+		{"cycle with tail", map[string][]string{"A": {"B"}, "B": {"C"}, "C": {"B", "D"}}, [][]string{{"A"}, {"B", "C"}, {"D"}}},
+		{"two cycles", map[string][]string{"A": {"B"}, "B": {"C"}, "C": {"A"}, "D": {"E", "A"}, "E": {"D"}}, [][]string{{"A", "B", "C"}, {"D", "E"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stronglyConnected(tt.graph)
+			for _, component := range got {
+				sort.Strings(component)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i][0] < got[j][0] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stronglyConnected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegativeEquityUsesLatestBalance(t *testing.T) {
+	database := connectTestSQLite(t)
+	// 40000000001: капитал был отрицательным, в последнем балансе положительный
+	statement(t, database, "40000000001", 2020, &models.BalanceSheet{Equity: ptr(int64(-5))})
+	statement(t, database, "40000000001", 2021, &models.BalanceSheet{Equity: ptr(int64(10))})
+	// 40000000002: последний отчет (2022) без баланса - берется баланс за 2021
+	statement(t, database, "40000000002", 2019, &models.BalanceSheet{Equity: ptr(int64(10))})
+	statement(t, database, "40000000002", 2021, &models.BalanceSheet{Equity: ptr(int64(-3))})
+	statement(t, database, "40000000002", 2022, nil)
+	// 40000000003: капитал не указан
+	statement(t, database, "40000000003", 2021, &models.BalanceSheet{TotalAssets: ptr(int64(1))})
+
+	flags := runRule(t, database, DefaultRules(), negativeEquity)
+	if got := flaggedRegcodes(flags); !reflect.DeepEqual(got, []string{"40000000002"}) {
+		t.Fatalf("flagged = %v, want [40000000002]", got)
+	}
+	value, year := flagValue(t, flags, "40000000002")
+	if value == nil || *value != -3 || year == nil || *year != 2021 {
+		t.Errorf("flag value/year = %v/%v, want -3/2021", value, year)
+	}
+}
+
+func TestZeroAssetsWithLiabilities(t *testing.T) {
+	database := connectTestSQLite(t)
+	statement(t, database, "40000000001", 2021, &models.BalanceSheet{
+		TotalAssets: ptr(int64(0)), CurrentLiabilities: ptr(int64(5)), Provisions: ptr(int64(2)),
+	})
+	statement(t, database, "40000000002", 2021, &models.BalanceSheet{TotalAssets: ptr(int64(0))})
+	statement(t, database, "40000000003", 2021, &models.BalanceSheet{TotalAssets: ptr(int64(100)), CurrentLiabilities: ptr(int64(5))})
+	// Нулевые активы были раньше, в последнем балансе - нет
+	statement(t, database, "40000000004", 2020, &models.BalanceSheet{TotalAssets: ptr(int64(0)), CurrentLiabilities: ptr(int64(5))})
+	statement(t, database, "40000000004", 2021, &models.BalanceSheet{TotalAssets: ptr(int64(10)), CurrentLiabilities: ptr(int64(5))})
+
+	flags := runRule(t, database, DefaultRules(), zeroAssetsWithLiabilities)
+	if got := flaggedRegcodes(flags); !reflect.DeepEqual(got, []string{"40000000001"}) {
+		t.Fatalf("flagged = %v, want [40000000001]", got)
+	}
+	value, year := flagValue(t, flags, "40000000001")
+	if value == nil || *value != 7 || year == nil || *year != 2021 {
+		t.Errorf("flag value/year = %v/%v, want 7/2021", value, year)
+	}
+}
+
+func TestNoRecentStatements(t *testing.T) {
+	database := connectTestSQLite(t)
+	registered := ptr(time.Date(2010, time.March, 1, 0, 0, 0, 0, time.UTC))
+	company := func(regcode, regtype string, registered *time.Time) *models.Registers {
+		return &models.Registers{Regcode: ptr(regcode), Regtype: ptr(regtype), Registered: registered}
+	}
+	terminated := company("40000000005", "K", registered)
+	terminated.Terminated = ptr(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	closed := company("40000000006", "K", registered)
+	closed.Closed = ptr("L")
+	create(t, database,
+		company("40000000001", "K", registered),                                                  // Последний отчет за 2021 - раньше 2022
+		company("40000000002", "K", registered),                                                  // Есть отчет за 2022
+		company("40000000003", "K", registered),                                                  // Отчетов нет совсем
+		company("40000000004", "K", ptr(time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC))), // Зарегистрирована в периоде
+		terminated,
+		closed,
+		company("40000000007", "B", registered), // Не тот regtype
+	)
+	statement(t, database, "40000000001", 2021, nil)
+	statement(t, database, "40000000002", 2022, nil)
+
+	// years = 2 при оценке в 2024 году: нужны отчеты за 2022 год или позже
+	flags := runRule(t, database, DefaultRules(), noRecentStatements)
+	if got := flaggedRegcodes(flags); !reflect.DeepEqual(got, []string{"40000000001", "40000000003"}) {
+		t.Fatalf("flagged = %v, want [40000000001 40000000003]", got)
+	}
+	value, year := flagValue(t, flags, "40000000001")
+	if value == nil || *value != 2021 || year == nil || *year != 2021 {
+		t.Errorf("40000000001 value/year = %v/%v, want 2021/2021", value, year)
+	}
+	if value, year := flagValue(t, flags, "40000000003"); value != nil || year != nil {
+		t.Errorf("40000000003 value/year = %v/%v, want nil (no statements)", value, year)
+	}
+}
+
+func TestFrequentOwnerChanges(t *testing.T) {
+	database := connectTestSQLite(t)
+	recent := testNow.AddDate(0, -1, 0)
+	old := testNow.AddDate(-2, 0, 0)
+	event := func(regcode, operation string, at time.Time) *models.ChangeEvent {
+		return &models.ChangeEvent{Entity: "beneficial_owners", Regcode: ptr(regcode), Operation: operation, CreatedAt: at}
+	}
+	create(t, database,
+		// 40000000001: 3 добавления/удаления за год - на пороге (max_changes = 3)
+		event("40000000001", models.ChangeOperationInsert, recent),
+		event("40000000001", models.ChangeOperationDelete, recent),
+		event("40000000001", models.ChangeOperationInsert, recent),
+		// 40000000002: изменения полей и старые события не считаются
+		event("40000000002", models.ChangeOperationUpdate, recent),
+		event("40000000002", models.ChangeOperationUpdate, recent),
+		event("40000000002", models.ChangeOperationInsert, old),
+		event("40000000002", models.ChangeOperationInsert, recent),
+	)
+	// 40000000003: журнала нет, но 4 бенефициара зарегистрированы за последний год
+	for i := 0; i < 4; i++ {
+		create(t, database, &models.BeneficialOwner{LegalEntityRegistrationNumber: ptr("40000000003"), RegisteredOn: ptr(recent)})
+	}
+	create(t, database, &models.BeneficialOwner{LegalEntityRegistrationNumber: ptr("40000000002"), RegisteredOn: ptr(old)})
+
+	flags := runRule(t, database, DefaultRules(), frequentOwnerChanges)
+	if got := flaggedRegcodes(flags); !reflect.DeepEqual(got, []string{"40000000001", "40000000003"}) {
+		t.Fatalf("flagged = %v, want [40000000001 40000000003]", got)
+	}
+	if value, _ := flagValue(t, flags, "40000000003"); value == nil || *value != 4 {
+		t.Errorf("40000000003 value = %v, want 4", value)
+	}
+}
+
+func TestSharedAddress(t *testing.T) {
+	database := connectTestSQLite(t)
+	company := func(regcode, addressid string) *models.Registers {
+		return &models.Registers{Regcode: ptr(regcode), Addressid: ptr(addressid), Address: ptr("Rīga, Brīvības iela " + addressid)}
+	}
+	closed := company("40000000004", "100")
+	closed.Closed = ptr("L")
+	terminated := company("40000000005", "100")
+	terminated.Terminated = ptr(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	create(t, database,
+		// addressid 100: 3 действующие компании (закрытая и ликвидированная не считаются)
+		company("40000000001", "100"), company("40000000002", "100"), company("40000000003", "100"),
+		closed, terminated,
+		// addressid 200: 2 действующие - другая всего одна, порог не превышен
+		company("40000000006", "200"), company("40000000007", "200"),
+		// Одинаковый текст адреса, но разные addressid
+		&models.Registers{Regcode: ptr("40000000008"), Addressid: ptr("300"), Address: ptr("Rīga, Brīvības iela 100")},
+	)
+
+	rules := DefaultRules()
+	rules.SharedAddress.MaxCompanies = 1
+	flags := runRule(t, database, rules, sharedAddress)
+	if got := flaggedRegcodes(flags); !reflect.DeepEqual(got, []string{"40000000001", "40000000002", "40000000003"}) {
+		t.Fatalf("flagged = %v, want companies of addressid 100", got)
+	}
+	if value, _ := flagValue(t, flags, "40000000001"); value == nil || *value != 2 {
+		t.Errorf("other companies = %v, want 2", value)
+	}
+}
+
+func TestCircularOwnership(t *testing.T) {
+	database := connectTestSQLite(t)
+	member := func(owner, company string) *models.Member {
+		return &models.Member{LegalEntityRegistrationNumber: ptr(owner), AtLegalEntityRegistrationNumber: ptr(company), EntityType: ptr("LEGAL_ENTITY")}
+	}
+	create(t, database,
+		member("40000000001", "40000000002"), member("40000000002", "40000000001"), // Цикл из двух компаний
+		member("40000000003", "40000000003"),                                       // Участник самой себя
+		member("40000000004", "40000000005"), member("40000000005", "40000000006"), // Цепочка без цикла
+		&models.Member{AtLegalEntityRegistrationNumber: ptr("40000000004"), EntityType: ptr("NATURAL_PERSON"), Name: ptr("Jānis Bērziņš")},
+	)
+
+	flags := runRule(t, database, DefaultRules(), circularOwnership)
+	if got := flaggedRegcodes(flags); !reflect.DeepEqual(got, []string{"40000000001", "40000000002", "40000000003"}) {
+		t.Fatalf("flagged = %v, want [40000000001 40000000002 40000000003]", got)
+	}
+	if value, _ := flagValue(t, flags, "40000000001"); value == nil || *value != 2 {
+		t.Errorf("cycle size = %v, want 2", value)
+	}
+	if flags["40000000003"][0].Message != "Компания указана участником самой себя" {
+		t.Errorf("self-owned message = %q", flags["40000000003"][0].Message)
+	}
+}
+
+func TestRefreshScoresAndLevels(t *testing.T) {
+	database := connectTestSQLite(t)
+	if err := database.AutoMigrate(&models.CompanyRisk{}, &models.CompanyRiskFlag{}); err != nil {
+		t.Fatal(err)
+	}
+	// Отрицательный капитал (30) + цикл владения (30) = 60 - high; только цикл - medium
+	statement(t, database, "40000000001", testNow.Year()-1, &models.BalanceSheet{Equity: ptr(int64(-1))})
+	create(t, database,
+		&models.Member{LegalEntityRegistrationNumber: ptr("40000000001"), AtLegalEntityRegistrationNumber: ptr("40000000002")},
+		&models.Member{LegalEntityRegistrationNumber: ptr("40000000002"), AtLegalEntityRegistrationNumber: ptr("40000000001")},
+	)
+
+	flagged, err := Refresh(database, DefaultRules())
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if flagged != 2 {
+		t.Errorf("flagged companies = %d, want 2", flagged)
+	}
+	var risks []models.CompanyRisk
+	if err := database.Order("regcode").Find(&risks).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(risks) != 2 ||
+		risks[0].Score != 60 || risks[0].Level != models.RiskLevelHigh || risks[0].Flags != 2 ||
+		risks[1].Score != 30 || risks[1].Level != models.RiskLevelMedium || risks[1].Flags != 1 {
+		t.Errorf("company risks = %+v, want 40000000001 60/high/2 and 40000000002 30/medium/1", risks)
+	}
+}
+
+// TestRulesPostgresSQL проверяет запросы всех правил парсером PostgreSQL
+func TestRulesPostgresSQL(t *testing.T) {
+	database, recorder := postgrestest.Open(t)
+	rules := DefaultRules()
+	e := &evaluation{rules: rules, evaluatedAt: testNow, flags: make(map[string][]models.CompanyRiskFlag)}
+	steps := map[string]func(*gorm.DB, *evaluation) error{
+		"negative_equity":              negativeEquity,
+		"zero_assets_with_liabilities": zeroAssetsWithLiabilities,
+		"no_recent_statements":         noRecentStatements,
+		"frequent_owner_changes":       frequentOwnerChanges,
+		"shared_address":               sharedAddress,
+		"circular_ownership":           circularOwnership,
+	}
+	for name, run := range steps {
+		t.Run(name, func(t *testing.T) {
+			recorder.Statements = nil
+			if err := run(database, e); err != nil {
+				t.Fatalf("rule: %v", err)
+			}
+			postgrestest.Check(t, recorder.Statements)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// risk/rules.go
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"capital-view-api/models"
+)
+
+// DefaultRulesPath - файл правил, если RISK_RULES_PATH не задан
+const DefaultRulesPath = "risk_rules.json"
+
+// Rule - настройки одного правила. Параметры, не относящиеся к правилу, не используются.
+type Rule struct {
+	Enabled      bool     `json:"enabled"`
+	Severity     string   `json:"severity"` // low, medium, high
+	Weight       int      `json:"weight"`   // Вклад в score
+	Years        int      `json:"years,omitempty"`
+	RegTypes     []string `json:"regtypes,omitempty"` // Только для этих regtype (пусто - все)
+	WindowDays   int      `json:"window_days,omitempty"`
+	MaxChanges   int      `json:"max_changes,omitempty"`
+	MaxCompanies int      `json:"max_companies,omitempty"`
+}
+
+// Rules - правила оценки риска (файл risk_rules.json). Поля, которых нет в файле, берутся из DefaultRules.
+type Rules struct {
+	MediumScore int `json:"medium_score"` // score, начиная с которого уровень medium
+	HighScore   int `json:"high_score"`   // score, начиная с которого уровень high
+
+	NegativeEquity            Rule `json:"negative_equity"`
+	ZeroAssetsWithLiabilities Rule `json:"zero_assets_with_liabilities"`
+	NoRecentStatements        Rule `json:"no_recent_statements"`
+	FrequentOwnerChanges      Rule `json:"frequent_beneficial_owner_changes"`
+	SharedAddress             Rule `json:"shared_address"`
+	CircularOwnership         Rule `json:"circular_ownership"`
+}
+
+// DefaultRules - правила по умолчанию (совпадают с risk_rules.json в репозитории)
+func DefaultRules() Rules {
+	return Rules{
+		MediumScore:               30,
+		HighScore:                 60,
+		NegativeEquity:            Rule{Enabled: true, Severity: models.RiskLevelHigh, Weight: 30},
+		ZeroAssetsWithLiabilities: Rule{Enabled: true, Severity: models.RiskLevelMedium, Weight: 20},
+		NoRecentStatements:        Rule{Enabled: true, Severity: models.RiskLevelMedium, Weight: 20, Years: 2, RegTypes: []string{"K"}},
+		FrequentOwnerChanges:      Rule{Enabled: true, Severity: models.RiskLevelMedium, Weight: 15, WindowDays: 365, MaxChanges: 3},
+		SharedAddress:             Rule{Enabled: true, Severity: models.RiskLevelLow, Weight: 10, MaxCompanies: 10},
+		CircularOwnership:         Rule{Enabled: true, Severity: models.RiskLevelHigh, Weight: 30},
+	}
+}
+
+// RulesPath - путь к файлу правил: RISK_RULES_PATH или DefaultRulesPath
+func RulesPath() string {
+	if path := os.Getenv("RISK_RULES_PATH"); path != "" {
+		return path
+	}
+	return DefaultRulesPath
+}
+
+// LoadRules читает правила из JSON файла поверх DefaultRules. Если файла нет, используются правила по умолчанию.
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Risk rules file %s not found, using default rules.", path)
+		return rules, nil
+	}
+	if err != nil {
+		return rules, err
+	}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// level - уровень риска по score
+func (r Rules) level(score int) string {
+	switch {
+	case score >= r.HighScore:
+		return models.RiskLevelHigh
+	case score >= r.MediumScore:
+		return models.RiskLevelMedium
+	default:
+		return models.RiskLevelLow
+	}
+}
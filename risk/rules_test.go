@@ -0,0 +1,69 @@
+// risk/rules_test.go
+package risk
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"capital-view-api/models"
+)
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+
+	rules, err := LoadRules(filepath.Join(dir, "missing.json"))
+	if err != nil || !reflect.DeepEqual(rules, DefaultRules()) {
+		t.Errorf("LoadRules(missing) = %+v, %v; want default rules", rules, err)
+	}
+
+	// Поля, которых нет в файле, остаются по умолчанию
+	path := filepath.Join(dir, "rules.json")
+	data := `{"high_score": 50, "shared_address": {"enabled": false}, "no_recent_statements": {"enabled": true, "severity": "high", "weight": 25, "years": 3}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	if rules.HighScore != 50 || rules.MediumScore != 30 {
+		t.Errorf("scores = %d/%d, want medium 30 (default) and high 50", rules.MediumScore, rules.HighScore)
+	}
+	if rules.SharedAddress.Enabled {
+		t.Error("shared_address must be disabled by the file")
+	}
+	if rules.NoRecentStatements.Years != 3 || rules.NoRecentStatements.Weight != 25 {
+		t.Errorf("no_recent_statements = %+v, want years 3 and weight 25", rules.NoRecentStatements)
+	}
+	if !reflect.DeepEqual(rules.NegativeEquity, DefaultRules().NegativeEquity) {
+		t.Errorf("negative_equity = %+v, want the default", rules.NegativeEquity)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Error("LoadRules must fail on invalid JSON")
+	}
+}
+
+func TestRulesMatchRepositoryFile(t *testing.T) {
+	rules, err := LoadRules(filepath.Join("..", DefaultRulesPath))
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	if !reflect.DeepEqual(rules, DefaultRules()) {
+		t.Errorf("%s = %+v, want DefaultRules()", DefaultRulesPath, rules)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	rules := DefaultRules()
+	for score, want := range map[int]string{0: models.RiskLevelLow, 29: models.RiskLevelLow, 30: models.RiskLevelMedium, 59: models.RiskLevelMedium, 60: models.RiskLevelHigh, 100: models.RiskLevelHigh} {
+		if got := rules.level(score); got != want {
+			t.Errorf("level(%d) = %s, want %s", score, got, want)
+		}
+	}
+}
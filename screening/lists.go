@@ -0,0 +1,424 @@
+// screening/lists.go
+package screening
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"capital-view-api/models"
+)
+
+// Форматы файлов списков
+const (
+	FormatCSV     = "csv"
+	FormatUNXML   = "un-xml"   // Сводный список СБ ООН (CONSOLIDATED_LIST: INDIVIDUAL, ENTITY)
+	FormatEUXML   = "eu-xml"   // Сводный список ЕС (FSF: sanctionEntity)
+	FormatOFACXML = "ofac-xml" // OFAC SDN (sdnList: sdnEntry)
+)
+
+// valueSeparator - разделитель нескольких значений в полях ScreeningEntry
+const valueSeparator = "; "
+
+// ParseFile читает список из файла .csv или .xml. Формат XML определяется по элементам записей.
+func ParseFile(path string) (entries []models.ScreeningEntry, format string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = parseCSV(file)
+		return entries, FormatCSV, err
+	case ".xml":
+		return parseXML(file)
+	default:
+		return nil, "", fmt.Errorf("unsupported list file extension %q (expected .csv or .xml)", filepath.Ext(path))
+	}
+}
+
+// CategoryForFile - категория списка по имени файла: "pep" в имени - PEP, иначе санкции
+func CategoryForFile(path string) string {
+	if strings.Contains(strings.ToLower(filepath.Base(path)), "pep") {
+		return models.ScreeningCategoryPEP
+	}
+	return models.ScreeningCategorySanctions
+}
+
+// csvColumns - допустимые названия колонок CSV (без учета регистра)
+var csvColumns = map[string][]string{
+	"id":         {"id", "uid", "external_id", "reference", "reference_number"},
+	"name":       {"name", "full_name", "whole_name"},
+	"first_name": {"first_name", "firstname", "forename", "given_name"},
+	"last_name":  {"last_name", "lastname", "surname", "family_name"},
+	"aliases":    {"aliases", "alias", "aka"},
+	"type":       {"type", "entry_type", "entity_type", "subject_type"},
+	"birth_date": {"birth_date", "date_of_birth", "dob", "birthdate"},
+	"country":    {"country", "countries", "nationality", "citizenship"},
+	"programs":   {"program", "programs", "regime", "list", "position"},
+	"remarks":    {"remarks", "remark", "comments", "notes"},
+}
+
+// parseCSV читает CSV с заголовком. Разделитель - ';' или ',' (по заголовку); псевдонимы в одной
+// колонке разделяются '|' (или ';', если разделитель колонок - ',').
+func parseCSV(reader io.Reader) ([]models.ScreeningEntry, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	header := text
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		header = text[:i]
+	}
+	csvReader := csv.NewReader(strings.NewReader(text))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	aliasSeparators := "|"
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		csvReader.Comma = ';'
+	} else {
+		aliasSeparators = "|;"
+	}
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty CSV file")
+	}
+
+	columns := make(map[string]int)
+	for i, title := range records[0] {
+		title = strings.ToLower(strings.TrimSpace(title))
+		for column, names := range csvColumns {
+			for _, name := range names {
+				if title == name {
+					if _, ok := columns[column]; !ok {
+						columns[column] = i
+					}
+				}
+			}
+		}
+	}
+	_, hasName := columns["name"]
+	_, hasLastName := columns["last_name"]
+	if !hasName && !hasLastName {
+		return nil, errors.New("CSV header has no name (or last_name) column")
+	}
+
+	var entries []models.ScreeningEntry
+	for _, row := range records[1:] {
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		name := value("name")
+		if name == "" {
+			name = joinNonEmpty(" ", value("first_name"), value("last_name"))
+		}
+		if name == "" {
+			continue
+		}
+		aliases := strings.FieldsFunc(value("aliases"), func(r rune) bool {
+			return strings.ContainsRune(aliasSeparators, r)
+		})
+		entries = append(entries, models.ScreeningEntry{
+			ExternalID: optional(value("id")),
+			EntryType:  entryType(value("type")),
+			Name:       name,
+			Aliases:    optional(joinValues(aliases)),
+			BirthDates: optional(value("birth_date")),
+			Countries:  optional(value("country")),
+			Programs:   optional(value("programs")),
+			Remarks:    optional(value("remarks")),
+		})
+	}
+	return entries, nil
+}
+
+// --- XML ---
+
+// unValue - элемент с <VALUE> (NATIONALITY, DESIGNATION и т.п.)
+type unValue struct {
+	Values []string `xml:"VALUE"`
+}
+
+// unRecord - INDIVIDUAL или ENTITY сводного списка ООН
+type unRecord struct {
+	DataID          string    `xml:"DATAID"`
+	Reference       string    `xml:"REFERENCE_NUMBER"`
+	FirstName       string    `xml:"FIRST_NAME"`
+	SecondName      string    `xml:"SECOND_NAME"`
+	ThirdName       string    `xml:"THIRD_NAME"`
+	FourthName      string    `xml:"FOURTH_NAME"`
+	ListType        string    `xml:"UN_LIST_TYPE"`
+	Comments        string    `xml:"COMMENTS1"`
+	Nationality     []unValue `xml:"NATIONALITY"`
+	IndividualAlias []struct {
+		Name string `xml:"ALIAS_NAME"`
+	} `xml:"INDIVIDUAL_ALIAS"`
+	EntityAlias []struct {
+		Name string `xml:"ALIAS_NAME"`
+	} `xml:"ENTITY_ALIAS"`
+	BirthDates []struct {
+		Date string `xml:"DATE"`
+		Year string `xml:"YEAR"`
+	} `xml:"INDIVIDUAL_DATE_OF_BIRTH"`
+}
+
+// euEntity - sanctionEntity сводного списка ЕС
+type euEntity struct {
+	LogicalID   string `xml:"logicalId,attr"`
+	EUReference string `xml:"euReferenceNumber,attr"`
+	Remark      string `xml:"remark"`
+	SubjectType struct {
+		Code string `xml:"code,attr"`
+	} `xml:"subjectType"`
+	Regulations []struct {
+		Programme string `xml:"programme,attr"`
+	} `xml:"regulation"`
+	NameAliases []struct {
+		WholeName string `xml:"wholeName,attr"`
+	} `xml:"nameAlias"`
+	Birthdates []struct {
+		Birthdate string `xml:"birthdate,attr"`
+		Year      string `xml:"year,attr"`
+	} `xml:"birthdate"`
+	Citizenships []struct {
+		Country string `xml:"countryDescription,attr"`
+	} `xml:"citizenship"`
+}
+
+// ofacName - имя записи SDN или псевдонима
+type ofacName struct {
+	FirstName string `xml:"firstName"`
+	LastName  string `xml:"lastName"`
+}
+
+// ofacEntry - sdnEntry списка OFAC SDN
+type ofacEntry struct {
+	UID string `xml:"uid"`
+	ofacName
+	SDNType  string     `xml:"sdnType"`
+	Remarks  string     `xml:"remarks"`
+	Programs []string   `xml:"programList>program"`
+	Akas     []ofacName `xml:"akaList>aka"`
+	Births   []string   `xml:"dateOfBirthList>dateOfBirthItem>dateOfBirth"`
+	Citizens []string   `xml:"nationalityList>nationality>country"`
+}
+
+// parseXML потоково читает XML и разбирает записи известных сводных списков
+func parseXML(reader io.Reader) ([]models.ScreeningEntry, string, error) {
+	decoder := xml.NewDecoder(reader)
+	var entries []models.ScreeningEntry
+	format := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, format, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "INDIVIDUAL", "ENTITY":
+			var record unRecord
+			if err := decoder.DecodeElement(&record, &start); err != nil {
+				return nil, FormatUNXML, err
+			}
+			format = FormatUNXML
+			if entry, ok := record.entry(start.Name.Local == "INDIVIDUAL"); ok {
+				entries = append(entries, entry)
+			}
+		case "sanctionEntity":
+			var record euEntity
+			if err := decoder.DecodeElement(&record, &start); err != nil {
+				return nil, FormatEUXML, err
+			}
+			format = FormatEUXML
+			if entry, ok := record.entry(); ok {
+				entries = append(entries, entry)
+			}
+		case "sdnEntry":
+			var record ofacEntry
+			if err := decoder.DecodeElement(&record, &start); err != nil {
+				return nil, FormatOFACXML, err
+			}
+			format = FormatOFACXML
+			if entry, ok := record.entry(); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if format == "" {
+		return nil, "", errors.New("no INDIVIDUAL/ENTITY (UN), sanctionEntity (EU) or sdnEntry (OFAC) elements found")
+	}
+	return entries, format, nil
+}
+
+func (r unRecord) entry(individual bool) (models.ScreeningEntry, bool) {
+	name := joinNonEmpty(" ", r.FirstName, r.SecondName, r.ThirdName, r.FourthName)
+	if name == "" {
+		return models.ScreeningEntry{}, false
+	}
+	var aliases, births, countries []string
+	for _, alias := range r.IndividualAlias {
+		aliases = append(aliases, alias.Name)
+	}
+	for _, alias := range r.EntityAlias {
+		aliases = append(aliases, alias.Name)
+	}
+	for _, birth := range r.BirthDates {
+		births = append(births, firstNonEmpty(birth.Date, birth.Year))
+	}
+	for _, nationality := range r.Nationality {
+		countries = append(countries, nationality.Values...)
+	}
+	kind := models.ScreeningEntryEntity
+	if individual {
+		kind = models.ScreeningEntryPerson
+	}
+	return models.ScreeningEntry{
+		ExternalID: optional(firstNonEmpty(r.Reference, r.DataID)),
+		EntryType:  &kind,
+		Name:       name,
+		Aliases:    optional(joinValues(aliases)),
+		BirthDates: optional(joinValues(births)),
+		Countries:  optional(joinValues(countries)),
+		Programs:   optional(strings.TrimSpace(r.ListType)),
+		Remarks:    optional(strings.TrimSpace(r.Comments)),
+	}, true
+}
+
+func (e euEntity) entry() (models.ScreeningEntry, bool) {
+	var names, births, countries, programs []string
+	for _, alias := range e.NameAliases {
+		names = append(names, alias.WholeName)
+	}
+	names = strings.Split(joinValues(names), valueSeparator)
+	if names[0] == "" {
+		return models.ScreeningEntry{}, false
+	}
+	for _, birth := range e.Birthdates {
+		births = append(births, firstNonEmpty(birth.Birthdate, birth.Year))
+	}
+	for _, citizenship := range e.Citizenships {
+		countries = append(countries, citizenship.Country)
+	}
+	for _, regulation := range e.Regulations {
+		programs = append(programs, regulation.Programme)
+	}
+	return models.ScreeningEntry{
+		ExternalID: optional(firstNonEmpty(e.EUReference, e.LogicalID)),
+		EntryType:  entryType(e.SubjectType.Code),
+		Name:       names[0],
+		Aliases:    optional(joinValues(names[1:])),
+		BirthDates: optional(joinValues(births)),
+		Countries:  optional(joinValues(countries)),
+		Programs:   optional(joinValues(programs)),
+		Remarks:    optional(strings.TrimSpace(e.Remark)),
+	}, true
+}
+
+func (e ofacEntry) entry() (models.ScreeningEntry, bool) {
+	name := joinNonEmpty(" ", e.FirstName, e.LastName)
+	if name == "" {
+		return models.ScreeningEntry{}, false
+	}
+	var aliases []string
+	for _, aka := range e.Akas {
+		aliases = append(aliases, joinNonEmpty(" ", aka.FirstName, aka.LastName))
+	}
+	return models.ScreeningEntry{
+		ExternalID: optional(e.UID),
+		EntryType:  entryType(e.SDNType),
+		Name:       name,
+		Aliases:    optional(joinValues(aliases)),
+		BirthDates: optional(joinValues(e.Births)),
+		Countries:  optional(joinValues(e.Citizens)),
+		Programs:   optional(joinValues(e.Programs)),
+		Remarks:    optional(strings.TrimSpace(e.Remarks)),
+	}, true
+}
+
+// entryType приводит тип записи из списка к person/entity (nil - неизвестен: сравнивается со всеми)
+func entryType(value string) *string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	var kind string
+	switch {
+	case value == "":
+		return nil
+	case strings.Contains(value, "person") || strings.Contains(value, "individual"):
+		kind = models.ScreeningEntryPerson
+	case strings.Contains(value, "entity") || strings.Contains(value, "enterprise") ||
+		strings.Contains(value, "company") || strings.Contains(value, "organi"):
+		kind = models.ScreeningEntryEntity
+	default:
+		return nil
+	}
+	return &kind
+}
+
+// joinValues объединяет непустые уникальные значения через valueSeparator
+func joinValues(values []string) string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return strings.Join(result, valueSeparator)
+}
+
+// splitValues - обратная операция к joinValues
+func splitValues(value *string) []string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return strings.Split(*value, valueSeparator)
+}
+
+func joinNonEmpty(separator string, values ...string) string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return strings.Join(result, separator)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,90 @@
+// screening/lists_test.go
+package screening
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"capital-view-api/models"
+)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// assertEntries сравнивает разобранные записи с ожидаемыми
+func assertEntries(t *testing.T, got, want []models.ScreeningEntry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("entries = %d, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("entry %d:\n got %s\nwant %s", i, describeEntry(got[i]), describeEntry(want[i]))
+		}
+	}
+}
+
+func describeEntry(entry models.ScreeningEntry) string {
+	value := func(field *string) string {
+		if field == nil {
+			return "<nil>"
+		}
+		return "\"" + *field + "\""
+	}
+	return "id=" + value(entry.ExternalID) + " type=" + value(entry.EntryType) + " name=\"" + entry.Name +
+		"\" aliases=" + value(entry.Aliases) + " birth=" + value(entry.BirthDates) + " countries=" + value(entry.Countries) +
+		" programs=" + value(entry.Programs) + " remarks=" + value(entry.Remarks)
+}
+
+func TestParseFilePEPSample(t *testing.T) {
+	path := filepath.Join("..", "csv_examples", "screening", "pep_sample.csv")
+	entries, format, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != FormatCSV {
+		t.Errorf("format = %q, want %q", format, FormatCSV)
+	}
+	if category := CategoryForFile(path); category != models.ScreeningCategoryPEP {
+		t.Errorf("category = %q, want %q", category, models.ScreeningCategoryPEP)
+	}
+	assertEntries(t, entries, []models.ScreeningEntry{
+		{
+			ExternalID: ptr("PEP-1"), EntryType: ptr(models.ScreeningEntryPerson), Name: "Didzis Kadaks",
+			BirthDates: ptr("14.03.1968"), Countries: ptr("LV"), Programs: ptr("Pašvaldības domes deputāts"),
+		},
+		{
+			// Колонки разделены ';' - псевдонимы разделяются только '|'
+			ExternalID: ptr("PEP-2"), EntryType: ptr(models.ScreeningEntryPerson), Name: "Argods Lusinsh",
+			Aliases: ptr("Argods Lūsiņš; A. Lusins"), BirthDates: ptr("1959"), Countries: ptr("LV"),
+			Programs: ptr("Valsts sekretāra vietnieks"),
+		},
+	})
+}
+
+func TestParseFileUNSample(t *testing.T) {
+	path := filepath.Join("..", "csv_examples", "screening", "un_sample.xml")
+	entries, format, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != FormatUNXML {
+		t.Errorf("format = %q, want %q", format, FormatUNXML)
+	}
+	if category := CategoryForFile(path); category != models.ScreeningCategorySanctions {
+		t.Errorf("category = %q, want %q", category, models.ScreeningCategorySanctions)
+	}
+	assertEntries(t, entries, []models.ScreeningEntry{
+		{
+			ExternalID: ptr("XXi.001"), EntryType: ptr(models.ScreeningEntryPerson), Name: "ROLAND LIPSHANS",
+			Aliases: ptr("Roland Lipschans"), BirthDates: ptr("1973-07-05"), Countries: ptr("Latvia"),
+			Programs: ptr("Sample"), Remarks: ptr("Fictitious entry for the sample import."),
+		},
+		{
+			ExternalID: ptr("XXe.001"), EntryType: ptr(models.ScreeningEntryEntity), Name: "KRASTNIEKI A.I. TRADING",
+			Aliases: ptr("Krastnieki A I"), Programs: ptr("Sample"),
+		},
+	})
+}
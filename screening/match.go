@@ -0,0 +1,267 @@
+// screening/match.go
+package screening
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+)
+
+// DefaultMinScore - минимальное сходство имен, при котором совпадение сохраняется
+const DefaultMinScore = 0.85
+
+// stopWords - организационно-правовые формы и служебные слова, не влияющие на сходство названий
+var stopWords = map[string]bool{
+	"sia": true, "as": true, "ik": true, "ks": true, "ps": true, "zs": true, "ak": true,
+	"biedriba": true, "nodibinajums": true, "sabiedriba": true, "ar": true, "ierobezotu": true, "atbildibu": true,
+	"ltd": true, "llc": true, "inc": true, "co": true, "corp": true, "company": true, "limited": true,
+	"gmbh": true, "ag": true, "oy": true, "ab": true, "uab": true, "ou": true, "plc": true,
+	"ooo": true, "oao": true, "zao": true, "pao": true, "jsc": true, "ojsc": true, "cjsc": true,
+	"the": true, "and": true, "of": true, "un": true,
+}
+
+// nameTokens - слова имени без регистра, диакритики, пунктуации и стоп-слов
+func nameTokens(name string) []string {
+	var tokens []string
+	for _, token := range strings.Fields(utils.FoldName(name)) {
+		if !stopWords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// Similarity - сходство двух имен от 0 до 1 без учета порядка слов: каждое слово короткого имени
+// сопоставляется с наиболее похожим свободным словом длинного (по расстоянию Левенштейна),
+// сумма делится на число слов длинного имени - лишние слова снижают сходство.
+func Similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	used := make([]bool, len(b))
+	total := 0.0
+	for _, token := range a {
+		best, bestIndex := 0.0, -1
+		for i, other := range b {
+			if used[i] {
+				continue
+			}
+			if ratio := tokenRatio(token, other); ratio > best {
+				best, bestIndex = ratio, i
+			}
+		}
+		if bestIndex >= 0 {
+			used[bestIndex] = true
+			total += best
+		}
+	}
+	return total / float64(len(b))
+}
+
+// tokenRatio - 1 - расстояние Левенштейна / длина большего слова
+func tokenRatio(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	left, right := []rune(a), []rune(b)
+	longest := len(left)
+	if len(right) > longest {
+		longest = len(right)
+	}
+	if longest == 0 {
+		return 1
+	}
+	previous := make([]int, len(right)+1)
+	current := make([]int, len(right)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(left); i++ {
+		current[0] = i
+		for j := 1; j <= len(right); j++ {
+			cost := 1
+			if left[i-1] == right[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return 1 - float64(previous[len(right)])/float64(longest)
+}
+
+// blockingKeys - ключи для отбора кандидатов: первые 3 буквы каждого слова
+// (опечатки в начале всех слов имени не находятся - это цена отказа от полного перебора)
+func blockingKeys(tokens []string) []string {
+	keys := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		runes := []rune(token)
+		if len(runes) > 3 {
+			runes = runes[:3]
+		}
+		keys = append(keys, string(runes))
+	}
+	return keys
+}
+
+// variant - имя или псевдоним записи списка
+type variant struct {
+	entry  int // Индекс в Index.entries
+	name   string
+	tokens []string
+}
+
+// Index - записи всех списков с индексом по ключам blockingKeys
+type Index struct {
+	entries  []models.ScreeningEntry
+	lists    map[uint]models.ScreeningList
+	years    [][]int // Годы рождения записей (из BirthDates)
+	variants []variant
+	byKey    map[string][]int // ключ -> индексы variants
+}
+
+var yearPattern = regexp.MustCompile(`\b(1[89]\d\d|20\d\d)\b`)
+
+// NewIndex строит индекс по записям списков
+func NewIndex(lists []models.ScreeningList, entries []models.ScreeningEntry) *Index {
+	index := &Index{
+		entries: entries,
+		lists:   make(map[uint]models.ScreeningList, len(lists)),
+		years:   make([][]int, len(entries)),
+		byKey:   make(map[string][]int),
+	}
+	for _, list := range lists {
+		index.lists[list.ID] = list
+	}
+	for i, entry := range entries {
+		if entry.BirthDates != nil {
+			for _, year := range yearPattern.FindAllString(*entry.BirthDates, -1) {
+				value := 0
+				for _, digit := range year {
+					value = value*10 + int(digit-'0')
+				}
+				index.years[i] = append(index.years[i], value)
+			}
+		}
+		for _, name := range append([]string{entry.Name}, splitValues(entry.Aliases)...) {
+			tokens := nameTokens(name)
+			if len(tokens) == 0 {
+				continue
+			}
+			variantIndex := len(index.variants)
+			index.variants = append(index.variants, variant{entry: i, name: name, tokens: tokens})
+			seen := make(map[string]bool)
+			for _, key := range blockingKeys(tokens) {
+				if !seen[key] {
+					seen[key] = true
+					index.byKey[key] = append(index.byKey[key], variantIndex)
+				}
+			}
+		}
+	}
+	return index
+}
+
+// Record - имя из реестра для проверки
+type Record struct {
+	Entity     string
+	RecordID   uint
+	Regcode    string
+	Field      string
+	Value      string
+	Kind       string     // models.ScreeningEntryPerson или models.ScreeningEntryEntity
+	BirthDate  *time.Time // Для физ. лиц, если известна
+	MaskedCode *string    // Маскированный персональный код: ddmmyy-*****
+}
+
+// Match возвращает совпадения записи со списками не ниже minScore (по одному на запись списка -
+// с лучшим из ее имен)
+func (index *Index) Match(record Record, minScore float64, screenedAt time.Time) []models.ScreeningMatch {
+	tokens := nameTokens(record.Value)
+	if len(tokens) == 0 {
+		return nil
+	}
+	candidates := make(map[int]bool)
+	for _, key := range blockingKeys(tokens) {
+		for _, variantIndex := range index.byKey[key] {
+			candidates[variantIndex] = true
+		}
+	}
+
+	best := make(map[int]models.ScreeningMatch) // индекс записи списка -> лучшее совпадение
+	for variantIndex := range candidates {
+		v := index.variants[variantIndex]
+		entry := index.entries[v.entry]
+		if entry.EntryType != nil && *entry.EntryType != record.Kind {
+			continue
+		}
+		score := Similarity(tokens, v.tokens)
+		if score < minScore {
+			continue
+		}
+		if current, ok := best[v.entry]; ok && current.Score >= score {
+			continue
+		}
+		list := index.lists[entry.ListID]
+		best[v.entry] = models.ScreeningMatch{
+			EntryID:        entry.ID,
+			ListID:         entry.ListID,
+			ListName:       list.Name,
+			Category:       list.Category,
+			Regcode:        record.Regcode,
+			Entity:         record.Entity,
+			RecordID:       record.RecordID,
+			MatchedField:   record.Field,
+			MatchedValue:   record.Value,
+			MatchedName:    v.name,
+			Score:          float64(int(score*1000+0.5)) / 1000,
+			BirthDateMatch: birthDateMatch(index.years[v.entry], record),
+			ScreenedAt:     screenedAt,
+		}
+	}
+
+	matches := make([]models.ScreeningMatch, 0, len(best))
+	for _, match := range best {
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// birthDateMatch сравнивает годы рождения из списка с датой рождения или кодом лица
+// (по коду известны только две последние цифры года). nil - сравнить нечем.
+func birthDateMatch(years []int, record Record) *bool {
+	if len(years) == 0 || record.Kind != models.ScreeningEntryPerson {
+		return nil
+	}
+	full, short := 0, -1
+	if record.BirthDate != nil {
+		full = record.BirthDate.Year()
+	} else if code := strings.TrimSpace(utils.StringValue(record.MaskedCode)); len(code) >= 6 && isDigits(code[4:6]) {
+		short = int(code[4]-'0')*10 + int(code[5]-'0')
+	} else {
+		return nil
+	}
+	matched := false
+	for _, year := range years {
+		if (full != 0 && year == full) || (short >= 0 && year%100 == short) {
+			matched = true
+			break
+		}
+	}
+	return &matched
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
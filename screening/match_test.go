@@ -0,0 +1,174 @@
+// screening/match_test.go
+package screening
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"capital-view-api/models"
+)
+
+func TestNameTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Jānis Bērziņš", []string{"janis", "berzins"}},
+		{"SIA \"Krastnieki A.I. Trading\"", []string{"krastnieki", "a", "i", "trading"}},
+		{"Sabiedrība ar ierobežotu atbildību Ozols", []string{"ozols"}},
+		{"SIA", nil},
+	}
+	for _, tt := range tests {
+		if got := nameTokens(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("nameTokens(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTokenRatio(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"janis", "janis", 1},
+		{"", "", 1},
+		{"abc", "", 0},
+		{"lusins", "lusinsh", 1 - 1.0/7},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"lipshans", "lipschans", 1 - 1.0/9},
+		{"ozols", "liepa", 0},
+		{"ā", "a", 0}, // Сравниваются руны, а не байты
+	}
+	for _, tt := range tests {
+		if got := tokenRatio(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("tokenRatio(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := tokenRatio(tt.b, tt.a); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("tokenRatio(%q, %q) = %v, want %v (symmetric)", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{"same", []string{"janis", "berzins"}, []string{"janis", "berzins"}, 1},
+		{"word order", []string{"berzins", "janis"}, []string{"janis", "berzins"}, 1},
+		{"extra word", []string{"janis"}, []string{"janis", "berzins"}, 0.5},
+		{"extra word reversed", []string{"janis", "berzins"}, []string{"janis"}, 0.5},
+		{"typo", []string{"argods", "lusins"}, []string{"argods", "lusinsh"}, (1 + 1 - 1.0/7) / 2},
+		{"word used once", []string{"anna", "anna"}, []string{"anna", "berzina"}, (1 + tokenRatio("anna", "berzina")) / 2},
+		{"empty", nil, []string{"janis"}, 0},
+		{"both empty", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockingKeys(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		want   []string
+	}{
+		{[]string{"roland", "lipshans"}, []string{"rol", "lip"}},
+		{[]string{"a", "i", "trading"}, []string{"a", "i", "tra"}},
+		{[]string{"lūsiņš"}, []string{"lūs"}}, // Первые 3 руны, а не байта
+		{nil, []string{}},
+	}
+	for _, tt := range tests {
+		if got := blockingKeys(tt.tokens); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("blockingKeys(%q) = %q, want %q", tt.tokens, got, tt.want)
+		}
+	}
+}
+
+func TestBirthDateMatch(t *testing.T) {
+	birthDate := time.Date(1973, 7, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		years  []int
+		record Record
+		want   *bool
+	}{
+		{"birth date year", []int{1973}, Record{Kind: models.ScreeningEntryPerson, BirthDate: &birthDate}, ptr(true)},
+		{"birth date other year", []int{1972, 1974}, Record{Kind: models.ScreeningEntryPerson, BirthDate: &birthDate}, ptr(false)},
+		{"birth date before code", []int{1974}, Record{Kind: models.ScreeningEntryPerson, BirthDate: &birthDate, MaskedCode: ptr("050774-*****")}, ptr(false)},
+		{"code yy", []int{1959, 1973}, Record{Kind: models.ScreeningEntryPerson, MaskedCode: ptr("050773-*****")}, ptr(true)},
+		{"code yy other century", []int{1873}, Record{Kind: models.ScreeningEntryPerson, MaskedCode: ptr("050773-*****")}, ptr(true)},
+		{"code other yy", []int{1973}, Record{Kind: models.ScreeningEntryPerson, MaskedCode: ptr("050774-*****")}, ptr(false)},
+		{"code with spaces", []int{1973}, Record{Kind: models.ScreeningEntryPerson, MaskedCode: ptr(" 050773-***** ")}, ptr(true)},
+		{"code too short", []int{1973}, Record{Kind: models.ScreeningEntryPerson, MaskedCode: ptr("0507")}, nil},
+		{"code without year", []int{1973}, Record{Kind: models.ScreeningEntryPerson, MaskedCode: ptr("0507**-*****")}, nil},
+		{"no date or code", []int{1973}, Record{Kind: models.ScreeningEntryPerson}, nil},
+		{"no years in list", nil, Record{Kind: models.ScreeningEntryPerson, BirthDate: &birthDate}, nil},
+		{"entity", []int{1973}, Record{Kind: models.ScreeningEntryEntity, BirthDate: &birthDate}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := birthDateMatch(tt.years, tt.record)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("birthDateMatch = %v, want %v", describeBool(got), describeBool(tt.want))
+			}
+		})
+	}
+}
+
+func describeBool(value *bool) string {
+	if value == nil {
+		return "nil"
+	}
+	if *value {
+		return "true"
+	}
+	return "false"
+}
+
+func TestIndexMatch(t *testing.T) {
+	lists := []models.ScreeningList{{ID: 1, Name: "UN", Category: models.ScreeningCategorySanctions}}
+	entries := []models.ScreeningEntry{
+		{ID: 10, ListID: 1, EntryType: ptr(models.ScreeningEntryPerson), Name: "ROLAND LIPSHANS",
+			Aliases: ptr("Roland Lipschans"), BirthDates: ptr("1973-07-05")},
+		{ID: 11, ListID: 1, EntryType: ptr(models.ScreeningEntryEntity), Name: "KRASTNIEKI A.I. TRADING"},
+	}
+	index := NewIndex(lists, entries)
+	screenedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Совпадение по псевдониму в другом порядке слов; год рождения - из кода лица
+	matches := index.Match(Record{
+		Entity: "members", RecordID: 5, Regcode: "40000000001", Field: "name", Value: "Lipschans Roland",
+		Kind: models.ScreeningEntryPerson, MaskedCode: ptr("050773-*****"),
+	}, DefaultMinScore, screenedAt)
+	if len(matches) != 1 {
+		t.Fatalf("matches = %+v, want one", matches)
+	}
+	match := matches[0]
+	if match.EntryID != 10 || match.ListName != "UN" || match.MatchedName != "Roland Lipschans" || match.Score != 1 {
+		t.Errorf("match = %+v, want entry 10 by alias with score 1", match)
+	}
+	if match.BirthDateMatch == nil || !*match.BirthDateMatch {
+		t.Errorf("birth date match = %v, want true", describeBool(match.BirthDateMatch))
+	}
+
+	// Тип записи списка должен совпадать с типом имени из реестра
+	if matches := index.Match(Record{Value: "Roland Lipshans", Kind: models.ScreeningEntryEntity}, DefaultMinScore, screenedAt); len(matches) != 0 {
+		t.Errorf("entity matches = %+v, want none (list entry is a person)", matches)
+	}
+	// Форма "SIA" не влияет на сходство названий
+	if matches := index.Match(Record{Value: "SIA Krastnieki A I Trading", Kind: models.ScreeningEntryEntity}, DefaultMinScore, screenedAt); len(matches) != 1 || matches[0].EntryID != 11 {
+		t.Errorf("company matches = %+v, want entry 11", matches)
+	}
+	// Сходство ниже порога
+	if matches := index.Match(Record{Value: "Roland Ozols", Kind: models.ScreeningEntryPerson}, DefaultMinScore, screenedAt); len(matches) != 0 {
+		t.Errorf("matches = %+v, want none below %v", matches, DefaultMinScore)
+	}
+}
@@ -0,0 +1,221 @@
+// screening/screen.go
+package screening
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"capital-view-api/models"
+	"capital-view-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// batchSize - размер пачки при чтении таблиц реестра и записи совпадений
+const batchSize = 1000
+
+// LoadDir загружает все файлы .csv и .xml из dir как списки (имя списка - имя файла). Список
+// с тем же именем заменяется, списки, файлов которых в каталоге больше нет, удаляются.
+// Файл, который не удалось разобрать, пропускается: его прежний список остается.
+func LoadDir(db *gorm.DB, dir string) (loaded int, err error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	present := make([]string, 0, len(files))
+	for _, file := range files {
+		extension := strings.ToLower(filepath.Ext(file.Name()))
+		if file.IsDir() || (extension != ".csv" && extension != ".xml") {
+			continue
+		}
+		present = append(present, file.Name())
+
+		path := filepath.Join(dir, file.Name())
+		entries, format, err := ParseFile(path)
+		if err != nil {
+			log.Printf("ERROR reading screening list %s, keeping the previously loaded version: %v", path, err)
+			continue
+		}
+		if err := saveList(db, file.Name(), CategoryForFile(path), format, entries); err != nil {
+			return loaded, fmt.Errorf("%s: %w", path, err)
+		}
+		log.Printf("Loaded screening list %s (%s): %d entries.", file.Name(), format, len(entries))
+		loaded++
+	}
+
+	// Списки удаленных файлов
+	var removed []models.ScreeningList
+	query := db.Model(&models.ScreeningList{})
+	if len(present) > 0 {
+		query = query.Where("name NOT IN ?", present)
+	}
+	if err := query.Find(&removed).Error; err != nil {
+		return loaded, err
+	}
+	for _, list := range removed {
+		if err := deleteList(db, list.ID); err != nil {
+			return loaded, err
+		}
+		log.Printf("Removed screening list %s: its file is no longer in %s.", list.Name, dir)
+	}
+	return loaded, nil
+}
+
+// saveList заменяет записи списка name в одной транзакции
+func saveList(db *gorm.DB, name, category, format string, entries []models.ScreeningEntry) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var list models.ScreeningList
+		if err := tx.Where("name = ?", name).Limit(1).Find(&list).Error; err != nil {
+			return err
+		}
+		if list.ID != 0 {
+			if err := deleteList(tx, list.ID); err != nil {
+				return err
+			}
+		}
+		list = models.ScreeningList{Name: name, Category: category, Format: format, Entries: len(entries), LoadedAt: time.Now()}
+		if err := tx.Create(&list).Error; err != nil {
+			return err
+		}
+		for i := range entries {
+			entries[i].ID = 0
+			entries[i].ListID = list.ID
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(entries, batchSize).Error
+	})
+}
+
+// deleteList удаляет список вместе с его записями и найденными по нему совпадениями
+func deleteList(db *gorm.DB, listID uint) error {
+	if err := db.Where("list_id = ?", listID).Delete(&models.ScreeningMatch{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("list_id = ?", listID).Delete(&models.ScreeningEntry{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(&models.ScreeningList{}, listID).Error
+}
+
+// Run проверяет названия компаний и имена участников, бенефициаров и должностных лиц по всем
+// загруженным спискам и заменяет ими таблицу screening_matches. runID - запуск импорта (0 - вне импорта).
+func Run(db *gorm.DB, runID uint) (int, error) {
+	var lists []models.ScreeningList
+	if err := db.Order("id").Find(&lists).Error; err != nil {
+		return 0, err
+	}
+	if len(lists) == 0 {
+		return 0, nil
+	}
+	var entries []models.ScreeningEntry
+	if err := db.Order("id").Find(&entries).Error; err != nil {
+		return 0, err
+	}
+	index := NewIndex(lists, entries)
+	screenedAt := time.Now()
+
+	var matches []models.ScreeningMatch
+	check := func(record Record) {
+		matches = append(matches, index.Match(record, DefaultMinScore, screenedAt)...)
+	}
+
+	var registers []models.Registers
+	err := db.Select("id", "regcode", "name").FindInBatches(&registers, batchSize, func(*gorm.DB, int) error {
+		for _, register := range registers {
+			check(Record{Entity: "registers", RecordID: register.ID, Regcode: utils.StringValue(register.Regcode),
+				Field: "name", Value: utils.StringValue(register.Name), Kind: models.ScreeningEntryEntity})
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var members []models.Member
+	err = db.Select("id", "at_legal_entity_registration_number", "entity_type", "name", "birth_date", "latvian_identity_number_masked").
+		FindInBatches(&members, batchSize, func(*gorm.DB, int) error {
+			for _, member := range members {
+				kind := models.ScreeningEntryEntity
+				if utils.StringValue(member.EntityType) == "NATURAL_PERSON" {
+					kind = models.ScreeningEntryPerson
+				}
+				check(Record{Entity: "members", RecordID: member.ID, Regcode: utils.StringValue(member.AtLegalEntityRegistrationNumber),
+					Field: "name", Value: utils.StringValue(member.Name), Kind: kind,
+					BirthDate: member.BirthDate, MaskedCode: member.LatvianIdentityNumberMasked})
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var owners []models.BeneficialOwner
+	err = db.Select("id", "legal_entity_registration_number", "forename", "surname", "birth_date", "latvian_identity_number_masked").
+		FindInBatches(&owners, batchSize, func(*gorm.DB, int) error {
+			for _, owner := range owners {
+				check(Record{Entity: "beneficial_owners", RecordID: owner.ID, Regcode: utils.StringValue(owner.LegalEntityRegistrationNumber),
+					Field: "forename surname", Value: joinNonEmpty(" ", utils.StringValue(owner.Forename), utils.StringValue(owner.Surname)),
+					Kind: models.ScreeningEntryPerson, BirthDate: owner.BirthDate, MaskedCode: owner.LatvianIdentityNumberMasked})
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var officers []models.Officer
+	err = db.Select("id", "at_legal_entity_registration_number", "entity_type", "name", "birth_date", "latvian_identity_number_masked").
+		FindInBatches(&officers, batchSize, func(*gorm.DB, int) error {
+			for _, officer := range officers {
+				kind := models.ScreeningEntryPerson
+				if entityType := utils.StringValue(officer.EntityType); entityType != "" && entityType != "NATURAL_PERSON" {
+					kind = models.ScreeningEntryEntity
+				}
+				check(Record{Entity: "officers", RecordID: officer.ID, Regcode: utils.StringValue(officer.AtLegalEntityRegistrationNumber),
+					Field: "name", Value: utils.StringValue(officer.Name), Kind: kind,
+					BirthDate: officer.BirthDate, MaskedCode: officer.LatvianIdentityNumberMasked})
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// Стабильный порядок записи: по компании, затем по убыванию сходства
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Regcode != matches[j].Regcode {
+			return matches[i].Regcode < matches[j].Regcode
+		}
+		return matches[i].Score > matches[j].Score
+	})
+	var run *uint
+	if runID != 0 {
+		run = &runID
+	}
+	for i := range matches {
+		matches[i].ImportRunID = run
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.ScreeningMatch{}).Error; err != nil {
+			return err
+		}
+		if len(matches) > 0 {
+			if err := tx.CreateInBatches(matches, batchSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.ScreeningList{}).Where("1 = 1").Update("screened_at", screenedAt).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(matches), nil
+}
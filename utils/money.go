@@ -0,0 +1,79 @@
+// utils/money.go
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// unitMultipliers - значения FinancialStatement.RoundedToNearest и соответствующие множители
+var unitMultipliers = map[string]float64{
+	"ONES":      1,
+	"TENS":      10,
+	"HUNDREDS":  100,
+	"THOUSANDS": 1000,
+	"MILLIONS":  1000000,
+}
+
+// UnitMultiplier возвращает множитель, приводящий суммы отчета к единицам валюты
+// ("THOUSANDS" -> 1000). Пустое значение считается "ONES". ok = false для неизвестных значений.
+func UnitMultiplier(roundedToNearest *string) (multiplier float64, ok bool) {
+	if roundedToNearest == nil || strings.TrimSpace(*roundedToNearest) == "" {
+		return 1, true
+	}
+	multiplier, ok = unitMultipliers[strings.ToUpper(strings.TrimSpace(*roundedToNearest))]
+	if !ok {
+		return 1, false
+	}
+	return multiplier, true
+}
+
+// LVLPerEUR - фиксированный курс перехода Латвии на евро (1 EUR = 0.702804 LVL)
+const LVLPerEUR = 0.702804
+
+// EURMultiplier возвращает множитель, приводящий суммы отчета к единицам евро:
+// учитывает RoundedToNearest и пересчитывает LVL в EUR по фиксированному курсу.
+// ok = false, если валюта или единицы не распознаны - такие суммы нельзя сравнивать с другими.
+func EURMultiplier(currency *string, roundedToNearest *string) (multiplier float64, ok bool) {
+	multiplier, ok = UnitMultiplier(roundedToNearest)
+	if !ok {
+		return 1, false
+	}
+	rate, ok := EURRate(currency)
+	if !ok {
+		return 1, false
+	}
+	return multiplier * rate, true
+}
+
+// EURRate возвращает курс пересчета суммы в валюте currency в евро (EUR -> 1, LVL -> 1/0.702804).
+// ok = false для пустой или неизвестной валюты.
+func EURRate(currency *string) (rate float64, ok bool) {
+	if currency == nil {
+		return 1, false
+	}
+	switch strings.ToUpper(strings.TrimSpace(*currency)) {
+	case "EUR":
+		return 1, true
+	case "LVL":
+		return 1 / LVLPerEUR, true
+	default:
+		return 1, false
+	}
+}
+
+// EURMultiplierSQL возвращает SQL-выражение множителя из EURMultiplier для колонок
+// currency и rounded_to_nearest - чтобы сравнивать суммы разных компаний прямо в запросе.
+// Нераспознанные валюта или единицы дают NULL: такие суммы не проходят ни одно условие.
+func EURMultiplierSQL(currencyColumn, roundedColumn string) string {
+	var units strings.Builder
+	units.WriteString("(CASE UPPER(TRIM(COALESCE(" + roundedColumn + ", ''))) WHEN '' THEN 1")
+	for _, name := range []string{"ONES", "TENS", "HUNDREDS", "THOUSANDS", "MILLIONS"} {
+		units.WriteString(" WHEN '" + name + "' THEN " + strconv.FormatFloat(unitMultipliers[name], 'f', -1, 64))
+	}
+	units.WriteString(" END)")
+
+	currency := "(CASE UPPER(TRIM(" + currencyColumn + ")) WHEN 'EUR' THEN 1.0 WHEN 'LVL' THEN " +
+		strconv.FormatFloat(1/LVLPerEUR, 'f', -1, 64) + " END)"
+	return units.String() + " * " + currency
+}
@@ -0,0 +1,60 @@
+// utils/normalize.go
+package utils
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// diacriticFold - замены для латышских (и соседних балтийских) букв с диакритикой.
+// Применяется после приведения к нижнему регистру, поэтому только строчные буквы.
+var diacriticFold = map[rune]rune{
+	// Латышский алфавит
+	'ā': 'a', 'č': 'c', 'ē': 'e', 'ģ': 'g', 'ī': 'i', 'ķ': 'k',
+	'ļ': 'l', 'ņ': 'n', 'š': 's', 'ū': 'u', 'ž': 'z',
+	// Старая орфография (встречается в названиях)
+	'ō': 'o', 'ŗ': 'r',
+	// Литовский и эстонский - часто встречаются в именах участников
+	'ą': 'a', 'ę': 'e', 'ė': 'e', 'į': 'i', 'ų': 'u',
+	'ä': 'a', 'ö': 'o', 'õ': 'o', 'ü': 'u',
+	// Прочие частые случаи
+	'á': 'a', 'é': 'e', 'í': 'i', 'ó': 'o', 'ú': 'u', 'ł': 'l', 'ń': 'n', 'ś': 's', 'ź': 'z', 'ż': 'z', 'ć': 'c',
+}
+
+// Fold приводит строку к нижнему регистру (включая не-ASCII, чего не делает LOWER() в SQLite)
+// и убирает диакритику: "LŪSIŅŠ" -> "lusins". Пунктуация сохраняется.
+func Fold(value string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if folded, ok := diacriticFold[r]; ok {
+			return folded
+		}
+		return r
+	}, value)
+}
+
+// FoldName нормализует имя или название для хранения в *_folded колонках и для поиска по ним:
+// Fold + все, что не буква и не цифра, заменяется пробелом, пробелы схлопываются.
+// `SIA "Rozentāle"` -> "sia rozentale".
+func FoldName(value string) string {
+	folded := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, Fold(value))
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+// PersonKey - ключ группировки строк одного лица: маскированный код + слова имени без учета
+// порядка ("Kadaks Didzis" в members и "Didzis Kadaks" в beneficial_owners дают один ключ).
+func PersonKey(name string, masked *string) string {
+	words := strings.Fields(FoldName(name))
+	sort.Strings(words)
+	key := "|" + strings.Join(words, " ")
+	if masked != nil {
+		key = *masked + key
+	}
+	return key
+}
@@ -0,0 +1,72 @@
+// utils/parse.go
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts - форматы дат, которые встречаются в CSV выгрузках регистра
+// ("30/12/2008", "30/12/2008 11:49", "2020-10-30 23:33:27.773") и в параметрах запросов.
+var dateLayouts = []string{
+	"02/01/2006",
+	"02/01/2006 15:04",
+	"02/01/2006 15:04:05",
+	"2006-01-02",
+	"2006-01-02 15:04:05", // Дробные секунды (.773) Go разбирает автоматически
+	time.RFC3339,
+}
+
+// ParseDate разбирает дату в любом из поддерживаемых форматов (в UTC).
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("неподдерживаемый формат даты '%s'", value)
+}
+
+// ParseInt разбирает целое число. Допускает запись вида "100.0",
+// если дробная часть равна нулю (так в CSV записаны количества акций).
+func ParseInt(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' не является целым числом", value)
+	}
+	if f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return 0, fmt.Errorf("'%s' не является целым числом", value)
+	}
+	return int64(f), nil
+}
+
+// ParseFloat разбирает десятичное число; запятая как разделитель тоже допускается.
+func ParseFloat(value string) (float64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", ".")
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' не является числом", value)
+	}
+	return f, nil
+}
+
+// IntPtr возвращает указатель на копию значения (для необязательных полей ответа)
+func IntPtr(value int) *int {
+	return &value
+}
+
+// StringValue возвращает значение указателя или пустую строку для nil
+func StringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,334 @@
+// webhooks/dispatch.go
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"capital-view-api/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MaxAttempts    = 6                // После шестой неудачной попытки доставка получает статус failed
+	BaseBackoff    = 30 * time.Second // Пауза после первой неудачи, дальше удваивается
+	MaxBackoff     = time.Hour
+	WorkerInterval = 30 * time.Second // Как часто сервер проверяет доставки, которым пора повторить попытку
+	ClaimLease     = 5 * time.Minute  // На сколько доставка закрепляется за процессом, который ее отправляет
+
+	deliverBatchSize = 100
+	maxErrorLength   = 500
+)
+
+// DefaultClient - HTTP клиент для отправки уведомлений
+var DefaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Backoff - пауза перед следующей попыткой после attempts неудачных: 30s, 1m, 2m, 4m, ... не больше MaxBackoff
+func Backoff(attempts int) time.Duration {
+	delay := BaseBackoff
+	for i := 1; i < attempts && delay < MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxBackoff {
+		delay = MaxBackoff
+	}
+	return delay
+}
+
+// Sign - значение заголовка X-Webhook-Signature: HMAC-SHA256 от "<timestamp>.<тело запроса>".
+// Получатель пересчитывает подпись своим секретом и сверяет X-Webhook-Timestamp, чтобы отбросить повторы.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret создает случайный секрет подписки
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Enqueue создает доставки по изменениям запуска импорта runID: по одной на каждую
+// активную подписку и компанию из watchlist, у которой есть интересные подписке события.
+// Повторный вызов для того же запуска ничего не делает.
+func Enqueue(database *gorm.DB, runID uint) (int, error) {
+	var existing int64
+	if err := database.Model(&models.WebhookDelivery{}).Where("import_run_id = ?", runID).Count(&existing).Error; err != nil {
+		return 0, err
+	}
+	if existing > 0 {
+		log.Printf("Webhook deliveries for import run %d already exist, skipping.", runID)
+		return 0, nil
+	}
+
+	var subscriptions []models.WebhookSubscription
+	if err := database.Where("active = ?", true).Order("id").Find(&subscriptions).Error; err != nil {
+		return 0, err
+	}
+	if len(subscriptions) == 0 {
+		return 0, nil
+	}
+
+	var changes []models.ChangeEvent
+	err := database.
+		Where("import_run_id = ? AND regcode IN (SELECT regcode FROM watchlist)", runID).
+		Order("id").
+		Find(&changes).Error
+	if err != nil {
+		return 0, err
+	}
+
+	// Изменения по компаниям, только интересующие подписчиков типы
+	itemsByCompany := make(map[string][]models.WebhookPayloadItem)
+	for _, change := range changes {
+		eventType := EventType(change)
+		if eventType == "" || change.Regcode == nil {
+			continue
+		}
+		itemsByCompany[*change.Regcode] = append(itemsByCompany[*change.Regcode], models.WebhookPayloadItem{
+			Type:     eventType,
+			ChangeID: change.ID,
+			Entity:   change.Entity,
+			EntityID: change.EntityID,
+			Field:    change.Field,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+	if len(itemsByCompany) == 0 {
+		return 0, nil
+	}
+	regcodes := make([]string, 0, len(itemsByCompany))
+	for regcode := range itemsByCompany {
+		regcodes = append(regcodes, regcode)
+	}
+	sort.Strings(regcodes)
+
+	var companies []models.Registers
+	if err := database.Select("regcode", "name").Where("regcode IN ?", regcodes).Find(&companies).Error; err != nil {
+		return 0, err
+	}
+	names := make(map[string]*string, len(companies))
+	for _, company := range companies {
+		if company.Regcode != nil {
+			names[*company.Regcode] = company.Name
+		}
+	}
+
+	now := time.Now().UTC()
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		for _, regcode := range regcodes {
+			payload := models.WebhookPayload{ImportRunID: runID, Regcode: regcode, CompanyName: names[regcode]}
+			var types []string
+			seen := make(map[string]bool)
+			for _, item := range itemsByCompany[regcode] {
+				if !subscribed(subscription, item.Type) {
+					continue
+				}
+				payload.Events = append(payload.Events, item)
+				if !seen[item.Type] {
+					seen[item.Type] = true
+					types = append(types, item.Type)
+				}
+			}
+			if len(payload.Events) == 0 {
+				continue
+			}
+			body, err := json.Marshal(payload)
+			if err != nil {
+				return 0, err
+			}
+			nextAttemptAt := now
+			deliveries = append(deliveries, models.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				ImportRunID:    runID,
+				Regcode:        regcode,
+				EventTypes:     strings.Join(types, ","),
+				Payload:        string(body),
+				Status:         models.WebhookDeliveryPending,
+				NextAttemptAt:  &nextAttemptAt,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	if err := database.CreateInBatches(deliveries, deliverBatchSize).Error; err != nil {
+		return 0, err
+	}
+	return len(deliveries), nil
+}
+
+// DeliverDue отправляет доставки в статусе pending, у которых наступило next_attempt_at.
+// Неудачная попытка переносится на Backoff(attempts), после MaxAttempts доставка получает статус failed.
+// DeliverDue вызывают и сервер (RunWorker), и импортер, поэтому каждая доставка перед отправкой
+// захватывается (claim): отправляет ее только тот процесс, который первым сдвинул next_attempt_at.
+func DeliverDue(database *gorm.DB, client *http.Client) (delivered, failed int, err error) {
+	var due []models.WebhookDelivery
+	err = database.
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, time.Now().UTC()).
+		Order("id").
+		Limit(deliverBatchSize).
+		Find(&due).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	subscriptions := make(map[uint]*models.WebhookSubscription)
+	for i := range due {
+		delivery := &due[i]
+		claimed, err := claim(database, delivery)
+		if err != nil {
+			return delivered, failed, err
+		}
+		if !claimed {
+			continue // Доставку уже отправляет другой процесс
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			var loaded models.WebhookSubscription
+			result := database.Where("id = ?", delivery.SubscriptionID).Limit(1).Find(&loaded)
+			if result.Error != nil {
+				return delivered, failed, result.Error
+			}
+			if result.RowsAffected > 0 {
+				subscription = &loaded
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if subscription == nil || !subscription.Active {
+			// Подписка удалена или отключена: повторять бессмысленно
+			message := "subscription is missing or inactive"
+			delivery.Status = models.WebhookDeliveryFailed
+			delivery.LastError = &message
+			delivery.NextAttemptAt = nil
+			if err := database.Save(delivery).Error; err != nil {
+				return delivered, failed, err
+			}
+			failed++
+			continue
+		}
+
+		now := time.Now().UTC()
+		statusCode, sendErr := send(client, subscription, delivery)
+		delivery.Attempts++
+		if statusCode != 0 {
+			delivery.LastStatusCode = &statusCode
+		}
+		if sendErr == nil {
+			delivery.Status = models.WebhookDeliveryDelivered
+			delivery.DeliveredAt = &now
+			delivery.NextAttemptAt = nil
+			delivery.LastError = nil
+			delivered++
+		} else {
+			message := sendErr.Error()
+			if len(message) > maxErrorLength {
+				message = message[:maxErrorLength]
+			}
+			delivery.LastError = &message
+			if delivery.Attempts >= MaxAttempts {
+				delivery.Status = models.WebhookDeliveryFailed
+				delivery.NextAttemptAt = nil
+				failed++
+			} else {
+				next := now.Add(Backoff(delivery.Attempts))
+				delivery.NextAttemptAt = &next
+			}
+			log.Printf("WARN: Webhook delivery %d to subscription %d failed (attempt %d): %v", delivery.ID, delivery.SubscriptionID, delivery.Attempts, sendErr)
+		}
+		if err := database.Save(delivery).Error; err != nil {
+			return delivered, failed, err
+		}
+	}
+	return delivered, failed, nil
+}
+
+// claim закрепляет доставку за процессом: условный UPDATE переносит next_attempt_at на ClaimLease
+// вперед, только если доставка все еще pending и ее время наступило. false - доставку уже взял
+// другой процесс. Если процесс упадет во время отправки, попытка повторится после ClaimLease.
+func claim(database *gorm.DB, delivery *models.WebhookDelivery) (bool, error) {
+	now := time.Now().UTC()
+	lease := now.Add(ClaimLease)
+	result := database.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", delivery.ID, models.WebhookDeliveryPending, now).
+		Update("next_attempt_at", lease)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return false, nil
+	}
+	delivery.NextAttemptAt = &lease
+	return true, nil
+}
+
+// send выполняет одну попытку доставки. Успех - ответ 2xx.
+func send(client *http.Client, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "capital-view-api-webhooks")
+	request.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set("X-Webhook-Event", delivery.EventTypes)
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", Sign(subscription.Secret, timestamp, body))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected response status %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// RunWorker периодически отправляет доставки, которым пора повторить попытку. Работает до отмены ctx.
+func RunWorker(ctx context.Context, database *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			delivered, failed, err := DeliverDue(database, DefaultClient)
+			if err != nil {
+				log.Printf("ERROR: Webhook delivery pass failed: %v", err)
+			} else if delivered > 0 || failed > 0 {
+				log.Printf("Webhooks: %d delivered, %d failed permanently.", delivered, failed)
+			}
+		}
+	}
+}
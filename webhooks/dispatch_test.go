@@ -0,0 +1,226 @@
+// webhooks/dispatch_test.go
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"capital-view-api/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testSecret = "test-secret"
+
+// newTestDB - временная база SQLite с подпиской на url и одной доставкой, которой пора уйти
+func newTestDB(t *testing.T, url string) (*gorm.DB, uint) {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "webhooks.db")), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := database.AutoMigrate(&models.WebhookSubscription{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatal(err)
+	}
+	subscription := models.WebhookSubscription{URL: url, Secret: testSecret, Active: true}
+	if err := database.Create(&subscription).Error; err != nil {
+		t.Fatal(err)
+	}
+	due := time.Now().UTC().Add(-time.Second)
+	delivery := models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		ImportRunID:    1,
+		Regcode:        "40003000000",
+		EventTypes:     models.WebhookEventMemberAdded,
+		Payload:        `{"import_run_id":1,"regcode":"40003000000","events":[]}`,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  &due,
+	}
+	if err := database.Create(&delivery).Error; err != nil {
+		t.Fatal(err)
+	}
+	return database, delivery.ID
+}
+
+func loadDelivery(t *testing.T, database *gorm.DB, id uint) models.WebhookDelivery {
+	t.Helper()
+	var delivery models.WebhookDelivery
+	if err := database.First(&delivery, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}
+
+// makeDue переносит следующую попытку в прошлое, чтобы не ждать Backoff
+func makeDue(t *testing.T, database *gorm.DB, id uint) {
+	t.Helper()
+	past := time.Now().UTC().Add(-time.Second)
+	if err := database.Model(&models.WebhookDelivery{}).Where("id = ?", id).Update("next_attempt_at", past).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeliverDueSignature(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		body, _ := io.ReadAll(r.Body)
+		// Проверка получателя: HMAC-SHA256 от "<timestamp>.<body>" общим секретом
+		mac := hmac.New(sha256.New, []byte(testSecret))
+		mac.Write([]byte(r.Header.Get("X-Webhook-Timestamp") + "." + string(body)))
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if r.Header.Get("X-Webhook-Timestamp") == "" || !hmac.Equal([]byte(r.Header.Get("X-Webhook-Signature")), []byte(want)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("X-Webhook-Event") != models.WebhookEventMemberAdded {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	database, id := newTestDB(t, server.URL)
+
+	delivered, failed, err := DeliverDue(database, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered != 1 || failed != 0 || hits != 1 {
+		t.Fatalf("delivered %d, failed %d, requests %d; want 1, 0, 1", delivered, failed, hits)
+	}
+	delivery := loadDelivery(t, database, id)
+	if delivery.Status != models.WebhookDeliveryDelivered || delivery.Attempts != 1 || delivery.NextAttemptAt != nil ||
+		delivery.LastStatusCode == nil || *delivery.LastStatusCode != http.StatusNoContent {
+		t.Errorf("delivery after success = %+v", delivery)
+	}
+
+	// Доставленное больше не отправляется
+	if delivered, _, _ := DeliverDue(database, server.Client()); delivered != 0 || hits != 1 {
+		t.Errorf("delivered delivery was sent again")
+	}
+}
+
+func TestDeliverDueRetryAfterServerError(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	database, id := newTestDB(t, server.URL)
+
+	before := time.Now().UTC()
+	if delivered, failed, err := DeliverDue(database, server.Client()); err != nil || delivered != 0 || failed != 0 {
+		t.Fatalf("first pass: delivered %d, failed %d, err %v; want 0, 0, nil", delivered, failed, err)
+	}
+	delivery := loadDelivery(t, database, id)
+	if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != 1 ||
+		delivery.LastStatusCode == nil || *delivery.LastStatusCode != http.StatusInternalServerError || delivery.LastError == nil {
+		t.Fatalf("delivery after 500 = %+v", delivery)
+	}
+	if delivery.NextAttemptAt == nil || delivery.NextAttemptAt.Before(before.Add(BaseBackoff)) {
+		t.Errorf("next attempt %v, want not earlier than %v", delivery.NextAttemptAt, before.Add(BaseBackoff))
+	}
+
+	// До наступления next_attempt_at повторной отправки нет
+	if _, _, err := DeliverDue(database, server.Client()); err != nil || hits != 1 {
+		t.Fatalf("retried before backoff: requests %d, err %v", hits, err)
+	}
+
+	makeDue(t, database, id)
+	if delivered, _, err := DeliverDue(database, server.Client()); err != nil || delivered != 1 {
+		t.Fatalf("retry: delivered %d, err %v; want 1", delivered, err)
+	}
+	delivery = loadDelivery(t, database, id)
+	if delivery.Status != models.WebhookDeliveryDelivered || delivery.Attempts != 2 || delivery.LastError != nil {
+		t.Errorf("delivery after retry = %+v", delivery)
+	}
+}
+
+func TestDeliverDueFailsAfterMaxAttempts(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	database, id := newTestDB(t, server.URL)
+
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		_, failed, err := DeliverDue(database, server.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		delivery := loadDelivery(t, database, id)
+		if delivery.Attempts != attempt {
+			t.Fatalf("attempts = %d, want %d", delivery.Attempts, attempt)
+		}
+		if attempt < MaxAttempts {
+			if failed != 0 || delivery.Status != models.WebhookDeliveryPending {
+				t.Fatalf("attempt %d: failed %d, status %s; want 0, pending", attempt, failed, delivery.Status)
+			}
+			makeDue(t, database, id)
+			continue
+		}
+		if failed != 1 || delivery.Status != models.WebhookDeliveryFailed || delivery.NextAttemptAt != nil {
+			t.Fatalf("attempt %d: failed %d, delivery %+v; want status failed without next attempt", attempt, failed, delivery)
+		}
+	}
+
+	if _, _, err := DeliverDue(database, server.Client()); err != nil || hits != MaxAttempts {
+		t.Errorf("requests = %d, err %v; want %d (no attempts after failed)", hits, err, MaxAttempts)
+	}
+}
+
+// TestDeliverDueClaim - второй процесс (здесь вложенный вызов DeliverDue во время отправки)
+// не должен отправить доставку, которую уже взял первый
+func TestDeliverDueClaim(t *testing.T) {
+	var hits, nestedDelivered int32
+	var database *gorm.DB
+	var client *http.Client
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			delivered, _, err := DeliverDue(database, client)
+			if err != nil {
+				t.Errorf("nested DeliverDue: %v", err)
+			}
+			atomic.AddInt32(&nestedDelivered, int32(delivered))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client = server.Client()
+	database, id := newTestDB(t, server.URL)
+
+	delivered, _, err := DeliverDue(database, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits != 1 || delivered != 1 || nestedDelivered != 0 {
+		t.Fatalf("requests %d, delivered %d, delivered by second process %d; want 1, 1, 0", hits, delivered, nestedDelivered)
+	}
+	if delivery := loadDelivery(t, database, id); delivery.Status != models.WebhookDeliveryDelivered || delivery.Attempts != 1 {
+		t.Errorf("delivery = %+v, want delivered after one attempt", delivery)
+	}
+}
@@ -0,0 +1,101 @@
+// webhooks/events.go
+// Пакет webhooks уведомляет подписчиков об изменениях компаний из watchlist:
+// после импорта события change_events превращаются в доставки (webhook_deliveries),
+// которые отправляются POST-запросами с HMAC-подписью и повторяются с нарастающей паузой.
+package webhooks
+
+import (
+	"fmt"
+	"strings"
+
+	"capital-view-api/models"
+)
+
+// EventTypes - все типы событий в порядке вывода
+var EventTypes = []string{
+	models.WebhookEventBeneficialOwnerAdded,
+	models.WebhookEventBeneficialOwnerRemoved,
+	models.WebhookEventMemberAdded,
+	models.WebhookEventMemberRemoved,
+	models.WebhookEventMemberSharesChanged,
+	models.WebhookEventFinancialStatementNew,
+	models.WebhookEventCompanyTerminated,
+}
+
+// shareColumns - поля members, изменение которых означает изменение доли участника
+var shareColumns = map[string]bool{
+	"number_of_shares":    true,
+	"share_nominal_value": true,
+	"share_currency":      true,
+}
+
+// EventType определяет тип уведомления для изменения ("" - изменение не интересно подписчикам)
+func EventType(change models.ChangeEvent) string {
+	field := ""
+	if change.Field != nil {
+		field = *change.Field
+	}
+	switch change.Entity {
+	case "beneficial_owners":
+		switch change.Operation {
+		case models.ChangeOperationInsert:
+			return models.WebhookEventBeneficialOwnerAdded
+		case models.ChangeOperationDelete:
+			return models.WebhookEventBeneficialOwnerRemoved
+		}
+	case "members":
+		switch change.Operation {
+		case models.ChangeOperationInsert:
+			return models.WebhookEventMemberAdded
+		case models.ChangeOperationDelete:
+			return models.WebhookEventMemberRemoved
+		case models.ChangeOperationUpdate:
+			if shareColumns[field] {
+				return models.WebhookEventMemberSharesChanged
+			}
+		}
+	case "financial_statements":
+		if change.Operation == models.ChangeOperationInsert {
+			return models.WebhookEventFinancialStatementNew
+		}
+	case "registers":
+		// Дата ликвидации появилась (closed меняется вместе с ней)
+		if change.Operation == models.ChangeOperationUpdate && field == "terminated" && change.NewValue != nil {
+			return models.WebhookEventCompanyTerminated
+		}
+	}
+	return ""
+}
+
+// ParseEventTypes разбирает список типов через запятую. Пустой список - все типы.
+func ParseEventTypes(value string) ([]string, error) {
+	known := make(map[string]bool, len(EventTypes))
+	for _, eventType := range EventTypes {
+		known[eventType] = true
+	}
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !known[part] {
+			return nil, fmt.Errorf("неизвестный тип события '%s' (допустимо: %s)", part, strings.Join(EventTypes, ", "))
+		}
+		result = append(result, part)
+	}
+	return result, nil
+}
+
+// subscribed - нужен ли подписке тип события
+func subscribed(subscription models.WebhookSubscription, eventType string) bool {
+	if subscription.EventTypes == nil || strings.TrimSpace(*subscription.EventTypes) == "" {
+		return true
+	}
+	for _, part := range strings.Split(*subscription.EventTypes, ",") {
+		if strings.TrimSpace(part) == eventType {
+			return true
+		}
+	}
+	return false
+}